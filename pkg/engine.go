@@ -2,6 +2,7 @@ package yaml
 
 import (
 	"fmt"
+	"os"
 
 	engine "github.com/k8s-manifest-kit/engine/pkg"
 )
@@ -20,7 +21,55 @@ import (
 //	)
 //	objects, _ := e.Render(ctx)
 func NewEngine(source Source, opts ...RendererOption) (*engine.Engine, error) {
-	renderer, err := New([]Source{source}, opts...)
+	return NewEngineFromSources([]Source{source}, opts...)
+}
+
+// DefaultDirPattern is the glob pattern used by NewEngineFromDir: all .yaml and .yml files,
+// recursively.
+const DefaultDirPattern = "**/*.{yaml,yml}"
+
+// NewEngineFromDir creates an Engine configured with a single YAML renderer reading all
+// .yaml and .yml files below a local directory, recursively (DefaultDirPattern).
+// Hidden files and directories (e.g. .git) are skipped; pass WithSkipHidden(false) to include them.
+// Options are applied after these defaults and can override them.
+//
+// Example:
+//
+//	e, _ := yaml.NewEngineFromDir("/path/to/manifests", yaml.WithSourceAnnotations(true))
+//	objects, _ := e.Render(ctx)
+func NewEngineFromDir(dir string, opts ...RendererOption) (*engine.Engine, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access manifest directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrNotDirectory, dir)
+	}
+
+	source := Source{
+		FS:   os.DirFS(dir),
+		Path: DefaultDirPattern,
+	}
+
+	return NewEngine(source, append([]RendererOption{WithSkipHidden(true)}, opts...)...)
+}
+
+// NewEngineFromSources creates an Engine configured with a single YAML renderer reading
+// from multiple sources. Objects are returned in source order.
+//
+// Example:
+//
+//	e, _ := yaml.NewEngineFromSources(
+//	    []yaml.Source{
+//	        {FS: os.DirFS("/path/to/base"), Path: "*.yaml"},
+//	        {FS: os.DirFS("/path/to/overrides"), Path: "*.yaml"},
+//	    },
+//	    yaml.WithSourceAnnotations(true),
+//	)
+//	objects, _ := e.Render(ctx)
+func NewEngineFromSources(sources []Source, opts ...RendererOption) (*engine.Engine, error) {
+	renderer, err := New(sources, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create yaml renderer: %w", err)
 	}
@@ -1,6 +1,8 @@
 package yaml_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"testing/fstest"
 
@@ -57,3 +59,95 @@ func TestNewEngine(t *testing.T) {
 		g.Expect(e).Should(BeNil())
 	})
 }
+
+func TestNewEngineFromSources(t *testing.T) {
+
+	t.Run("should create engine with multiple sources", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS1 := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+		testFS2 := fstest.MapFS{
+			"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		e, err := yaml.NewEngineFromSources([]yaml.Source{
+			{FS: testFS1, Path: "*.yaml"},
+			{FS: testFS2, Path: "*.yaml"},
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+	})
+
+	t.Run("should return error for invalid source", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		e, err := yaml.NewEngineFromSources([]yaml.Source{
+			{FS: testFS, Path: "*.yaml"},
+			{FS: nil, Path: "*.yaml"},
+		})
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("index 1"))
+		g.Expect(e).Should(BeNil())
+	})
+}
+
+func TestNewEngineFromDir(t *testing.T) {
+
+	t.Run("should render YAML files recursively", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := t.TempDir()
+
+		writeFile(t, filepath.Join(dir, "pod.yaml"), podYAML)
+		writeFile(t, filepath.Join(dir, "apps", "base", "configmap.yml"), configMapYAML)
+		writeFile(t, filepath.Join(dir, "apps", "README.md"), "# readme")
+		writeFile(t, filepath.Join(dir, ".git", "config.yaml"), configMapYAML)
+
+		e, err := yaml.NewEngineFromDir(dir)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+	})
+
+	t.Run("should return error for missing directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := yaml.NewEngineFromDir(filepath.Join(t.TempDir(), "missing"))
+		g.Expect(err).Should(MatchError(os.ErrNotExist))
+		g.Expect(e).Should(BeNil())
+	})
+
+	t.Run("should return error for regular file", func(t *testing.T) {
+		g := NewWithT(t)
+		file := filepath.Join(t.TempDir(), "pod.yaml")
+		writeFile(t, file, podYAML)
+
+		e, err := yaml.NewEngineFromDir(file)
+		g.Expect(err).Should(MatchError(yaml.ErrNotDirectory))
+		g.Expect(e).Should(BeNil())
+	})
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
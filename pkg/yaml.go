@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"io/fs"
-	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing/fstest"
+	"time"
 
-	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
 	"github.com/k8s-manifest-kit/engine/pkg/types"
 	"github.com/k8s-manifest-kit/pkg/util/cache"
 	"github.com/k8s-manifest-kit/pkg/util/k8s"
@@ -16,33 +20,114 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-const rendererType = "yaml"
+const (
+	// RendererType is the type identifier of the YAML renderer, returned by Name.
+	RendererType = "yaml"
+
+	// AnnotationSourceLabel is the annotation key for the label of the renderer instance that
+	// produced an object. It is added with the other source annotations when a label is set.
+	AnnotationSourceLabel = "manifests.k8s-manifests-lib/source.label"
+)
 
 var (
 	// ErrNoFilesMatched is returned when no files match the specified pattern.
-	ErrNoFilesMatched = errors.New("no files matched pattern")
+	ErrNoFilesMatched = newClassError(ErrNoMatch, "no files matched pattern")
 
 	// ErrPathIsDirectory is returned when a path is a directory instead of a file.
 	ErrPathIsDirectory = errors.New("path is a directory, not a file")
+
+	// ErrInvalidPattern is returned when a Source.Path glob pattern is malformed.
+	ErrInvalidPattern = errors.New("invalid glob pattern")
+
+	// ErrInvalidSource is returned when a Source configuration is invalid.
+	ErrInvalidSource = errors.New("invalid source")
+
+	// ErrUnexpectedObjectCount is returned when a source renders fewer or more objects than expected.
+	ErrUnexpectedObjectCount = newClassError(ErrValidation, "unexpected object count")
+
+	// ErrNotDirectory is returned when a directory is expected but the path is not a directory.
+	ErrNotDirectory = errors.New("path is not a directory")
+
+	// ErrBinaryContent is returned when a matched file is binary or not valid UTF-8 text.
+	ErrBinaryContent = newClassError(ErrParse, "file is binary or not valid UTF-8 text")
+
+	// ErrUnsupportedExtension is returned when a matched file does not have an allowed extension.
+	ErrUnsupportedExtension = newClassError(ErrValidation, "file extension is not allowed")
+
+	// ErrRenderInterrupted is returned when the render context is canceled or its deadline
+	// expires. The context error is wrapped as well, so errors.Is(err, context.Canceled) works.
+	ErrRenderInterrupted = errors.New("render interrupted")
 )
 
 // Source represents the input for a YAML rendering operation.
 type Source struct {
+	// Name optionally identifies the source, e.g. for Renderer.RemoveSource.
+	// Non-empty names must be unique within a renderer.
+	Name string
+
 	// FS is the filesystem containing YAML manifest files.
 	// Supports embedded filesystems via embed.FS or testing via fstest.MapFS.
 	FS fs.FS
 
 	// Path specifies the glob pattern to match YAML files.
 	// Only .yaml and .yml files are processed. Examples: "manifests/*.yaml", "**/*.yml"
+	// ${NAME} placeholders are resolved from the values passed to Process, e.g.
+	// "clusters/${CLUSTER}/**/*.yaml"; cache keys use the resolved path.
+	// For Objects sources, Path is an optional label used in error messages.
+	Path string
+
+	// Objects provides pre-built objects that are passed through without decoding.
+	// They flow through the same filters, transformers and validators as file-based objects
+	// and are deep-copied on every render. Mutually exclusive with FS.
+	Objects []unstructured.Unstructured
+
+	// ExpectAtLeast is the minimum number of objects this source must produce after
+	// filters and transformers. 0 = no minimum.
+	ExpectAtLeast int
+
+	// ExpectAtMost is the maximum number of objects this source may produce after
+	// filters and transformers. 0 = no maximum.
+	ExpectAtMost int
+
+	// Cache overrides the renderer cache policy for this source, e.g. to cache remote sources
+	// for longer or never cache local development directories. nil uses the renderer cache.
+	Cache *SourceCache
+}
+
+// SourceInfo describes a configured source as reported by Renderer.Sources.
+type SourceInfo struct {
+	// Name is the source name, if any.
+	Name string
+
+	// Path is the source glob pattern (or label, for Objects sources).
 	Path string
+
+	// Files lists the files resolved for the source by the last uncached render,
+	// or nil if the source has not been rendered yet or is an Objects source.
+	Files []string
 }
 
 // Renderer handles YAML file rendering operations.
 // It implements types.Renderer.
+//
+// A Renderer is safe for concurrent use: Process, AddSource, RemoveSource and Sources may be
+// called from multiple goroutines, and concurrent renders share the cache. Returned objects are
+// owned by the caller. User-supplied filters, transformers, validators and warning handlers
+// may be invoked concurrently and must be safe for concurrent use.
 type Renderer struct {
+	mu     sync.RWMutex
 	inputs []*sourceHolder
 	opts   RendererOptions
-	cache  cache.Interface[[]unstructured.Unstructured]
+	cache  cache.Interface[loadedSource]
+
+	// lastCount is the number of objects returned by the previous render, used to size results.
+	lastCount atomic.Int64
+
+	// digestFiles enables recording input file digests, needed for provenance.
+	digestFiles bool
+
+	// fingerprint is the options fingerprint, part of cache keys and provenance.
+	fingerprint string
 }
 
 // New creates a new YAML Renderer with the given inputs and options.
@@ -50,162 +135,713 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	rendererOpts := RendererOptions{
 		Filters:      make([]types.Filter, 0),
 		Transformers: make([]types.Transformer, 0),
+		Validators:   make([]Validator, 0),
+
+		BinaryFilePolicy:      PolicyError,
+		AmbiguousScalarPolicy: PolicyWarn,
+		LossyNumberPolicy:     PolicyWarn,
+		AllowedExtensions:     DefaultAllowedExtensions(),
+		Decoder:               YAMLv3Decoder{},
+		CacheDeepCopy:         true,
+		Redact:                true,
+		AllowAliases:          true,
+		AllowMergeKeys:        true,
+		SensitiveKinds:        DefaultSensitiveKinds(),
+		IgnoreFile:            DefaultIgnoreFile,
+		ChecksumFile:          DefaultChecksumFile,
+		RequireMatch:          true,
+		WindowsPaths:          runtime.GOOS == "windows",
 	}
 
 	for _, opt := range opts {
 		opt.ApplyTo(&rendererOpts)
 	}
 
+	rendererOpts.Transformers = orderTransformers(rendererOpts)
+
+	if rendererOpts.TargetSelector != "" {
+		f, err := MatchMetadata(rendererOpts.TargetSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target selector: %w", err)
+		}
+
+		// Targeting applies to objects as authored, before any other filter
+		rendererOpts.Filters = append([]types.Filter{f}, rendererOpts.Filters...)
+	}
+
+	if len(rendererOpts.DeniedKinds) > 0 {
+		// Denied kinds are checked before any other validator
+		rendererOpts.Validators = append([]Validator{DenyKinds(rendererOpts.DeniedKinds...)}, rendererOpts.Validators...)
+	}
+
+	if rendererOpts.DeprecatedAPIPolicy != PolicyIgnore {
+		v, err := DeprecatedAPIValidator(rendererOpts.KubernetesVersion, rendererOpts.DeprecatedAPIPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deprecated API check: %w", err)
+		}
+
+		rendererOpts.Validators = append(rendererOpts.Validators, v)
+	}
+
+	if rendererOpts.VersionSkewPolicy != PolicyIgnore {
+		if rendererOpts.KubernetesVersion == "" {
+			return nil, errors.New("invalid version skew check: a kubernetes version is required")
+		}
+
+		v, err := versionSkewValidator(
+			rendererOpts.KubernetesVersion,
+			rendererOpts.VersionSkewPolicy,
+			rendererOpts.DeprecatedAPIPolicy == PolicyIgnore,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version skew check: %w", err)
+		}
+
+		rendererOpts.Validators = append(rendererOpts.Validators, v)
+	}
+
+	if rendererOpts.SchemaBundles != nil {
+		bundles, err := LoadSchemaBundles(rendererOpts.SchemaBundles)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema bundles: %w", err)
+		}
+
+		bundle, err := SelectSchemaBundle(bundles, rendererOpts.KubernetesVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema bundles: %w", err)
+		}
+
+		rendererOpts.Validators = append(rendererOpts.Validators, SchemaValidator(bundle))
+	}
+
 	// Wrap sources in holders and validate
 	holders := make([]*sourceHolder, len(inputs))
 	for i := range inputs {
-		holders[i] = &sourceHolder{
-			Source: inputs[i],
-		}
-		if err := holders[i].Validate(); err != nil {
+		holder, err := newSourceHolder(inputs[i], rendererOpts)
+		if err != nil {
 			return nil, fmt.Errorf("invalid source at index %d: %w", i, err)
 		}
+		if holder.Name != "" && slices.ContainsFunc(holders[:i], func(h *sourceHolder) bool { return h.Name == holder.Name }) {
+			return nil, fmt.Errorf("invalid source at index %d: %w: duplicate name %q", i, ErrInvalidSource, holder.Name)
+		}
+
+		holders[i] = holder
+	}
+
+	fingerprint, err := optionsFingerprint(rendererOpts)
+	if err != nil {
+		return nil, err
 	}
 
 	r := &Renderer{
-		inputs: holders,
-		opts:   rendererOpts,
-		cache:  newCache(rendererOpts.CacheOptions),
+		inputs:      holders,
+		opts:        rendererOpts,
+		cache:       newCache(rendererOpts.CacheOptions, rendererOpts),
+		digestFiles: len(rendererOpts.ProvenanceHandlers) > 0,
+		fingerprint: fingerprint,
+	}
+
+	if r.digestFiles {
+		r.opts.PostRenderHooks = append(slices.Clip(r.opts.PostRenderHooks), r.provenanceHook)
 	}
 
 	return r, nil
 }
 
+// NewFromStrings creates a new YAML Renderer reading in-memory manifests.
+// Keys are slash-separated file names (e.g. "apps/pod.yaml") and values are YAML content;
+// as with file-based sources, only .yaml and .yml names are decoded.
+// Files are rendered in directory walk order: the entries of each directory are visited in
+// lexical order of their names, and a subdirectory is rendered in full at its position, so
+// "a/b.yaml" comes before "a-c.yaml" and "a.yaml".
+//
+// Example:
+//
+//	r, _ := yaml.NewFromStrings(map[string]string{"pod.yaml": podYAML})
+func NewFromStrings(files map[string]string, opts ...RendererOption) (*Renderer, error) {
+	fsys := make(fstest.MapFS, len(files))
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	return New([]Source{{FS: fsys, Path: "**/*"}}, opts...)
+}
+
 // Process executes the rendering logic for all configured inputs.
-// Render-time values are ignored by the YAML renderer as it does not support templates.
-func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
-	allObjects := make([]unstructured.Unstructured, 0)
+// Render-time values only resolve ${NAME} placeholders in source paths, as the YAML renderer
+// does not support templates.
+// With WithPartialResults, a render whose deadline expires returns the objects of the sources
+// rendered so far together with an *IncompleteRenderError.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	objects, _, err := r.process(ctx, values)
+
+	return objects, err
+}
 
-	for _, holder := range r.inputs {
-		objects, err := r.renderSingle(ctx, holder)
-		if err != nil {
-			return nil, fmt.Errorf("error rendering YAML pattern %s: %w", holder.Path, err)
+// ProcessBySource renders all configured inputs like Process, returning the objects grouped by
+// source. Sources are keyed by name, or by path if unnamed; objects of unnamed sources sharing a
+// path are grouped together. Sources that produce no objects map to an empty slice.
+func (r *Renderer) ProcessBySource(ctx context.Context, values map[string]any) (map[string][]unstructured.Unstructured, error) {
+	objects, report, err := r.process(ctx, values)
+	if objects == nil && err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]unstructured.Unstructured, len(report.Sources))
+
+	offset := 0
+	for _, source := range report.Sources {
+		key := source.Name
+		if key == "" {
+			key = source.Path
 		}
 
-		// Apply renderer-level filters and transformers per-source for better error context
-		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"error applying filters/transformers to YAML pattern %s: %w",
-				holder.Path,
-				err,
-			)
+		if _, ok := groups[key]; !ok {
+			groups[key] = make([]unstructured.Unstructured, 0, source.Objects)
+		}
+
+		groups[key] = append(groups[key], objects[offset:offset+source.Objects]...)
+		offset += source.Objects
+	}
+
+	return groups, err
+}
+
+// process runs the render hooks and renders all configured inputs.
+func (r *Renderer) process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, RenderReport, error) {
+	ctx = withRenderValues(ctx, values)
+	ctx = withWarningHandler(ctx, r.opts.WarningHandler)
+	ctx = withPipelineValues(ctx, r.opts.PipelineValues)
+
+	for _, hook := range r.opts.PreRenderHooks {
+		if err := hook(ctx); err != nil {
+			return nil, RenderReport{}, r.labelError(fmt.Errorf("pre-render hook failed: %w", err))
+		}
+	}
+
+	r.emit(ctx, Event{Type: EventRenderStarted})
+
+	start := time.Now()
+	objects, report, err := r.render(ctx)
+	report.Duration = time.Since(start)
+	report.Err = err
+
+	r.emit(ctx, Event{Type: EventRenderFinished, Objects: report.Objects, Duration: report.Duration, Err: err})
+
+	for _, hook := range r.opts.PostRenderHooks {
+		if hookErr := hook(ctx, objects, report); hookErr != nil {
+			err = errors.Join(err, fmt.Errorf("post-render hook failed: %w", hookErr))
+		}
+	}
+
+	if err != nil {
+		var incomplete *IncompleteRenderError
+		if errors.As(err, &incomplete) {
+			return objects, report, r.labelError(err)
 		}
 
-		allObjects = append(allObjects, transformed...)
+		return nil, report, r.labelError(err)
+	}
+
+	return objects, report, nil
+}
+
+// render renders all configured inputs and reports what was rendered.
+func (r *Renderer) render(ctx context.Context) ([]unstructured.Unstructured, RenderReport, error) {
+	// AddSource and RemoveSource never modify the slice in place, so a snapshot is enough
+	r.mu.RLock()
+	inputs := r.inputs
+	r.mu.RUnlock()
+
+	report := RenderReport{
+		Label:   r.opts.Label,
+		Sources: make([]SourceReport, 0, len(inputs)),
+	}
+
+	allObjects := make([]unstructured.Unstructured, 0, r.lastCount.Load())
+
+	results, failed := r.renderSources(ctx, inputs)
+
+	for i, result := range results {
+		if i == failed {
+			if r.opts.PartialResults && errors.Is(result.err, context.DeadlineExceeded) {
+				report.Objects = len(allObjects)
+
+				return allObjects, report, newIncompleteRenderError(inputs[i:], result.err)
+			}
+
+			return nil, report, result.err
+		}
+
+		report.Sources = append(report.Sources, result.report)
+		allObjects = append(allObjects, result.objects...)
+	}
+
+	r.lastCount.Store(int64(len(allObjects)))
+	report.Objects = len(allObjects)
+
+	return allObjects, report, nil
+}
+
+// renderSource renders a single source and runs it through the pipeline and validators.
+func (r *Renderer) renderSource(ctx context.Context, holder *sourceHolder) ([]unstructured.Unstructured, SourceReport, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, SourceReport{}, err
+	}
+
+	ctx, recorder := withFileTimings(ctx)
+
+	result, cached, err := r.renderSingle(ctx, holder)
+	if err != nil {
+		return nil, SourceReport{}, fmt.Errorf("error rendering YAML pattern %s: %w", holder.Path, err)
+	}
+
+	objects := result.objects
+	holder.setResolvedFiles(result.files)
+
+	injectFanOutNamespace(ctx, objects)
+
+	timings := recorder.list()
+
+	// Apply renderer-level filters and transformers per-source for better error context
+	transformed, err := r.applyPipelineByFile(ctx, objects, timings)
+	if err != nil {
+		return nil, SourceReport{}, fmt.Errorf(
+			"error applying filters/transformers to YAML pattern %s: %w",
+			holder.Path,
+			r.redactError(err, objects),
+		)
+	}
+
+	if i, err := applyValidators(ctx, transformed, r.opts.Validators); err != nil {
+		err = r.withValidationExcerpt(ctx, holder, result.files, transformed[i], r.redactError(err, transformed))
+
+		return nil, SourceReport{}, fmt.Errorf("error validating YAML pattern %s: %w", holder.Path, err)
+	}
+
+	if err := holder.checkCount(len(transformed)); err != nil {
+		return nil, SourceReport{}, fmt.Errorf("error validating YAML pattern %s: %w", holder.Path, err)
+	}
+
+	r.reportSlowFiles(ctx, timings)
+
+	for i := range transformed {
+		r.emit(ctx, Event{
+			Type:             EventObjectEmitted,
+			Source:           holder.Name,
+			Pattern:          holder.Path,
+			File:             sourceFile(transformed[i]),
+			GroupVersionKind: transformed[i].GroupVersionKind(),
+			Namespace:        transformed[i].GetNamespace(),
+			Name:             transformed[i].GetName(),
+		})
+	}
+
+	return transformed, SourceReport{
+		Name:    holder.Name,
+		Path:    holder.Path,
+		Files:   result.files,
+		Digests: result.digests,
+		Objects: len(transformed),
+		Cached:  cached,
+		Timings: timings,
+	}, nil
+}
+
+// AddSource validates and appends a source to the renderer.
+// It is safe to call concurrently with Process; renders already in progress keep
+// their source set, and cached results are retained.
+func (r *Renderer) AddSource(source Source) error {
+	holder, err := newSourceHolder(source, r.opts)
+	if err != nil {
+		return fmt.Errorf("invalid source: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if holder.Name != "" && slices.ContainsFunc(r.inputs, func(h *sourceHolder) bool { return h.Name == holder.Name }) {
+		return fmt.Errorf("invalid source: %w: duplicate name %q", ErrInvalidSource, holder.Name)
+	}
+
+	r.inputs = append(slices.Clip(r.inputs), holder)
+
+	return nil
+}
+
+// RemoveSource removes the source with the given name.
+// It reports whether a source was removed and is safe to call concurrently with Process.
+func (r *Renderer) RemoveSource(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.inputs, func(h *sourceHolder) bool { return h.Name == name })
+	if i < 0 {
+		return false
+	}
+
+	r.inputs[i].remove()
+	r.inputs = slices.Delete(slices.Clone(r.inputs), i, i+1)
+
+	return true
+}
+
+// Sources returns the renderer's configured sources in render order,
+// including the files each one resolved to in the last render.
+func (r *Renderer) Sources() []SourceInfo {
+	r.mu.RLock()
+	inputs := r.inputs
+	r.mu.RUnlock()
+
+	result := make([]SourceInfo, len(inputs))
+	for i, holder := range inputs {
+		result[i] = SourceInfo{
+			Name:  holder.Name,
+			Path:  holder.Path,
+			Files: holder.resolvedFiles(),
+		}
 	}
 
-	return allObjects, nil
+	return result
 }
 
-// Name returns the renderer type identifier.
+// Name returns the renderer type identifier (RendererType). It is the same for all instances;
+// use Label to tell instances apart.
 func (r *Renderer) Name() string {
-	return rendererType
+	return RendererType
+}
+
+// Label returns the instance label set with WithLabel, or an empty string.
+func (r *Renderer) Label() string {
+	return r.opts.Label
+}
+
+// labelError attributes an error to the renderer instance, if it has a label.
+func (r *Renderer) labelError(err error) error {
+	if r.opts.Label == "" {
+		return err
+	}
+
+	return fmt.Errorf("%s renderer %q: %w", RendererType, r.opts.Label, err)
+}
+
+// cacheFor returns the cache used for a source, or nil if it is not cached.
+func (r *Renderer) cacheFor(holder *sourceHolder) cache.Interface[loadedSource] {
+	if holder.Cache != nil {
+		return holder.cache
+	}
+
+	return r.cache
+}
+
+// checkContext returns the context error wrapped with ErrRenderInterrupted if ctx is done,
+// so renders stop between files and documents instead of walking the whole tree.
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrRenderInterrupted, err)
+	}
+
+	return nil
 }
 
 // renderSingle performs the rendering for a single YAML input.
-func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+// The returned flag reports whether the result was served from the cache.
+func (r *Renderer) renderSingle(ctx context.Context, holder *sourceHolder) (loadedSource, bool, error) {
+	// Passthrough sources need no decoding, so they bypass the cache
+	if holder.Objects != nil {
+		return loadedSource{objects: k8s.DeepCloneUnstructuredSlice(holder.Objects)}, false, nil
+	}
+
+	pattern, err := resolvePath(ctx, holder.Path)
+	if err != nil {
+		return loadedSource{}, false, err
+	}
+
 	spec := YAMLSpec{
-		Path: holder.Path,
+		Path:    pattern,
+		Source:  holder.id,
+		Options: r.fingerprint,
 	}
 
-	// Check cache (if enabled)
-	if r.cache != nil {
+	c := r.cacheFor(holder)
+
+	// Check cache (if enabled); refreshes skip the lookup to replace the entry
+	if c != nil && !isCacheRefresh(ctx) {
 		// ensure objects are evicted
-		r.cache.Sync()
+		c.Sync()
+
+		if cached, found := c.Get(spec); found {
+			return cached, true, nil
+		}
+
+		if stale, ok := r.revalidate(ctx, holder, spec); ok {
+			return stale, true, nil
+		}
+	}
 
-		if cached, found := r.cache.Get(spec); found {
-			return cached, nil
+	result, err := r.loadSource(ctx, holder, pattern)
+	if err != nil {
+		if stale, ok := r.staleResult(ctx, holder, spec, err); ok {
+			return stale, true, nil
 		}
+
+		return loadedSource{}, false, err
+	}
+
+	holder.lastCount.Store(int64(len(result.objects)))
+
+	// Cache result (if enabled)
+	if c != nil {
+		c.Set(spec, result)
+		r.keepStale(c, holder, spec, result)
 	}
 
-	result := make([]unstructured.Unstructured, 0)
+	return result, false, nil
+}
 
+// loadSource reads and decodes the files matching the resolved pattern of a source.
+func (r *Renderer) loadSource(ctx context.Context, holder *sourceHolder, pattern string) (loadedSource, error) {
 	// Find all matching files
-	matches, err := fs.Glob(holder.FS, holder.Path)
+	matches, err := r.matchFiles(ctx, holder, pattern)
+	if err != nil {
+		return loadedSource{}, err
+	}
+
+	result := loadedSource{files: matches}
+	if r.digestFiles {
+		result.digests = make(map[string]string, len(matches))
+	}
+
+	sums, err := loadChecksums(r.retryingFS(ctx, holder.FS), r.opts.ChecksumFile, r.opts.RequireChecksums)
 	if err != nil {
-		return nil, fmt.Errorf("failed to match pattern %s: %w", holder.Path, err)
+		return loadedSource{}, err
 	}
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("%w: %s", ErrNoFilesMatched, holder.Path)
+		if r.opts.RequireMatch {
+			return loadedSource{}, fmt.Errorf("%w: %s", ErrNoFilesMatched, pattern)
+		}
+
+		ReportWarning(ctx, Warning{Rule: "no-match", Message: fmt.Sprintf("%s: %s", ErrNoFilesMatched, pattern)})
 	}
 
+	if err := checkContext(ctx); err != nil {
+		return loadedSource{}, err
+	}
+
+	if len(matches) == 1 {
+		// Single-file fast path: use the decoded objects without an intermediate copy
+		start := time.Now()
+
+		objects, err := r.loadYAMLFile(ctx, holder, sums, result.digests, matches[0])
+		if err != nil {
+			return loadedSource{}, &fileError{file: matches[0], err: err}
+		}
+
+		recordDecodeTime(ctx, matches[0], time.Since(start), len(objects))
+
+		result.objects = objects
+
+		return result, nil
+	}
+
+	result.objects = make([]unstructured.Unstructured, 0, holder.lastCount.Load())
+
 	// Process each matched file
 	for _, match := range matches {
-		fileObjects, err := r.loadYAMLFile(holder.FS, match)
+		if err := checkContext(ctx); err != nil {
+			return loadedSource{}, err
+		}
+
+		start := time.Now()
+
+		fileObjects, err := r.loadYAMLFile(ctx, holder, sums, result.digests, match)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load %s: %w", match, err)
+			return loadedSource{}, &fileError{file: match, err: err}
 		}
 
-		result = append(result, fileObjects...)
-	}
+		recordDecodeTime(ctx, match, time.Since(start), len(fileObjects))
 
-	// Cache result (if enabled)
-	if r.cache != nil {
-		r.cache.Set(spec, result)
+		result.objects = append(result.objects, fileObjects...)
 	}
 
 	return result, nil
 }
 
-// loadYAMLFile loads and parses a single YAML file.
-func (r *Renderer) loadYAMLFile(fsys fs.FS, path string) ([]unstructured.Unstructured, error) {
+// loadYAMLFile loads and parses a single YAML file, recording its content digest in digests
+// unless digests is nil.
+func (r *Renderer) loadYAMLFile(
+	ctx context.Context,
+	holder *sourceHolder,
+	sums checksums,
+	digests map[string]string,
+	path string,
+) ([]unstructured.Unstructured, error) {
+	fsys := holder.FS
+
+	r.emit(ctx, Event{Type: EventFileMatched, Source: holder.Name, Pattern: holder.Path, File: path})
+
 	// Check if path is a directory
-	info, err := fs.Stat(fsys, path)
+	var info fs.FileInfo
+
+	err := r.retry(ctx, path, func() error {
+		var err error
+		info, err = fs.Stat(fsys, path)
+
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		return nil, classify(ErrSourceUnavailable, fmt.Errorf("failed to stat %s: %w", path, err))
 	}
 
 	if info.IsDir() {
 		return nil, fmt.Errorf("%w: %s", ErrPathIsDirectory, path)
 	}
 
-	// Skip non-YAML files
-	ext := filepath.Ext(path)
-	if ext != ".yaml" && ext != ".yml" {
+	// Skip or reject files without an allowed extension
+	if !r.allowedExtension(path) {
+		switch r.opts.ExtensionPolicy {
+		case PolicyError:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedExtension, path)
+		case PolicyWarn:
+			ReportWarning(ctx, Warning{File: path, Rule: "unsupported-extension", Message: "skipped file without an allowed extension"})
+		case PolicyIgnore:
+		}
+
 		return nil, nil
 	}
 
-	// Read file
-	file, err := fsys.Open(path)
+	if err := r.checkFileSize(info.Size()); err != nil {
+		return nil, err
+	}
+
+	// Content is only referenced until decoding completes, so its buffer can be reused
+	var content []byte
+	var release func()
+
+	err = r.retry(ctx, path, func() error {
+		var err error
+		content, release, err = r.readFile(fsys, path, info.Size())
+
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
-	defer func() {
-		_ = file.Close()
-	}()
+	defer release()
+
+	// The reported size may be missing or wrong, so check what was actually read
+	if err := r.checkFileSize(int64(len(content))); err != nil {
+		return nil, err
+	}
+
+	if digests != nil || sums != nil {
+		digest := contentDigest(content)
+
+		// Verify before decoding, so tampered content is never parsed
+		if err := sums.verify(path, digest); err != nil {
+			return nil, err
+		}
+
+		if digests != nil {
+			digests[path] = digest
+		}
+	}
+
+	if isBinary(content) {
+		switch r.opts.BinaryFilePolicy {
+		case PolicyError:
+			return nil, fmt.Errorf("%w: %s", ErrBinaryContent, path)
+		case PolicyWarn:
+			ReportWarning(ctx, Warning{File: path, Rule: "binary-file", Message: "skipped binary or non-UTF-8 file"})
+		case PolicyIgnore:
+		}
 
-	content, err := io.ReadAll(file)
+		return nil, nil
+	}
+
+	if err := r.checkStructure(content); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkEmptyDocuments(ctx, path, content); err != nil {
+		return nil, r.withExcerpt(err, path, content)
+	}
+
+	resolved, err := r.resolveScalars(ctx, path, content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, r.withExcerpt(err, path, content)
 	}
 
 	// Decode YAML content
-	objects, err := k8s.DecodeYAML(content)
+	objects, err := r.decodeDocuments(ctx, resolved)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		return nil, r.withExcerpt(r.redactDecodeError(err, resolved), path, resolved)
+	}
+
+	if r.opts.IgnoreAnnotation {
+		objects = dropIgnored(objects)
+	}
+
+	if r.opts.Originals != nil {
+		file := strconv.FormatUint(holder.id, 10) + ":" + path
+		if err := r.opts.Originals.record(file, content); err != nil {
+			return nil, fmt.Errorf("failed to record original YAML: %w", err)
+		}
 	}
 
 	// Add source annotations if enabled
 	if r.opts.SourceAnnotations {
+		file := trimSourcePrefix(path, r.opts.SourcePrefix)
+
 		for i := range objects {
 			annotations := objects[i].GetAnnotations()
 			if annotations == nil {
 				annotations = make(map[string]string)
 			}
 
-			annotations[types.AnnotationSourceType] = rendererType
-			annotations[types.AnnotationSourceFile] = path
+			annotations[types.AnnotationSourceType] = RendererType
+			annotations[types.AnnotationSourceFile] = file
+
+			if r.opts.Label != "" {
+				annotations[AnnotationSourceLabel] = r.opts.Label
+			}
 
 			objects[i].SetAnnotations(annotations)
 		}
 	}
 
+	if len(r.opts.DocumentHooks) > 0 {
+		info := DocumentInfo{Source: holder.Name, Pattern: holder.Path, File: path}
+		if err := applyDocumentHooks(ctx, info, objects, r.opts.DocumentHooks); err != nil {
+			return nil, r.redactError(err, objects)
+		}
+	}
+
+	r.emit(ctx, Event{Type: EventFileParsed, Source: holder.Name, Pattern: holder.Path, File: path, Objects: len(objects)})
+
 	return objects, nil
 }
+
+// readFile reads a file into a pooled buffer. The returned release function must be called
+// once the content is no longer referenced.
+func (r *Renderer) readFile(fsys fs.FS, path string, sizeHint int64) ([]byte, func(), error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, nil, classify(ErrSourceUnavailable, fmt.Errorf("failed to open file: %w", err))
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	content, release, err := readPooled(r.limitReader(file), sizeHint)
+	if err != nil {
+		return nil, nil, classify(ErrSourceUnavailable, fmt.Errorf("failed to read file: %w", err))
+	}
+
+	return content, release, nil
+}
@@ -0,0 +1,75 @@
+package yaml
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ApplySetPartOfLabel is the label marking an object as a member of a kubectl ApplySet.
+	ApplySetPartOfLabel = "applyset.kubernetes.io/part-of"
+
+	// ApplySetIDLabel is the label identifying the parent object of a kubectl ApplySet.
+	ApplySetIDLabel = "applyset.kubernetes.io/id"
+)
+
+// ApplySetParent identifies the parent object of a kubectl ApplySet, which tracks the members of
+// the set so appliers can prune objects removed between renders. Cluster-scoped parents have an
+// empty namespace.
+type ApplySetParent struct {
+	GroupKind schema.GroupKind
+	Name      string
+	Namespace string
+}
+
+// ID returns the ApplySet ID of the parent as computed by kubectl:
+// "applyset-" + base64url(sha256("<name>.<namespace>.<kind>.<group>")) + "-v1".
+func (p ApplySetParent) ID() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{p.Name, p.Namespace, p.GroupKind.Kind, p.GroupKind.Group}, ".")))
+
+	return fmt.Sprintf("applyset-%s-v1", base64.RawURLEncoding.EncodeToString(sum[:]))
+}
+
+// ApplySet returns a transformer that labels objects as members of the ApplySet of parent, so
+// `kubectl apply --prune --applyset` (or any applier following the ApplySet specification) can
+// prune objects removed from the source tree. If the parent object itself is rendered, it is
+// labeled with the ApplySet ID instead. For appliers of other tools, use the engine's labels.Set
+// transformer with their prune label.
+//
+// Example:
+//
+//	yaml.WithStagedTransformer(yaml.StageFinalize, yaml.ApplySet(yaml.ApplySetParent{
+//		GroupKind: schema.GroupKind{Kind: "Secret"},
+//		Name:      "my-app",
+//		Namespace: "default",
+//	}))
+func ApplySet(parent ApplySetParent) types.Transformer {
+	id := parent.ID()
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		key := ApplySetPartOfLabel
+		if obj.GroupVersionKind().GroupKind() == parent.GroupKind &&
+			obj.GetName() == parent.Name &&
+			obj.GetNamespace() == parent.Namespace {
+			key = ApplySetIDLabel
+		}
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+
+		labels[key] = id
+		obj.SetLabels(labels)
+
+		return obj, nil
+	}
+}
@@ -0,0 +1,65 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const applySetParentYAML = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-app
+  namespace: default
+`
+
+func TestApplySet(t *testing.T) {
+	parent := yaml.ApplySetParent{
+		GroupKind: schema.GroupKind{Kind: "Secret"},
+		Name:      "my-app",
+		Namespace: "default",
+	}
+
+	t.Run("should compute the kubectl ApplySet ID", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// sha256("my-app.default.Secret.") encoded as unpadded base64url
+		g.Expect(parent.ID()).To(Equal("applyset-iCeuHqlt3ov6n803ZrPFVTjduUezM6ZFQZ3bDz9dLBo-v1"))
+	})
+
+	t.Run("should label members and the parent", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml":    &fstest.MapFile{Data: []byte(podYAML)},
+			"parent.yaml": &fstest.MapFile{Data: []byte(applySetParentYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformer(yaml.ApplySet(parent)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			switch obj.GetKind() {
+			case "Secret":
+				g.Expect(obj.GetLabels()).To(Equal(map[string]string{yaml.ApplySetIDLabel: parent.ID()}))
+			default:
+				g.Expect(obj.GetLabels()).To(And(
+					HaveKeyWithValue(yaml.ApplySetPartOfLabel, parent.ID()),
+					HaveKeyWithValue("app", "test-app"),
+				))
+			}
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AuditRecord is a single audit log entry describing an emitted object.
+type AuditRecord struct {
+	// Time is when the render finished.
+	Time time.Time `json:"time"`
+
+	// Renderer is the renderer type ("yaml").
+	Renderer string `json:"renderer"`
+
+	// Label is the label of the renderer instance, if set with WithLabel.
+	Label string `json:"label,omitempty"`
+
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// Source is the name of the source that produced the object, if any.
+	Source string `json:"source,omitempty"`
+
+	// Pattern is the pattern (or label) of the source that produced the object.
+	Pattern string `json:"pattern"`
+
+	// File is the source file annotation of the object, if source annotations are enabled.
+	File string `json:"file,omitempty"`
+
+	// Digest is the SHA-256 digest of the object's canonical JSON encoding ("sha256:<hex>").
+	Digest string `json:"digest"`
+}
+
+// AuditLog returns a post-render hook appending one JSON line (an AuditRecord) per emitted
+// object to w. The records of a render are written with a single Write call; concurrent
+// renders sharing the hook are serialized. Failed renders are not recorded.
+func AuditLog(w io.Writer) PostRenderHook {
+	var mu sync.Mutex
+
+	return func(_ context.Context, objects []unstructured.Unstructured, report RenderReport) error {
+		if report.Err != nil {
+			return nil
+		}
+
+		records, err := auditRecords(objects, report, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+
+		enc := json.NewEncoder(&buf)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return fmt.Errorf("failed to encode audit record: %w", err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// auditRecords builds the audit records of a render. Objects are in source order,
+// so the per-source object counts of the report attribute each object to its source.
+func auditRecords(objects []unstructured.Unstructured, report RenderReport, now time.Time) ([]AuditRecord, error) {
+	records := make([]AuditRecord, 0, len(objects))
+
+	i := 0
+	for _, source := range report.Sources {
+		for range source.Objects {
+			if i >= len(objects) {
+				break
+			}
+
+			obj := objects[i]
+			i++
+
+			digest, err := objectDigest(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			records = append(records, AuditRecord{
+				Time:       now,
+				Renderer:   RendererType,
+				Label:      report.Label,
+				APIVersion: obj.GetAPIVersion(),
+				Kind:       obj.GetKind(),
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+				Source:     source.Name,
+				Pattern:    source.Path,
+				File:       sourceFile(obj),
+				Digest:     digest,
+			})
+		}
+	}
+
+	return records, nil
+}
+
+// objectDigest returns the SHA-256 digest of an object's canonical JSON encoding.
+func objectDigest(obj unstructured.Unstructured) (string, error) {
+	data, err := CanonicalJSON(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s %s: %w", obj.GroupVersionKind(), obj.GetName(), err)
+	}
+
+	return contentDigest(data), nil
+}
+
+// contentDigest returns the SHA-256 digest of data as "sha256:<hex>".
+func contentDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
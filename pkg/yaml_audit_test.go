@@ -0,0 +1,99 @@
+package yaml_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAuditLog(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should append one record per emitted object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var log bytes.Buffer
+
+		renderer, err := yaml.New(
+			[]yaml.Source{
+				{Name: "pods", FS: testFS, Path: "pod.yaml"},
+				{FS: testFS, Path: "multi-doc.yaml"},
+			},
+			yaml.WithSourceAnnotations(true),
+			yaml.WithAuditLog(&log),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		records := readAuditRecords(g, &log)
+		g.Expect(records).To(HaveLen(3))
+
+		g.Expect(records[0].Renderer).To(Equal("yaml"))
+		g.Expect(records[0].APIVersion).To(Equal("v1"))
+		g.Expect(records[0].Kind).To(Equal("Pod"))
+		g.Expect(records[0].Name).To(Equal("test-pod"))
+		g.Expect(records[0].Source).To(Equal("pods"))
+		g.Expect(records[0].Pattern).To(Equal("pod.yaml"))
+		g.Expect(records[0].File).To(Equal("pod.yaml"))
+		g.Expect(records[0].Time).ToNot(BeZero())
+
+		g.Expect(records[1].Kind).To(Equal("Service"))
+		g.Expect(records[1].Pattern).To(Equal("multi-doc.yaml"))
+		g.Expect(records[2].Kind).To(Equal("Secret"))
+
+		data, err := yaml.CanonicalJSON(objects[0])
+		g.Expect(err).ToNot(HaveOccurred())
+		sum := sha256.Sum256(data)
+		g.Expect(records[0].Digest).To(Equal("sha256:" + hex.EncodeToString(sum[:])))
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(readAuditRecords(g, &log)).To(HaveLen(3))
+	})
+
+	t.Run("should not record failed renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var log bytes.Buffer
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "missing.yaml"}},
+			yaml.WithAuditLog(&log),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(log.Len()).To(BeZero())
+	})
+}
+
+// readAuditRecords consumes all audit records written to buf so far.
+func readAuditRecords(g Gomega, buf *bytes.Buffer) []yaml.AuditRecord {
+	records := make([]yaml.AuditRecord, 0)
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record yaml.AuditRecord
+		g.Expect(json.Unmarshal(scanner.Bytes(), &record)).To(Succeed())
+
+		records = append(records, record)
+	}
+
+	g.Expect(scanner.Err()).ToNot(HaveOccurred())
+
+	return records
+}
@@ -0,0 +1,37 @@
+package yaml
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Decoder is the backend converting YAML streams to unstructured objects. The renderer uses
+// YAMLv3Decoder unless another one is configured with WithDecoder. This module ships no other
+// backend; Decoder is the extension point for consumers providing their own.
+//
+// Checks running before decoding (structure limits, empty documents, scalar resolution, number
+// precision) always use gopkg.in/yaml.v3, so only the conversion to objects is replaced. Backends
+// should pass yamltest.DecoderConformance to produce the same objects as YAMLv3Decoder.
+type Decoder interface {
+	// Decode returns the objects of the documents in content, in order. Content has no byte
+	// order mark and uses LF line endings. Empty documents and documents without apiVersion or
+	// kind are skipped; integers are returned as int64 and other numbers as float64.
+	Decode(content []byte) ([]unstructured.Unstructured, error)
+}
+
+// DecoderFunc adapts a function to a Decoder.
+type DecoderFunc func(content []byte) ([]unstructured.Unstructured, error)
+
+// Decode calls f(content).
+func (f DecoderFunc) Decode(content []byte) ([]unstructured.Unstructured, error) {
+	return f(content)
+}
+
+// YAMLv3Decoder is the default Decoder, using k8s.DecodeYAML, based on gopkg.in/yaml.v3.
+type YAMLv3Decoder struct{}
+
+// Decode decodes the documents of content.
+func (YAMLv3Decoder) Decode(content []byte) ([]unstructured.Unstructured, error) {
+	return k8s.DecodeYAML(content)
+}
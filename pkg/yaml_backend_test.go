@@ -0,0 +1,54 @@
+package yaml_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Run("should decode with the configured backend", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+		decoder := yaml.DecoderFunc(func(content []byte) ([]unstructured.Unstructured, error) {
+			calls++
+
+			return yaml.YAMLv3Decoder{}.Decode(content)
+		})
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"multi.yaml": multiDocYAML, "pod.yaml": podYAML},
+			yaml.WithDecoder(decoder),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(calls).To(Equal(2))
+	})
+
+	t.Run("should classify backend errors as parse errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errBackend := errors.New("backend failure")
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"pod.yaml": podYAML},
+			yaml.WithDecoder(yaml.DecoderFunc(func([]byte) ([]unstructured.Unstructured, error) {
+				return nil, errBackend
+			})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errBackend))
+		g.Expect(err).To(MatchError(yaml.ErrParse))
+	})
+}
@@ -1,36 +1,203 @@
 package yaml
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
 	"github.com/k8s-manifest-kit/pkg/util/cache"
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+const (
+	// CacheEntryFormat identifies serialized cache entries produced by MarshalCacheEntry.
+	CacheEntryFormat = "k8s-manifest-kit.renderer-yaml/cache-entry"
+
+	// CacheEntryVersion is the schema version of serialized cache entries. It is incremented
+	// on incompatible changes; entries with another version are rejected as incompatible.
+	CacheEntryVersion = 1
+)
+
+// ErrIncompatibleCacheEntry is returned when a serialized cache entry has an unknown format or
+// schema version, or is corrupt. Callers should treat it as a cache miss.
+var ErrIncompatibleCacheEntry = errors.New("incompatible cache entry")
+
+// cacheEntry is the serialized form of a cache entry.
+type cacheEntry struct {
+	Format          string            `json:"format"`
+	Version         int               `json:"version"`
+	RendererVersion string            `json:"rendererVersion,omitempty"`
+	Objects         []json.RawMessage `json:"objects"`
+}
+
 // YAMLSpec contains the data used to generate cache keys for rendered YAML files.
 //
 //nolint:revive // Name matches pattern from other renderers (KustomizationSpec, TemplateSpec, ChartSpec)
 type YAMLSpec struct {
 	Path string
+
+	// Source identifies the source within the process, so that sources with the same path in
+	// different file systems do not share entries.
+	Source uint64
+
+	// Options is a fingerprint of the renderer options, so that renderers configured differently
+	// do not share entries when they share a cache or key function.
+	Options string
+}
+
+// loadedSource is the result of reading a source: the decoded objects, the files they were read
+// from and, with provenance enabled, the content digests of those files. Caches store results
+// whole, so reports and provenance of cached renders describe the read that produced the objects.
+type loadedSource struct {
+	objects []unstructured.Unstructured
+	files   []string
+	digests map[string]string
 }
 
-// newCache creates a cache instance with YAML-specific default KeyFunc.
-func newCache(opts *cache.Options) cache.Interface[[]unstructured.Unstructured] {
+// clone returns a deep copy of the result.
+func (s loadedSource) clone() loadedSource {
+	return loadedSource{
+		objects: k8s.DeepCloneUnstructuredSlice(s.objects),
+		files:   slices.Clone(s.files),
+		digests: maps.Clone(s.digests),
+	}
+}
+
+// cloningCache deep copies results on store and on every hit, like cache.NewRenderCache.
+type cloningCache struct {
+	entries cache.Interface[loadedSource]
+}
+
+// Get returns a copy of the result stored for key.
+func (c *cloningCache) Get(key any) (loadedSource, bool) {
+	result, found := c.entries.Get(key)
+	if !found {
+		return loadedSource{}, false
+	}
+
+	return result.clone(), true
+}
+
+// Set stores a copy of value for key.
+func (c *cloningCache) Set(key any, value loadedSource) {
+	c.entries.Set(key, value.clone())
+}
+
+// Sync removes expired entries.
+func (c *cloningCache) Sync() {
+	c.entries.Sync()
+}
+
+// SourceCache is the cache policy of a single source, overriding the renderer cache.
+type SourceCache struct {
+	// Disabled never caches the source, even if the renderer has a cache.
+	Disabled bool
+
+	// TTL is how long results of the source are cached, in a cache of its own. It applies
+	// even if the renderer has no cache. 0 uses the renderer cache TTL, or the default
+	// TTL of 5 minutes.
+	TTL time.Duration
+}
+
+// newCache creates a cache instance with YAML-specific default KeyFunc, honoring the deep copy,
+// compression and size cap settings of the renderer options. Compressed caches store encoded
+// entries and always return fresh objects, regardless of CacheDeepCopy.
+func newCache(opts *cache.Options, ro RendererOptions) cache.Interface[loadedSource] {
 	if opts == nil {
 		return nil
 	}
 
 	co := *opts
 
-	// Inject path-only KeyFunc as default for YAML
+	// Inject source, path and options KeyFunc as default for YAML
 	if co.KeyFunc == nil {
 		co.KeyFunc = func(key any) string {
 			if spec, ok := key.(YAMLSpec); ok {
-				return spec.Path
+				return spec.Options + ":" + strconv.FormatUint(spec.Source, 10) + ":" + spec.Path
 			}
 
 			return cache.DefaultKeyFunc(key)
 		}
 	}
 
-	return cache.NewRenderCache(co)
+	switch {
+	case ro.CacheCompression && ro.CacheMaxBytes > 0:
+		return &compressedCache{entries: newSizedCache(co, ro.CacheMaxBytes, compressedEntry.size, nil)}
+	case ro.CacheCompression:
+		return &compressedCache{entries: cache.New[compressedEntry](co)}
+	case ro.CacheMaxBytes > 0 && ro.CacheDeepCopy:
+		return newSizedCache(co, ro.CacheMaxBytes, estimateResultSize, loadedSource.clone)
+	case ro.CacheMaxBytes > 0:
+		return newSizedCache(co, ro.CacheMaxBytes, estimateResultSize, nil)
+	case !ro.CacheDeepCopy:
+		return cache.New[loadedSource](co)
+	default:
+		return &cloningCache{entries: cache.New[loadedSource](co)}
+	}
+}
+
+// MarshalCacheEntry encodes rendered objects as a versioned JSON cache entry, for disk or
+// distributed caches shared across processes and renderer versions. Integers keep their
+// int64 precision through a round trip.
+func MarshalCacheEntry(objects []unstructured.Unstructured) ([]byte, error) {
+	entry := cacheEntry{
+		Format:          CacheEntryFormat,
+		Version:         CacheEntryVersion,
+		RendererVersion: rendererVersion(),
+		Objects:         make([]json.RawMessage, 0, len(objects)),
+	}
+
+	for i := range objects {
+		data, err := objects[i].MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s %s: %w", objects[i].GroupVersionKind(), objects[i].GetName(), err)
+		}
+
+		entry.Objects = append(entry.Objects, data)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalCacheEntry decodes a cache entry encoded by MarshalCacheEntry. Entries with another
+// format or schema version, or that cannot be decoded, fail with ErrIncompatibleCacheEntry so
+// they can be discarded and re-rendered.
+func UnmarshalCacheEntry(data []byte) ([]unstructured.Unstructured, error) {
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIncompatibleCacheEntry, err)
+	}
+
+	if entry.Format != CacheEntryFormat {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrIncompatibleCacheEntry, entry.Format)
+	}
+
+	if entry.Version != CacheEntryVersion {
+		return nil, fmt.Errorf(
+			"%w: schema version %d, expected %d",
+			ErrIncompatibleCacheEntry,
+			entry.Version,
+			CacheEntryVersion,
+		)
+	}
+
+	objects := make([]unstructured.Unstructured, len(entry.Objects))
+	for i, raw := range entry.Objects {
+		if err := objects[i].UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("%w: object %d: %w", ErrIncompatibleCacheEntry, i, err)
+		}
+	}
+
+	return objects, nil
 }
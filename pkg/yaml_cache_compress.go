@@ -0,0 +1,118 @@
+package yaml
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// compressedCache stores render results as gzip-compressed cache entries (see MarshalCacheEntry)
+// and decodes them on every hit. Decoding yields fresh objects, so no deep copies are needed.
+type compressedCache struct {
+	entries cache.Interface[compressedEntry]
+}
+
+// compressedEntry is a compressed source result.
+type compressedEntry struct {
+	data    []byte
+	files   []string
+	digests map[string]string
+}
+
+// size returns the size of the compressed data plus the estimated size of the file list.
+func (e compressedEntry) size() int64 {
+	return int64(len(e.data)) + estimateFilesSize(e.files, e.digests)
+}
+
+// Get decompresses and decodes the entry stored for key. Entries that fail to decode are
+// reported as misses, so the source is rendered again.
+func (c *compressedCache) Get(key any) (loadedSource, bool) {
+	entry, found := c.entries.Get(key)
+	if !found {
+		return loadedSource{}, false
+	}
+
+	objects, err := decompressCacheEntry(entry.data)
+	if err != nil {
+		return loadedSource{}, false
+	}
+
+	return loadedSource{objects: objects, files: slices.Clone(entry.files), digests: maps.Clone(entry.digests)}, true
+}
+
+// Set compresses and stores value for key. Values that cannot be encoded are not cached.
+func (c *compressedCache) Set(key any, value loadedSource) {
+	data, err := compressCacheEntry(value.objects)
+	if err != nil {
+		return
+	}
+
+	c.entries.Set(key, compressedEntry{
+		data:    data,
+		files:   slices.Clone(value.files),
+		digests: maps.Clone(value.digests),
+	})
+}
+
+// Sync removes expired entries.
+func (c *compressedCache) Sync() {
+	c.entries.Sync()
+}
+
+// reserve accounts bytes held outside the cache if the entries have a size cap.
+func (c *compressedCache) reserve(size int64) bool {
+	if budget, ok := c.entries.(byteBudget); ok {
+		return budget.reserve(size)
+	}
+
+	return true
+}
+
+// release returns bytes accounted by reserve.
+func (c *compressedCache) release(size int64) {
+	if budget, ok := c.entries.(byteBudget); ok {
+		budget.release(size)
+	}
+}
+
+// compressCacheEntry encodes objects with MarshalCacheEntry and gzip-compresses the result.
+func compressCacheEntry(objects []unstructured.Unstructured) ([]byte, error) {
+	data, err := MarshalCacheEntry(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompressCacheEntry reverses compressCacheEntry.
+func decompressCacheEntry(data []byte) ([]unstructured.Unstructured, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalCacheEntry(raw)
+}
@@ -0,0 +1,245 @@
+package yaml
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaultCacheTTL mirrors the default TTL of cache.New.
+const defaultCacheTTL = 5 * time.Minute
+
+// byteBudget is implemented by caches with a size cap, so that results retained outside the
+// cache count toward the cap.
+type byteBudget interface {
+	reserve(size int64) bool
+	release(size int64)
+}
+
+// sizedEntry is an entry of sizedCache.
+type sizedEntry[T any] struct {
+	key        string
+	value      T
+	size       int64
+	expiration time.Time
+}
+
+// sizedCache is a TTL cache that tracks the estimated size of its entries and evicts the least
+// recently used ones when their total, plus the bytes reserved outside the cache, exceeds
+// maxBytes.
+type sizedCache[T any] struct {
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	bytes    int64
+	reserved int64
+	maxBytes int64
+	ttl      time.Duration
+	keyFunc  func(any) string
+	sizeOf   func(T) int64
+	clone    func(T) T
+}
+
+// newSizedCache creates a sizedCache. clone, if set, copies values on store and on every hit.
+func newSizedCache[T any](opts cache.Options, maxBytes int64, sizeOf func(T) int64, clone func(T) T) *sizedCache[T] {
+	if opts.TTL <= 0 {
+		opts.TTL = defaultCacheTTL
+	}
+
+	if opts.KeyFunc == nil {
+		opts.KeyFunc = cache.DefaultKeyFunc
+	}
+
+	if clone == nil {
+		clone = func(v T) T { return v }
+	}
+
+	return &sizedCache[T]{
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		maxBytes: maxBytes,
+		ttl:      opts.TTL,
+		keyFunc:  opts.KeyFunc,
+		sizeOf:   sizeOf,
+		clone:    clone,
+	}
+}
+
+// Get returns the unexpired value stored for key, marking it as recently used.
+func (c *sizedCache[T]) Get(key any) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero T
+
+	elem, ok := c.entries[c.keyFunc(key)]
+	if !ok {
+		return zero, false
+	}
+
+	entry := entryOf[T](elem)
+	if time.Now().After(entry.expiration) {
+		return zero, false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	return c.clone(entry.value), true
+}
+
+// Set stores value for key, evicting the least recently used entries until the cache fits
+// maxBytes. Values larger than the bytes not reserved are not stored.
+func (c *sizedCache[T]) Set(key any, value T) {
+	size := c.sizeOf(value)
+	value = c.clone(value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	strKey := c.keyFunc(key)
+	if elem, ok := c.entries[strKey]; ok {
+		c.remove(elem)
+	}
+
+	if c.reserved+size > c.maxBytes {
+		return
+	}
+
+	c.evict(size)
+
+	c.entries[strKey] = c.lru.PushFront(&sizedEntry[T]{
+		key:        strKey,
+		value:      value,
+		size:       size,
+		expiration: time.Now().Add(c.ttl),
+	})
+	c.bytes += size
+}
+
+// reserve accounts size bytes held outside the cache, e.g. retained stale results, evicting the
+// least recently used entries until everything fits maxBytes. It reports false, reserving
+// nothing, if the reserved bytes alone would exceed maxBytes.
+func (c *sizedCache[T]) reserve(size int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reserved+size > c.maxBytes {
+		return false
+	}
+
+	c.evict(size)
+	c.reserved += size
+	c.bytes += size
+
+	return true
+}
+
+// release returns bytes accounted by reserve.
+func (c *sizedCache[T]) release(size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reserved -= size
+	c.bytes -= size
+}
+
+// evict removes the least recently used entries until size more bytes fit maxBytes; the caller
+// holds the lock.
+func (c *sizedCache[T]) evict(size int64) {
+	for c.bytes+size > c.maxBytes && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Sync removes all expired entries.
+func (c *sizedCache[T]) Sync() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if now.After(entryOf[T](elem).expiration) {
+			c.remove(elem)
+		}
+
+		elem = next
+	}
+}
+
+// remove deletes an entry; the caller holds the lock.
+func (c *sizedCache[T]) remove(elem *list.Element) {
+	entry := entryOf[T](elem)
+	c.lru.Remove(elem)
+
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+// entryOf returns the entry held by an element of the LRU list.
+func entryOf[T any](elem *list.Element) *sizedEntry[T] {
+	return elem.Value.(*sizedEntry[T]) //nolint:forcetypeassert // the list only holds entries
+}
+
+// estimateResultSize approximates the memory held by a source result: its objects plus the
+// names and digests of its files.
+func estimateResultSize(result loadedSource) int64 {
+	return estimateObjectsSize(result.objects) + estimateFilesSize(result.files, result.digests)
+}
+
+// estimateFilesSize approximates the memory held by file names and digests.
+func estimateFilesSize(files []string, digests map[string]string) int64 {
+	size := int64(0)
+	for _, file := range files {
+		size += estimateValueSize(file)
+	}
+
+	for file, digest := range digests {
+		size += estimateValueSize(file) + estimateValueSize(digest)
+	}
+
+	return size
+}
+
+// estimateObjectsSize approximates the memory held by decoded objects: string contents plus a
+// fixed overhead per value, map entry and slice element. It is meant for relative accounting,
+// not as an exact measure.
+func estimateObjectsSize(objects []unstructured.Unstructured) int64 {
+	size := int64(0)
+	for i := range objects {
+		size += estimateValueSize(objects[i].Object)
+	}
+
+	return size
+}
+
+// estimateValueSize approximates the memory held by a decoded YAML value.
+func estimateValueSize(v any) int64 {
+	const overhead = 16
+
+	switch value := v.(type) {
+	case string:
+		return overhead + int64(len(value))
+	case map[string]any:
+		size := int64(3 * overhead)
+		for k, child := range value {
+			size += overhead + int64(len(k)) + estimateValueSize(child)
+		}
+
+		return size
+	case []any:
+		size := int64(2 * overhead)
+		for _, child := range value {
+			size += estimateValueSize(child)
+		}
+
+		return size
+	default:
+		return overhead
+	}
+}
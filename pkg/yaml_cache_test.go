@@ -0,0 +1,215 @@
+package yaml_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCacheEntry(t *testing.T) {
+	testFS := fstest.MapFS{
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"numbers.yaml":   &fstest.MapFile{Data: []byte(largeNumberYAML)},
+	}
+
+	t.Run("should round-trip rendered objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		data, err := yaml.MarshalCacheEntry(objects)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		decoded, err := yaml.UnmarshalCacheEntry(data)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(decoded).To(Equal(objects))
+	})
+
+	t.Run("should reject entries with another schema version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := json.Marshal(map[string]any{
+			"format":  yaml.CacheEntryFormat,
+			"version": yaml.CacheEntryVersion + 1,
+			"objects": []any{},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = yaml.UnmarshalCacheEntry(data)
+		g.Expect(err).To(MatchError(yaml.ErrIncompatibleCacheEntry))
+	})
+
+	t.Run("should reject entries with another format", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.UnmarshalCacheEntry([]byte(`{"format":"other","version":1,"objects":[]}`))
+		g.Expect(err).To(MatchError(yaml.ErrIncompatibleCacheEntry))
+	})
+
+	t.Run("should reject corrupt entries", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.UnmarshalCacheEntry([]byte(`{"format":`))
+		g.Expect(err).To(MatchError(yaml.ErrIncompatibleCacheEntry))
+	})
+}
+
+func TestSourceCache(t *testing.T) {
+	render := func(t *testing.T, source yaml.Source, opts ...yaml.RendererOption) func() []string {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{source}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		return func() []string {
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			names := make([]string, len(objects))
+			for i := range objects {
+				names[i] = objects[i].GetName()
+			}
+
+			return names
+		}
+	}
+
+	t.Run("should not cache disabled sources", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		process := render(t, yaml.Source{FS: testFS, Path: "*.yaml", Cache: &yaml.SourceCache{Disabled: true}}, yaml.WithCache())
+		g.Expect(process()).To(ConsistOf("test-pod"))
+
+		testFS["pod.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+		g.Expect(process()).To(ConsistOf("test-config"))
+	})
+
+	t.Run("should cache sources of a renderer without cache", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		process := render(t, yaml.Source{FS: testFS, Path: "*.yaml", Cache: &yaml.SourceCache{TTL: time.Hour}})
+		g.Expect(process()).To(ConsistOf("test-pod"))
+
+		testFS["pod.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+		g.Expect(process()).To(ConsistOf("test-pod"))
+	})
+
+	t.Run("should expire sources after their own TTL", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		process := render(
+			t,
+			yaml.Source{FS: testFS, Path: "*.yaml", Cache: &yaml.SourceCache{TTL: time.Millisecond}},
+			yaml.WithCache(cache.WithTTL(time.Hour)),
+		)
+		g.Expect(process()).To(ConsistOf("test-pod"))
+
+		testFS["pod.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+		g.Eventually(process).Should(ConsistOf("test-config"))
+	})
+
+	t.Run("should reject negative TTLs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{{
+			FS:    fstest.MapFS{},
+			Path:  "*.yaml",
+			Cache: &yaml.SourceCache{TTL: -time.Second},
+		}})
+		g.Expect(err).To(MatchError(yaml.ErrInvalidSource))
+	})
+}
+
+func TestCacheMaxBytes(t *testing.T) {
+	// a payload of scattered numbers, so the entry stays large when compressed
+	var payload strings.Builder
+	for i := range 16 * 1024 {
+		fmt.Fprintf(&payload, "%d,", i*7919%100003)
+	}
+
+	largeConfigMapYAML := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: large-config
+data:
+  payload: "` + payload.String() + `"
+`
+
+	for _, compressed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("should only cache results within the cap (compressed: %t)", compressed), func(t *testing.T) {
+			g := NewWithT(t)
+			smallFS := fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+			largeFS := fstest.MapFS{"config.yaml": &fstest.MapFile{Data: []byte(largeConfigMapYAML)}}
+
+			limit := int64(32 * 1024)
+			if compressed {
+				limit = 4 * 1024
+			}
+
+			renderer, err := yaml.New(
+				[]yaml.Source{{FS: smallFS, Path: "pod.yaml"}, {FS: largeFS, Path: "config.yaml"}},
+				yaml.WithCache(),
+				yaml.WithCacheCompression(compressed),
+				yaml.WithCacheMaxBytes(limit),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			smallFS["pod.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+			largeFS["config.yaml"] = &fstest.MapFile{Data: []byte(podYAML)}
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(2))
+			g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+			g.Expect(objects[1].GetName()).To(Equal("test-pod"))
+		})
+	}
+
+	t.Run("should evict least recently used entries", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"first.yaml":  &fstest.MapFile{Data: []byte(largeConfigMapYAML)},
+			"second.yaml": &fstest.MapFile{Data: []byte(largeConfigMapYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "first.yaml"}, {FS: testFS, Path: "second.yaml"}},
+			yaml.WithCache(),
+			yaml.WithCacheMaxBytes(int64(payload.Len())*3/2),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// The cap holds a single result, so storing the second evicted the first
+		testFS["first.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("test-config"))
+		g.Expect(objects[1].GetName()).To(Equal("large-config"))
+	})
+}
@@ -0,0 +1,106 @@
+package yaml
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// DefaultChecksumFile is the name of the checksum manifest honored at the root of a source filesystem.
+const DefaultChecksumFile = "SHA256SUMS"
+
+var (
+	// ErrChecksumMismatch is returned when a matched file does not match its listed digest.
+	ErrChecksumMismatch = newClassError(ErrValidation, "checksum mismatch")
+
+	// ErrMissingChecksum is returned when a matched file is not listed in the checksum manifest,
+	// or when checksums are required and the manifest is missing.
+	ErrMissingChecksum = newClassError(ErrValidation, "missing checksum")
+
+	// ErrInvalidChecksumFile is returned when the checksum manifest cannot be parsed.
+	ErrInvalidChecksumFile = newClassError(ErrParse, "invalid checksum file")
+)
+
+// checksums maps slash-separated file paths to lowercase hex SHA-256 digests.
+// A nil map disables verification.
+type checksums map[string]string
+
+// loadChecksums reads and parses the checksum manifest at the root of fsys.
+// A missing manifest disables verification unless required is set.
+func loadChecksums(fsys fs.FS, name string, required bool) (checksums, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			if required {
+				return nil, fmt.Errorf("%w: %s not found", ErrMissingChecksum, name)
+			}
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read checksum file %s: %w", name, err)
+	}
+
+	sums, err := parseChecksums(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return sums, nil
+}
+
+// parseChecksums parses sha256sum output: "<hex digest>  <path>" per line, where the path may be
+// prefixed with "*" (binary mode). Blank lines and lines starting with # are ignored.
+func parseChecksums(content []byte) (checksums, error) {
+	sums := make(checksums)
+
+	scanner := bufio.NewScanner(bytes.NewReader(normalizeYAML(content)))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		digest, file, ok := strings.Cut(text, " ")
+		file = strings.TrimPrefix(strings.TrimLeft(file, " "), "*")
+
+		if _, err := hex.DecodeString(digest); !ok || err != nil || len(digest) != 64 || file == "" {
+			return nil, fmt.Errorf("%w: line %d: expected \"<sha256>  <path>\"", ErrInvalidChecksumFile, line)
+		}
+
+		sums[path.Clean(file)] = strings.ToLower(digest)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidChecksumFile, err)
+	}
+
+	return sums, nil
+}
+
+// verify checks a file's content digest ("sha256:<hex>") against the manifest.
+func (c checksums) verify(file string, digest string) error {
+	if c == nil {
+		return nil
+	}
+
+	expected, ok := c[file]
+	if !ok {
+		return fmt.Errorf("%w: %s is not listed", ErrMissingChecksum, file)
+	}
+
+	if actual := strings.TrimPrefix(digest, "sha256:"); actual != expected {
+		return fmt.Errorf("%w: %s: expected %s, got %s", ErrChecksumMismatch, file, expected, actual)
+	}
+
+	return nil
+}
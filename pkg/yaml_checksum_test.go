@@ -0,0 +1,112 @@
+package yaml_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestChecksumVerification(t *testing.T) {
+	sums := fmt.Sprintf("# generated\n%s  pod.yaml\n%s *manifests/configmap.yaml\n",
+		sha256Hex(podYAML),
+		sha256Hex(configMapYAML),
+	)
+
+	t.Run("should render files matching their checksums", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"SHA256SUMS":               &fstest.MapFile{Data: []byte(sums)},
+			"pod.yaml":                 &fstest.MapFile{Data: []byte(podYAML)},
+			"manifests/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "**/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should fail on tampered files", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"SHA256SUMS": &fstest.MapFile{Data: []byte(sums)},
+			"pod.yaml":   &fstest.MapFile{Data: []byte(podYAML + "\n# tampered\n")},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrChecksumMismatch))
+	})
+
+	t.Run("should fail on files missing from the manifest", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"SHA256SUMS":     &fstest.MapFile{Data: []byte(sums)},
+			"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+			"injected.yaml":  &fstest.MapFile{Data: []byte(configMapYAML)},
+			"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "injected.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrMissingChecksum))
+	})
+
+	t.Run("should skip verification without a manifest unless required", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err = yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithRequireChecksums(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrMissingChecksum))
+	})
+
+	t.Run("should reject malformed manifests", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"CHECKSUMS": &fstest.MapFile{Data: []byte("not-a-digest pod.yaml\n")},
+			"pod.yaml":  &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithChecksumFile("CHECKSUMS"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrInvalidChecksumFile))
+		g.Expect(err.Error()).To(ContainSubstring("line 1"))
+	})
+}
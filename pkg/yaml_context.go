@@ -0,0 +1,51 @@
+package yaml
+
+import (
+	"context"
+)
+
+// ContextKey is a typed key for values passed to filters, transformers, validators and hooks
+// through the render context with WithPipelineContext. Keys are compared by identity, so two keys
+// created with the same name are distinct.
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey creates a typed context key. The name is only used for debugging.
+//
+// Example:
+//
+//	var TenantKey = yaml.NewContextKey[string]("tenant")
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return &ContextKey[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *ContextKey[T]) String() string {
+	return k.name
+}
+
+// PipelineValue returns the value stored for key in the render context, and whether one was set.
+//
+// Example:
+//
+//	func tenantLabel(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+//		if tenant, ok := yaml.PipelineValue(ctx, TenantKey); ok {
+//			obj.SetLabels(map[string]string{"tenant": tenant})
+//		}
+//		return obj, nil
+//	}
+func PipelineValue[T any](ctx context.Context, key *ContextKey[T]) (T, bool) {
+	value, ok := ctx.Value(key).(T)
+
+	return value, ok
+}
+
+// withPipelineValues returns a context carrying the given pipeline values.
+func withPipelineValues(ctx context.Context, values map[any]any) context.Context {
+	for key, value := range values {
+		ctx = context.WithValue(ctx, key, value)
+	}
+
+	return ctx
+}
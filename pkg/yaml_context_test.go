@@ -0,0 +1,110 @@
+package yaml_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+var (
+	tenantKey   = yaml.NewContextKey[string]("tenant")
+	replicasKey = yaml.NewContextKey[int]("replicas")
+)
+
+func TestPipelineContext(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	tenantLabel := func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		tenant, ok := yaml.PipelineValue(ctx, tenantKey)
+		if !ok {
+			tenant = "none"
+		}
+
+		obj.SetLabels(map[string]string{"tenant": tenant})
+
+		return obj, nil
+	}
+
+	t.Run("should pass values to transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithPipelineContext(tenantKey, "team-a"),
+			yaml.WithPipelineContext(replicasKey, 3),
+			yaml.WithTransformer(tenantLabel),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("tenant", "team-a"))
+	})
+
+	t.Run("should report missing values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithTransformer(tenantLabel),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("tenant", "none"))
+	})
+
+	t.Run("should keep keys with the same name distinct", func(t *testing.T) {
+		g := NewWithT(t)
+		other := yaml.NewContextKey[string]("tenant")
+
+		var got []string
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithPipelineContext(tenantKey, "team-a"),
+			yaml.WithPipelineContext(other, "team-b"),
+			yaml.WithPreRenderHook(func(ctx context.Context) error {
+				a, _ := yaml.PipelineValue(ctx, tenantKey)
+				b, _ := yaml.PipelineValue(ctx, other)
+				got = append(got, a, b)
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal([]string{"team-a", "team-b"}))
+	})
+
+	t.Run("should merge struct-based options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var opts yaml.RendererOptions
+		yaml.WithPipelineContext(tenantKey, "team-a").ApplyTo(&opts)
+		yaml.WithPipelineContext(replicasKey, 3).ApplyTo(&opts)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			opts,
+			yaml.WithTransformer(tenantLabel),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("tenant", "team-a"))
+	})
+}
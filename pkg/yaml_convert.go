@@ -0,0 +1,79 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConvertVersions returns a transformer that upgrades objects to the given target group versions
+// using the conversion functions registered in scheme (e.g. extensions/v1beta1 Deployment to apps/v1).
+//
+// For each object, the first target group version that the scheme recognizes for the object's kind
+// is used. Objects whose kind is unknown to the scheme, or that have no registered target version,
+// are returned unchanged.
+func ConvertVersions(scheme *runtime.Scheme, targets ...schema.GroupVersion) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		gvk := obj.GroupVersionKind()
+		if !scheme.Recognizes(gvk) {
+			return obj, nil
+		}
+
+		for _, gv := range targets {
+			target := gv.WithKind(gvk.Kind)
+			if target == gvk {
+				return obj, nil
+			}
+
+			if !scheme.Recognizes(target) {
+				continue
+			}
+
+			return convertTo(scheme, obj, target)
+		}
+
+		return obj, nil
+	}
+}
+
+// convertTo converts an unstructured object to the target GroupVersionKind through typed objects.
+func convertTo(
+	scheme *runtime.Scheme,
+	obj unstructured.Unstructured,
+	target schema.GroupVersionKind,
+) (unstructured.Unstructured, error) {
+	gvk := obj.GroupVersionKind()
+
+	in, err := scheme.New(gvk)
+	if err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to create %s: %w", gvk, err)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, in); err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to decode %s: %w", gvk, err)
+	}
+
+	out, err := scheme.New(target)
+	if err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to create %s: %w", target, err)
+	}
+
+	if err := scheme.Convert(in, out, nil); err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to convert %s to %s: %w", gvk, target, err)
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(out)
+	if err != nil {
+		return unstructured.Unstructured{}, fmt.Errorf("failed to encode %s: %w", target, err)
+	}
+
+	result := unstructured.Unstructured{Object: data}
+	result.SetGroupVersionKind(target)
+
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	jqmatcher "github.com/lburgazzoli/gomega-matchers/pkg/matchers/jq"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+var (
+	widgetV1Alpha1 = schema.GroupVersion{Group: "example.com", Version: "v1alpha1"}
+	widgetV1       = schema.GroupVersion{Group: "example.com", Version: "v1"}
+)
+
+type widgetAlpha struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Size int `json:"size"`
+}
+
+func (w *widgetAlpha) DeepCopyObject() runtime.Object {
+	c := *w
+	c.ObjectMeta = *w.ObjectMeta.DeepCopy()
+
+	return &c
+}
+
+type widgetSpec struct {
+	Replicas int `json:"replicas"`
+}
+
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec widgetSpec `json:"spec"`
+}
+
+func (w *widget) DeepCopyObject() runtime.Object {
+	c := *w
+	c.ObjectMeta = *w.ObjectMeta.DeepCopy()
+
+	return &c
+}
+
+func newWidgetScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(widgetV1Alpha1.WithKind("Widget"), &widgetAlpha{})
+	scheme.AddKnownTypeWithName(widgetV1.WithKind("Widget"), &widget{})
+
+	err := scheme.AddConversionFunc((*widgetAlpha)(nil), (*widget)(nil), func(a any, b any, _ conversion.Scope) error {
+		in := a.(*widgetAlpha)
+		out := b.(*widget)
+		out.ObjectMeta = in.ObjectMeta
+		out.Spec.Replicas = in.Size
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to register conversion: %v", err)
+	}
+
+	return scheme
+}
+
+const widgetAlphaYAML = `
+apiVersion: example.com/v1alpha1
+kind: Widget
+metadata:
+  name: test-widget
+size: 3
+`
+
+func TestConvertVersions(t *testing.T) {
+	t.Run("should convert objects to the target version", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"widget.yaml": &fstest.MapFile{Data: []byte(widgetAlphaYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformer(yaml.ConvertVersions(newWidgetScheme(t), widgetV1)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object).To(And(
+			jqmatcher.Match(`.apiVersion == "example.com/v1"`),
+			jqmatcher.Match(`.kind == "Widget"`),
+			jqmatcher.Match(`.metadata.name == "test-widget"`),
+			jqmatcher.Match(`.spec.replicas == 3`),
+		))
+	})
+
+	t.Run("should leave unknown kinds unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformer(yaml.ConvertVersions(newWidgetScheme(t), widgetV1)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetAPIVersion()).To(Equal("v1"))
+	})
+
+	t.Run("should leave objects without a target version unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"widget.yaml": &fstest.MapFile{Data: []byte(widgetAlphaYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformer(yaml.ConvertVersions(newWidgetScheme(t), schema.GroupVersion{Group: "apps", Version: "v1"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetAPIVersion()).To(Equal("example.com/v1alpha1"))
+	})
+}
@@ -0,0 +1,229 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	goyaml "gopkg.in/yaml.v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	// ErrTabIndentation is returned when a YAML document uses tab characters for indentation.
+	ErrTabIndentation = newClassError(ErrParse, "tab character used for indentation")
+
+	// ErrEmptyDocument is returned when a file contains an empty document and empty documents are rejected.
+	ErrEmptyDocument = newClassError(ErrParse, "empty YAML document")
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// maxPooledBufferSize bounds the capacity of buffers returned to bufferPool,
+// so a single huge manifest does not pin memory for the lifetime of the process.
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool holds read buffers reused across files and renders.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// readPooled reads r into a pooled buffer, pre-sized to sizeHint bytes.
+// The returned release function must be called once the content is no longer referenced.
+func readPooled(r io.Reader, sizeHint int64) ([]byte, func(), error) {
+	buf, _ := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	release := func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			bufferPool.Put(buf)
+		}
+	}
+
+	if sizeHint > 0 && sizeHint < maxPooledBufferSize {
+		buf.Grow(int(sizeHint) + bytes.MinRead)
+	}
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		release()
+
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), release, nil
+}
+
+// normalizeYAML strips a leading UTF-8 byte order mark and converts CRLF line endings to LF.
+func normalizeYAML(content []byte) []byte {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	if bytes.Contains(content, []byte("\r\n")) {
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	}
+
+	return content
+}
+
+// isBinary reports whether content looks like binary data rather than UTF-8 text.
+func isBinary(content []byte) bool {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
+
+// decodeYAML normalizes and decodes YAML content into unstructured objects using decoder.
+// When decoding fails because of tab indentation, a positioned ErrTabIndentation is returned
+// instead of the upstream parser error.
+func decodeYAML(decoder Decoder, content []byte) ([]unstructured.Unstructured, error) {
+	content = normalizeYAML(content)
+
+	objects, err := decoder.Decode(content)
+	if err != nil {
+		if line, col, ok := findTabIndentation(content); ok {
+			return nil, fmt.Errorf("line %d, column %d: %w", line, col, ErrTabIndentation)
+		}
+
+		return nil, classify(ErrParse, fmt.Errorf("failed to decode YAML: %w", err))
+	}
+
+	return objects, nil
+}
+
+// checkEmptyDocuments applies the empty document policy to YAML content. It is a no-op
+// with PolicyIgnore, as empty documents are always skipped by the decoder.
+func (r *Renderer) checkEmptyDocuments(ctx context.Context, path string, content []byte) error {
+	if r.opts.EmptyDocumentPolicy == PolicyIgnore {
+		return nil
+	}
+
+	for _, line := range findEmptyDocuments(content) {
+		switch r.opts.EmptyDocumentPolicy {
+		case PolicyError:
+			return fmt.Errorf("%w: %s: line %d", ErrEmptyDocument, path, line)
+		case PolicyWarn:
+			ReportWarning(ctx, Warning{File: path, Line: line, Rule: "empty-document", Message: fmt.Sprintf("skipped empty document at line %d", line)})
+		case PolicyIgnore:
+		}
+	}
+
+	return nil
+}
+
+// findEmptyDocuments returns the 1-based start lines of documents that are empty, null,
+// comment-only or an empty mapping.
+func findEmptyDocuments(content []byte) []int {
+	var lines []int
+
+	dec := goyaml.NewDecoder(bytes.NewReader(normalizeYAML(content)))
+
+	for {
+		var node goyaml.Node
+
+		// Syntax errors are reported with more context by the regular decoder
+		if err := dec.Decode(&node); err != nil {
+			return lines
+		}
+
+		if isEmptyDocument(&node) {
+			lines = append(lines, node.Line)
+		}
+	}
+}
+
+// isEmptyDocument reports whether a document node holds no content.
+func isEmptyDocument(node *goyaml.Node) bool {
+	if len(node.Content) == 0 {
+		return true
+	}
+
+	root := node.Content[0]
+
+	switch root.Kind {
+	case goyaml.ScalarNode:
+		return root.Tag == "!!null"
+	case goyaml.MappingNode:
+		return len(root.Content) == 0
+	case goyaml.DocumentNode, goyaml.SequenceNode, goyaml.AliasNode:
+		return false
+	}
+
+	return false
+}
+
+// findTabIndentation returns the 1-based position of a tab in the leading whitespace of the line
+// gopkg.in/yaml.v3 reports a syntax error on, so tabs in unrelated lines (e.g. block scalar
+// content) are not blamed for other errors. Errors the parser attributes to a tab may point at
+// the line opening the enclosing block, so the first tab-indented line from there is used.
+func findTabIndentation(content []byte) (int, int, bool) {
+	msg, ok := syntaxError(content)
+	if !ok {
+		return 0, 0, false
+	}
+
+	m := errorLine.FindStringSubmatch(msg)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	errLine, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	last := errLine
+
+	if strings.Contains(msg, "tab character") {
+		last = len(lines)
+	}
+
+	for i := errLine; i <= last && i <= len(lines); i++ {
+		if col, ok := leadingTab(lines[i-1]); ok {
+			return i, col, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// syntaxError returns the message of the first syntax error gopkg.in/yaml.v3 reports for content.
+func syntaxError(content []byte) (string, bool) {
+	dec := goyaml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		var node goyaml.Node
+
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return "", false
+		}
+
+		if err != nil {
+			return err.Error(), true
+		}
+	}
+}
+
+// leadingTab returns the 1-based column of the first tab in the leading whitespace of line.
+func leadingTab(line []byte) (int, bool) {
+	for j, c := range line {
+		if c == '\t' {
+			return j + 1, true
+		}
+
+		if c != ' ' {
+			break
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,188 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// ErrRemovedAPI is returned when an object uses an apiVersion removed in the target Kubernetes version.
+var ErrRemovedAPI = newClassError(ErrValidation, "api version removed in target kubernetes version")
+
+// DeprecatedAPI describes an apiVersion/kind that is deprecated and eventually removed from Kubernetes.
+type DeprecatedAPI struct {
+	// GroupVersionKind is the deprecated API.
+	GroupVersionKind schema.GroupVersionKind
+
+	// DeprecatedIn is the Kubernetes version that deprecated the API (e.g. "1.16").
+	DeprecatedIn string
+
+	// RemovedIn is the Kubernetes version that stopped serving the API (e.g. "1.22").
+	RemovedIn string
+
+	// Replacement is the apiVersion to migrate to (e.g. "apps/v1").
+	Replacement string
+}
+
+// DeprecatedAPIs returns the built-in list of deprecated and removed Kubernetes APIs.
+// The returned slice is a copy and may be extended by callers.
+func DeprecatedAPIs() []DeprecatedAPI {
+	apis := []DeprecatedAPI{
+		deprecated("extensions", "v1beta1", "Deployment", "1.9", "1.16", "apps/v1"),
+		deprecated("extensions", "v1beta1", "DaemonSet", "1.9", "1.16", "apps/v1"),
+		deprecated("extensions", "v1beta1", "ReplicaSet", "1.9", "1.16", "apps/v1"),
+		deprecated("extensions", "v1beta1", "NetworkPolicy", "1.9", "1.16", "networking.k8s.io/v1"),
+		deprecated("extensions", "v1beta1", "PodSecurityPolicy", "1.10", "1.16", "policy/v1beta1"),
+		deprecated("extensions", "v1beta1", "Ingress", "1.14", "1.22", "networking.k8s.io/v1"),
+		deprecated("apps", "v1beta1", "Deployment", "1.9", "1.16", "apps/v1"),
+		deprecated("apps", "v1beta1", "StatefulSet", "1.9", "1.16", "apps/v1"),
+		deprecated("apps", "v1beta2", "Deployment", "1.9", "1.16", "apps/v1"),
+		deprecated("apps", "v1beta2", "StatefulSet", "1.9", "1.16", "apps/v1"),
+		deprecated("apps", "v1beta2", "DaemonSet", "1.9", "1.16", "apps/v1"),
+		deprecated("apps", "v1beta2", "ReplicaSet", "1.9", "1.16", "apps/v1"),
+		deprecated("networking.k8s.io", "v1beta1", "Ingress", "1.19", "1.22", "networking.k8s.io/v1"),
+		deprecated("networking.k8s.io", "v1beta1", "IngressClass", "1.19", "1.22", "networking.k8s.io/v1"),
+		deprecated("rbac.authorization.k8s.io", "v1beta1", "ClusterRole", "1.17", "1.22", "rbac.authorization.k8s.io/v1"),
+		deprecated("rbac.authorization.k8s.io", "v1beta1", "ClusterRoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"),
+		deprecated("rbac.authorization.k8s.io", "v1beta1", "Role", "1.17", "1.22", "rbac.authorization.k8s.io/v1"),
+		deprecated("rbac.authorization.k8s.io", "v1beta1", "RoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"),
+		deprecated("apiextensions.k8s.io", "v1beta1", "CustomResourceDefinition", "1.16", "1.22", "apiextensions.k8s.io/v1"),
+		deprecated("admissionregistration.k8s.io", "v1beta1", "MutatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"),
+		deprecated("admissionregistration.k8s.io", "v1beta1", "ValidatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"),
+		deprecated("apiregistration.k8s.io", "v1beta1", "APIService", "1.19", "1.22", "apiregistration.k8s.io/v1"),
+		deprecated("scheduling.k8s.io", "v1beta1", "PriorityClass", "1.14", "1.22", "scheduling.k8s.io/v1"),
+		deprecated("storage.k8s.io", "v1beta1", "CSIDriver", "1.19", "1.22", "storage.k8s.io/v1"),
+		deprecated("storage.k8s.io", "v1beta1", "CSINode", "1.17", "1.22", "storage.k8s.io/v1"),
+		deprecated("storage.k8s.io", "v1beta1", "StorageClass", "1.19", "1.22", "storage.k8s.io/v1"),
+		deprecated("storage.k8s.io", "v1beta1", "VolumeAttachment", "1.19", "1.22", "storage.k8s.io/v1"),
+		deprecated("storage.k8s.io", "v1beta1", "CSIStorageCapacity", "1.24", "1.27", "storage.k8s.io/v1"),
+		deprecated("coordination.k8s.io", "v1beta1", "Lease", "1.19", "1.22", "coordination.k8s.io/v1"),
+		deprecated("certificates.k8s.io", "v1beta1", "CertificateSigningRequest", "1.19", "1.22", "certificates.k8s.io/v1"),
+		deprecated("batch", "v1beta1", "CronJob", "1.21", "1.25", "batch/v1"),
+		deprecated("policy", "v1beta1", "PodDisruptionBudget", "1.21", "1.25", "policy/v1"),
+		deprecated("policy", "v1beta1", "PodSecurityPolicy", "1.21", "1.25", ""),
+		deprecated("discovery.k8s.io", "v1beta1", "EndpointSlice", "1.21", "1.25", "discovery.k8s.io/v1"),
+		deprecated("events.k8s.io", "v1beta1", "Event", "1.22", "1.25", "events.k8s.io/v1"),
+		deprecated("autoscaling", "v2beta1", "HorizontalPodAutoscaler", "1.22", "1.25", "autoscaling/v2"),
+		deprecated("autoscaling", "v2beta2", "HorizontalPodAutoscaler", "1.23", "1.26", "autoscaling/v2"),
+		deprecated("node.k8s.io", "v1beta1", "RuntimeClass", "1.22", "1.25", "node.k8s.io/v1"),
+		deprecated("flowcontrol.apiserver.k8s.io", "v1beta1", "FlowSchema", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"),
+		deprecated("flowcontrol.apiserver.k8s.io", "v1beta1", "PriorityLevelConfiguration", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"),
+		deprecated("flowcontrol.apiserver.k8s.io", "v1beta2", "FlowSchema", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"),
+		deprecated("flowcontrol.apiserver.k8s.io", "v1beta2", "PriorityLevelConfiguration", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"),
+		deprecated("flowcontrol.apiserver.k8s.io", "v1beta3", "FlowSchema", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"),
+		deprecated("flowcontrol.apiserver.k8s.io", "v1beta3", "PriorityLevelConfiguration", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"),
+	}
+
+	return apis
+}
+
+func deprecated(
+	group string,
+	ver string,
+	kind string,
+	deprecatedIn string,
+	removedIn string,
+	replacement string,
+) DeprecatedAPI {
+	return DeprecatedAPI{
+		GroupVersionKind: schema.GroupVersionKind{Group: group, Version: ver, Kind: kind},
+		DeprecatedIn:     deprecatedIn,
+		RemovedIn:        removedIn,
+		Replacement:      replacement,
+	}
+}
+
+// DeprecatedAPIValidator returns a validator that flags objects using deprecated or removed apiVersions
+// for the given target Kubernetes version (e.g. "1.29" or "v1.29.3").
+//
+// Deprecated APIs that are still served are reported as warnings. APIs removed in the target version
+// are reported as warnings with PolicyWarn or fail validation with PolicyError; PolicyIgnore disables
+// the check. If targetVersion is empty, every known deprecated API is reported as a warning.
+// If no apis are given, the built-in DeprecatedAPIs() list is used.
+func DeprecatedAPIValidator(targetVersion string, policy Policy, apis ...DeprecatedAPI) (Validator, error) {
+	var target *version.Version
+
+	if targetVersion != "" {
+		v, err := version.ParseGeneric(targetVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubernetes version %q: %w", targetVersion, err)
+		}
+
+		target = v
+	}
+
+	if len(apis) == 0 {
+		apis = DeprecatedAPIs()
+	}
+
+	index := make(map[schema.GroupVersionKind]DeprecatedAPI, len(apis))
+	for _, api := range apis {
+		index[api.GroupVersionKind] = api
+	}
+
+	return func(ctx context.Context, object unstructured.Unstructured) error {
+		if policy == PolicyIgnore {
+			return nil
+		}
+
+		api, ok := index[object.GroupVersionKind()]
+		if !ok {
+			return nil
+		}
+
+		return checkDeprecatedAPI(ctx, object, api, target, policy)
+	}, nil
+}
+
+func checkDeprecatedAPI(
+	ctx context.Context,
+	object unstructured.Unstructured,
+	api DeprecatedAPI,
+	target *version.Version,
+	policy Policy,
+) error {
+	gvk := object.GroupVersionKind()
+
+	replacement := ""
+	if api.Replacement != "" {
+		replacement = ", use " + api.Replacement
+	}
+
+	if target != nil && api.RemovedIn != "" && atLeast(target, api.RemovedIn) {
+		msg := fmt.Sprintf("%s %s: %s removed in %s%s", gvk.Kind, object.GetName(), gvk.GroupVersion(), api.RemovedIn, replacement)
+
+		if policy == PolicyError {
+			return fmt.Errorf("%w: %s", ErrRemovedAPI, msg)
+		}
+
+		ReportWarning(ctx, Warning{File: sourceFile(object), Rule: "removed-api", Message: msg})
+
+		return nil
+	}
+
+	if target != nil && api.DeprecatedIn != "" && !atLeast(target, api.DeprecatedIn) {
+		return nil
+	}
+
+	ReportWarning(ctx, Warning{
+		File:    sourceFile(object),
+		Rule:    "deprecated-api",
+		Message: fmt.Sprintf("%s %s: %s deprecated in %s%s", gvk.Kind, object.GetName(), gvk.GroupVersion(), api.DeprecatedIn, replacement),
+	})
+
+	return nil
+}
+
+// atLeast reports whether v is at or after the given major.minor version string.
+func atLeast(v *version.Version, other string) bool {
+	o, err := version.ParseGeneric(other)
+	if err != nil {
+		return false
+	}
+
+	return v.AtLeast(o)
+}
@@ -0,0 +1,131 @@
+package yaml_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const cronJobV1Beta1YAML = `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: test-cronjob
+spec:
+  schedule: "* * * * *"
+`
+
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []yaml.Warning
+}
+
+func (c *warningCollector) Handle(_ context.Context, w yaml.Warning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.warnings = append(c.warnings, w)
+}
+
+func (c *warningCollector) Warnings() []yaml.Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]yaml.Warning(nil), c.warnings...)
+}
+
+func TestDeprecatedAPIPolicy(t *testing.T) {
+	testFS := fstest.MapFS{
+		"cronjob.yaml": &fstest.MapFile{Data: []byte(cronJobV1Beta1YAML)},
+		"pod.yaml":     &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	t.Run("should warn about deprecated APIs still served", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithKubernetesVersion("1.22"),
+			yaml.WithDeprecatedAPIPolicy(yaml.PolicyError),
+			yaml.WithWarningHandler(collector.Handle),
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "deprecated-api"),
+			HaveField("File", "cronjob.yaml"),
+			HaveField("Message", ContainSubstring("batch/v1")),
+		)))
+	})
+
+	t.Run("should fail on removed APIs with error policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithKubernetesVersion("v1.29.1"),
+			yaml.WithDeprecatedAPIPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrRemovedAPI))
+		g.Expect(err.Error()).To(ContainSubstring("test-cronjob"))
+	})
+
+	t.Run("should warn on removed APIs with warn policy", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithKubernetesVersion("1.29"),
+			yaml.WithDeprecatedAPIPolicy(yaml.PolicyWarn),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(collector.Warnings()).To(ConsistOf(HaveField("Rule", "removed-api")))
+	})
+
+	t.Run("should not report APIs before their deprecation", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithKubernetesVersion("1.20"),
+			yaml.WithDeprecatedAPIPolicy(yaml.PolicyError),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(collector.Warnings()).To(BeEmpty())
+	})
+
+	t.Run("should reject invalid kubernetes versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithKubernetesVersion("latest"),
+			yaml.WithDeprecatedAPIPolicy(yaml.PolicyWarn),
+		)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
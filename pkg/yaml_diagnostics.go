@@ -0,0 +1,176 @@
+package yaml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Severity is the severity of a Diagnostic.
+type Severity string
+
+const (
+	// SeverityError marks a diagnostic that failed the render.
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks a non-fatal issue reported as a Warning.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a machine-readable warning or error of a render, e.g. for CI systems and bots
+// posting review comments.
+type Diagnostic struct {
+	// File is the source file the diagnostic relates to, if known.
+	File string `json:"file,omitempty"`
+
+	// Line is the 1-based line in File, or 0 if unknown.
+	Line int `json:"line,omitempty"`
+
+	// Severity is SeverityError for render errors and SeverityWarning for warnings.
+	Severity Severity `json:"severity"`
+
+	// Rule identifies the check that produced the diagnostic: the Warning rule for warnings,
+	// and the error class for errors ("no-match", "parse", "validation", "source-unavailable",
+	// "limit-exceeded" or "render-error").
+	Rule string `json:"rule"`
+
+	// Message is a human-readable description of the issue.
+	Message string `json:"message"`
+}
+
+// diagnosticsKey carries the diagnostics collector of Diagnose in the render context.
+type diagnosticsKey struct{}
+
+// diagnosticsCollector collects the warnings reported during Diagnose.
+type diagnosticsCollector struct {
+	mu          sync.Mutex
+	diagnostics []Diagnostic
+}
+
+func (c *diagnosticsCollector) add(w Warning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		File:     w.File,
+		Line:     w.Line,
+		Severity: SeverityWarning,
+		Rule:     w.Rule,
+		Message:  w.Message,
+	})
+}
+
+// collectDiagnostic records a warning if ctx belongs to Diagnose.
+func collectDiagnostic(ctx context.Context, w Warning) {
+	if c, ok := ctx.Value(diagnosticsKey{}).(*diagnosticsCollector); ok {
+		c.add(w)
+	}
+}
+
+// Diagnose renders like Process and returns all warnings and errors of the render as
+// diagnostics, warnings first in reporting order. Warnings are still passed to the handler set
+// with WithWarningHandler. The file and line of errors are taken from the failing file and, if
+// reported, the decoder or a SourceExcerptError. The render error is returned as well, so
+// callers can tell failed renders apart without inspecting severities.
+//
+// Example:
+//
+//	diagnostics, _ := r.Diagnose(ctx, nil)
+//	_ = yaml.WriteDiagnostics(os.Stdout, diagnostics)
+func (r *Renderer) Diagnose(ctx context.Context, values map[string]any) ([]Diagnostic, error) {
+	collector := &diagnosticsCollector{}
+
+	_, _, err := r.process(context.WithValue(ctx, diagnosticsKey{}, collector), values)
+
+	collector.mu.Lock()
+	diagnostics := append([]Diagnostic(nil), collector.diagnostics...)
+	collector.mu.Unlock()
+
+	return append(diagnostics, errorDiagnostics(err)...), err
+}
+
+// WriteDiagnostics writes diagnostics as an indented JSON document of the form
+// {"diagnostics": [...]}.
+func WriteDiagnostics(w io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(struct {
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}{diagnostics}); err != nil {
+		return fmt.Errorf("failed to encode diagnostics: %w", err)
+	}
+
+	return nil
+}
+
+// errorDiagnostics converts a render error into diagnostics, one per joined error.
+func errorDiagnostics(err error) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok && !isClassified(err) {
+		var diagnostics []Diagnostic
+		for _, e := range joined.Unwrap() {
+			diagnostics = append(diagnostics, errorDiagnostics(e)...)
+		}
+
+		return diagnostics
+	}
+
+	d := Diagnostic{
+		Severity: SeverityError,
+		Rule:     errorRule(err),
+		Message:  err.Error(),
+	}
+
+	var excerptErr *SourceExcerptError
+	var fileErr *fileError
+
+	switch {
+	case errors.As(err, &excerptErr):
+		d.File, d.Line = excerptErr.File, excerptErr.Line
+	case errors.As(err, &fileErr):
+		d.File = fileErr.file
+		if m := errorLine.FindStringSubmatch(fileErr.err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	return []Diagnostic{d}
+}
+
+// isClassified reports whether err is an error tagged with its class, whose Unwrap also
+// returns multiple errors.
+func isClassified(err error) bool {
+	_, ok := err.(*classifiedError) //nolint:errorlint // only the error itself matters
+
+	return ok
+}
+
+// errorRule returns the diagnostic rule of an error from its class.
+func errorRule(err error) string {
+	switch {
+	case errors.Is(err, ErrNoMatch):
+		return "no-match"
+	case errors.Is(err, ErrParse):
+		return "parse"
+	case errors.Is(err, ErrValidation):
+		return "validation"
+	case errors.Is(err, ErrSourceUnavailable):
+		return "source-unavailable"
+	case errors.Is(err, ErrLimitExceeded):
+		return "limit-exceeded"
+	default:
+		return "render-error"
+	}
+}
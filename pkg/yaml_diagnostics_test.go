@@ -0,0 +1,86 @@
+package yaml_test
+
+import (
+	"bytes"
+	"testing"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDiagnose(t *testing.T) {
+	files := map[string]string{
+		"a-pod.yaml":    podYAML + "---\n",
+		"b-broken.yaml": malformedConfigMapYAML,
+	}
+
+	t.Run("should report warnings and errors with their location", func(t *testing.T) {
+		g := NewWithT(t)
+		warnings := &warningCollector{}
+
+		renderer, err := yaml.NewFromStrings(files,
+			yaml.WithEmptyDocumentPolicy(yaml.PolicyWarn),
+			yaml.WithWarningHandler(warnings.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		diagnostics, err := renderer.Diagnose(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrParse))
+		g.Expect(diagnostics).To(HaveExactElements(
+			yaml.Diagnostic{
+				File:     "a-pod.yaml",
+				Line:     13,
+				Severity: yaml.SeverityWarning,
+				Rule:     "empty-document",
+				Message:  "skipped empty document at line 13",
+			},
+			And(
+				HaveField("File", "b-broken.yaml"),
+				HaveField("Line", 5),
+				HaveField("Severity", yaml.SeverityError),
+				HaveField("Rule", "parse"),
+				HaveField("Message", err.Error()),
+			),
+		))
+
+		// Warnings still reach the configured handler
+		g.Expect(warnings.Warnings()).To(HaveLen(1))
+	})
+
+	t.Run("should return no diagnostics for clean renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{"pod.yaml": podYAML})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		diagnostics, err := renderer.Diagnose(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(diagnostics).To(BeEmpty())
+	})
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	t.Run("should write diagnostics as JSON", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.WriteDiagnostics(&buf, []yaml.Diagnostic{
+			{File: "pod.yaml", Line: 3, Severity: yaml.SeverityWarning, Rule: "lossy-number", Message: "line 3: lossy"},
+			{Severity: yaml.SeverityError, Rule: "no-match", Message: "no files matched pattern"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(buf.String()).To(MatchJSON(`{"diagnostics": [
+			{"file": "pod.yaml", "line": 3, "severity": "warning", "rule": "lossy-number", "message": "line 3: lossy"},
+			{"severity": "error", "rule": "no-match", "message": "no files matched pattern"}
+		]}`))
+	})
+
+	t.Run("should write an empty list without diagnostics", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		g.Expect(yaml.WriteDiagnostics(&buf, nil)).To(Succeed())
+		g.Expect(buf.String()).To(MatchJSON(`{"diagnostics": []}`))
+	})
+}
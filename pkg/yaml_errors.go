@@ -0,0 +1,93 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error classes group the errors returned by the renderer by failure class, so callers can
+// branch with errors.Is on the class instead of matching specific errors or messages. Specific
+// errors (e.g. ErrTabIndentation) belong to their class and still match themselves, and errors
+// wrapped from the filesystem or from validators are tagged with their class. ErrLimitExceeded
+// is the class of resource limit violations.
+//
+// Example:
+//
+//	switch _, err := r.Process(ctx, nil); {
+//	case errors.Is(err, yaml.ErrSourceUnavailable):
+//		// retry later
+//	case errors.Is(err, yaml.ErrParse), errors.Is(err, yaml.ErrValidation):
+//		// report to the manifest author
+//	}
+var (
+	// ErrNoMatch is the class of errors caused by patterns matching no files.
+	ErrNoMatch = errors.New("no match")
+
+	// ErrParse is the class of errors caused by content that cannot be decoded.
+	ErrParse = errors.New("parse error")
+
+	// ErrValidation is the class of errors caused by objects or files rejected by a check,
+	// including validators set with WithValidator.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrSourceUnavailable is the class of errors caused by sources that cannot be read.
+	ErrSourceUnavailable = errors.New("source unavailable")
+)
+
+// classError is a specific error belonging to an error class.
+type classError struct {
+	msg   string
+	class error
+}
+
+// newClassError returns a specific error with message msg belonging to class.
+func newClassError(class error, msg string) error {
+	return &classError{msg: msg, class: class}
+}
+
+func (e *classError) Error() string {
+	return e.msg
+}
+
+// Is reports whether target is the class of the error.
+func (e *classError) Is(target error) bool {
+	return target == e.class
+}
+
+// classifiedError tags a wrapped error with an error class, keeping its message.
+type classifiedError struct {
+	err   error
+	class error
+}
+
+// classify tags err with class, unless it is nil, already belongs to it or was caused by an
+// interrupted render.
+func classify(class error, err error) error {
+	if err == nil || errors.Is(err, class) || errors.Is(err, ErrRenderInterrupted) {
+		return err
+	}
+
+	return &classifiedError{err: err, class: class}
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.err, e.class}
+}
+
+// fileError is an error loading a source file, recording the file for diagnostics.
+type fileError struct {
+	file string
+	err  error
+}
+
+func (e *fileError) Error() string {
+	return fmt.Sprintf("failed to load %s: %v", e.file, e.err)
+}
+
+func (e *fileError) Unwrap() error {
+	return e.err
+}
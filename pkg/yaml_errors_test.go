@@ -0,0 +1,128 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+// policyError is a typed error returned by a test validator.
+type policyError struct {
+	rule string
+}
+
+func (e *policyError) Error() string {
+	return "policy violated: " + e.rule
+}
+
+func TestErrorClasses(t *testing.T) {
+	classes := []error{yaml.ErrNoMatch, yaml.ErrParse, yaml.ErrValidation, yaml.ErrSourceUnavailable, yaml.ErrLimitExceeded}
+
+	render := func(t *testing.T, source yaml.Source, opts ...yaml.RendererOption) error {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{source}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+
+		return err
+	}
+
+	tests := []struct {
+		name     string
+		source   yaml.Source
+		opts     []yaml.RendererOption
+		class    error
+		specific error
+	}{
+		{
+			name:     "no match",
+			source:   yaml.Source{FS: fstest.MapFS{}, Path: "*.yaml"},
+			class:    yaml.ErrNoMatch,
+			specific: yaml.ErrNoFilesMatched,
+		},
+		{
+			name:   "malformed YAML",
+			source: yaml.Source{FS: fstest.MapFS{"bad.yaml": &fstest.MapFile{Data: []byte("kind: [unclosed")}}, Path: "*.yaml"},
+			class:  yaml.ErrParse,
+		},
+		{
+			name: "tab indentation",
+			source: yaml.Source{
+				FS:   fstest.MapFS{"bad.yaml": &fstest.MapFile{Data: []byte("kind: Pod\nmetadata:\n\tname: x\n")}},
+				Path: "*.yaml",
+			},
+			class:    yaml.ErrParse,
+			specific: yaml.ErrTabIndentation,
+		},
+		{
+			name:     "unexpected object count",
+			source:   yaml.Source{FS: fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}, Path: "*.yaml", ExpectAtLeast: 2},
+			class:    yaml.ErrValidation,
+			specific: yaml.ErrUnexpectedObjectCount,
+		},
+		{
+			name: "unreadable source",
+			source: yaml.Source{
+				FS:   &flakyFS{FS: fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}, failures: 1, err: errTransient},
+				Path: "pod.yaml",
+			},
+			class:    yaml.ErrSourceUnavailable,
+			specific: errTransient,
+		},
+		{
+			name:     "file too large",
+			source:   yaml.Source{FS: fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}, Path: "*.yaml"},
+			opts:     []yaml.RendererOption{yaml.WithMaxFileSize(8)},
+			class:    yaml.ErrLimitExceeded,
+			specific: yaml.ErrLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should classify "+tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := render(t, tt.source, tt.opts...)
+			g.Expect(err).To(MatchError(tt.class))
+
+			if tt.specific != nil {
+				g.Expect(err).To(MatchError(tt.specific))
+			}
+
+			for _, class := range classes {
+				if class != tt.class {
+					g.Expect(errors.Is(err, class)).To(BeFalse(), "unexpected class %v", class)
+				}
+			}
+		})
+	}
+
+	t.Run("should classify validator errors and keep them accessible", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := render(
+			t,
+			yaml.Source{FS: fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}, Path: "*.yaml"},
+			yaml.WithValidator(func(_ context.Context, _ unstructured.Unstructured) error {
+				return &policyError{rule: "no-pods"}
+			}),
+		)
+		g.Expect(err).To(MatchError(yaml.ErrValidation))
+		g.Expect(err).To(MatchError(ContainSubstring("policy violated: no-pods")))
+
+		var policyErr *policyError
+		g.Expect(errors.As(err, &policyErr)).To(BeTrue())
+		g.Expect(policyErr.rule).To(Equal("no-pods"))
+	})
+}
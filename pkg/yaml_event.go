@@ -0,0 +1,85 @@
+package yaml
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EventType identifies a render pipeline event.
+type EventType string
+
+const (
+	// EventRenderStarted is emitted when Process starts, after pre-render hooks.
+	EventRenderStarted EventType = "RenderStarted"
+
+	// EventFileMatched is emitted for each file matched by a source pattern.
+	EventFileMatched EventType = "FileMatched"
+
+	// EventFileParsed is emitted after a matched file has been decoded.
+	EventFileParsed EventType = "FileParsed"
+
+	// EventObjectEmitted is emitted for each object returned by Process,
+	// after filters, transformers and validators.
+	EventObjectEmitted EventType = "ObjectEmitted"
+
+	// EventRenderFinished is emitted when Process finishes, before post-render hooks.
+	EventRenderFinished EventType = "RenderFinished"
+)
+
+// Event describes a step of the render pipeline. Fields not relevant to an event type are left empty.
+type Event struct {
+	// Type is the kind of event.
+	Type EventType
+
+	// Time is when the event was emitted.
+	Time time.Time
+
+	// Label is the label of the renderer instance, if set with WithLabel.
+	Label string
+
+	// Source is the name of the source being rendered, if any.
+	Source string
+
+	// Pattern is the pattern (or label) of the source being rendered.
+	Pattern string
+
+	// File is the matched or parsed file. For ObjectEmitted it is taken from the
+	// source file annotation and is only set when source annotations are enabled.
+	File string
+
+	// GroupVersionKind, Namespace and Name identify the emitted object.
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+
+	// Objects is the number of objects decoded from File (FileParsed)
+	// or returned by the render (RenderFinished).
+	Objects int
+
+	// Duration is the render duration (RenderFinished).
+	Duration time.Duration
+
+	// Err is the render error, if any (RenderFinished).
+	Err error
+}
+
+// EventListener receives render pipeline events synchronously, in pipeline order.
+// Listeners should return quickly and must be safe for concurrent use if the renderer is used concurrently.
+// File events are only emitted when files are read, not for cached renders.
+type EventListener func(ctx context.Context, event Event)
+
+// emit sends an event to all registered listeners.
+func (r *Renderer) emit(ctx context.Context, event Event) {
+	if len(r.opts.EventListeners) == 0 {
+		return
+	}
+
+	event.Time = time.Now()
+	event.Label = r.opts.Label
+
+	for _, listener := range r.opts.EventListeners {
+		listener(ctx, event)
+	}
+}
@@ -0,0 +1,83 @@
+package yaml_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestEventListener(t *testing.T) {
+	testFS := fstest.MapFS{
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	t.Run("should emit events in pipeline order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var events []yaml.Event
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Name: "all", FS: testFS, Path: "*.yaml"}},
+			yaml.WithSourceAnnotations(true),
+			yaml.WithEventListener(func(_ context.Context, event yaml.Event) {
+				events = append(events, event)
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		types := make([]yaml.EventType, 0, len(events))
+		for _, event := range events {
+			g.Expect(event.Time).ToNot(BeZero())
+			types = append(types, event.Type)
+		}
+
+		g.Expect(types).To(Equal([]yaml.EventType{
+			yaml.EventRenderStarted,
+			yaml.EventFileMatched,
+			yaml.EventFileParsed,
+			yaml.EventFileMatched,
+			yaml.EventFileParsed,
+			yaml.EventObjectEmitted,
+			yaml.EventObjectEmitted,
+			yaml.EventObjectEmitted,
+			yaml.EventRenderFinished,
+		}))
+
+		g.Expect(events[2].File).To(Equal("multi-doc.yaml"))
+		g.Expect(events[2].Objects).To(Equal(2))
+		g.Expect(events[5].Source).To(Equal("all"))
+		g.Expect(events[5].File).To(Equal("multi-doc.yaml"))
+		g.Expect(events[5].GroupVersionKind.Kind).To(Equal("Service"))
+		g.Expect(events[7].Name).To(Equal("test-pod"))
+		g.Expect(events[8].Objects).To(Equal(3))
+		g.Expect(events[8].Err).ToNot(HaveOccurred())
+	})
+
+	t.Run("should report render errors in the finished event", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var finished yaml.Event
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "missing.yaml"}},
+			yaml.WithEventListener(func(_ context.Context, event yaml.Event) {
+				if event.Type == yaml.EventRenderFinished {
+					finished = event
+				}
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(finished.Err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+}
@@ -0,0 +1,204 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	goyaml "gopkg.in/yaml.v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// excerptContext is the number of lines shown before and after the offending line.
+const excerptContext = 2
+
+// errorLine matches the line number reported by decoding errors (e.g. "yaml: line 3: ...").
+var errorLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// SourceExcerptError adds an annotated excerpt of the offending source lines to an error, so
+// manifest authors see the failing location without opening the file. Its message is the
+// message of Err followed by the excerpt. Values of sensitive kinds are redacted in the excerpt
+// unless redaction is disabled.
+type SourceExcerptError struct {
+	// File is the path of the source file within the source filesystem.
+	File string
+
+	// Line is the 1-based line the excerpt is centered on.
+	Line int
+
+	// Excerpt holds the numbered lines around Line, the offending line marked with ">".
+	Excerpt string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *SourceExcerptError) Error() string {
+	return fmt.Sprintf("%v\n%s:%d:\n%s", e.Err, e.File, e.Line, e.Excerpt)
+}
+
+func (e *SourceExcerptError) Unwrap() error {
+	return e.Err
+}
+
+// withExcerpt adds an excerpt of content around the line reported by err. The error is returned
+// unchanged unless WithSourceExcerpts is enabled and err reports a line within content.
+func (r *Renderer) withExcerpt(err error, path string, content []byte) error {
+	if err == nil || !r.opts.SourceExcerpts {
+		return err
+	}
+
+	m := errorLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+
+	line, convErr := strconv.Atoi(m[1])
+	if convErr != nil {
+		return err
+	}
+
+	return newExcerptError(err, path, normalizeYAML(content), line, r.sensitiveContent(content))
+}
+
+// withValidationExcerpt adds an excerpt of the document obj was decoded from to a validation
+// error. Files of the source are read again, so objects renamed by transformers or files changed
+// since the render get no excerpt.
+func (r *Renderer) withValidationExcerpt(
+	ctx context.Context,
+	holder *sourceHolder,
+	files []string,
+	obj unstructured.Unstructured,
+	err error,
+) error {
+	if !r.opts.SourceExcerpts || holder.Objects != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if checkContext(ctx) != nil {
+			return err
+		}
+
+		content, readErr := fs.ReadFile(holder.FS, file)
+		if readErr != nil {
+			continue
+		}
+
+		content = normalizeYAML(content)
+
+		if line, ok := findDocumentLine(content, obj.GetKind(), obj.GetName()); ok {
+			sensitive := r.opts.Redact && isSensitive(obj, r.opts.SensitiveKinds)
+
+			return newExcerptError(err, file, content, line, sensitive)
+		}
+	}
+
+	return err
+}
+
+// newExcerptError wraps err with the lines of content around line, redacting values if sensitive.
+// err is returned unchanged if line is out of range.
+func newExcerptError(err error, path string, content []byte, line int, sensitive bool) error {
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if line < 1 || line > len(lines) {
+		return err
+	}
+
+	first := max(1, line-excerptContext)
+	last := min(len(lines), line+excerptContext)
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+
+	for n := first; n <= last; n++ {
+		text := lines[n-1]
+		if sensitive {
+			text = redactExcerptLine(text)
+		}
+
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+
+		b.WriteString(strings.TrimRight(fmt.Sprintf("%s %*d | %s", marker, width, n, text), " "))
+		b.WriteString("\n")
+	}
+
+	return &SourceExcerptError{
+		File:    path,
+		Line:    line,
+		Excerpt: strings.TrimSuffix(b.String(), "\n"),
+		Err:     err,
+	}
+}
+
+// redactExcerptLine replaces the value of a source line with RedactedValue, keeping its
+// indentation and key. Only top-level apiVersion and kind values are kept.
+func redactExcerptLine(line string) string {
+	trimmed := strings.TrimLeft(line, " -")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+		return line
+	}
+
+	indent := line[:len(line)-len(trimmed)]
+
+	key, value, found := strings.Cut(trimmed, ":")
+	if !found {
+		return indent + RedactedValue
+	}
+
+	if strings.TrimSpace(value) == "" || indent == "" && (key == "apiVersion" || key == "kind") {
+		return line
+	}
+
+	return indent + key + ": " + RedactedValue
+}
+
+// findDocumentLine returns the line of the kind key of the first document in content with the
+// given kind and metadata.name.
+func findDocumentLine(content []byte, kind string, name string) (int, bool) {
+	dec := goyaml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		var node goyaml.Node
+		if err := dec.Decode(&node); err != nil {
+			return 0, false
+		}
+
+		if len(node.Content) == 0 || node.Content[0].Kind != goyaml.MappingNode {
+			continue
+		}
+
+		root := node.Content[0]
+
+		kindKey, kindValue := mappingEntry(root, "kind")
+		_, metadata := mappingEntry(root, "metadata")
+		_, nameValue := mappingEntry(metadata, "name")
+
+		if kindValue != nil && kindValue.Value == kind && nameValue != nil && nameValue.Value == name {
+			return kindKey.Line, true
+		}
+	}
+}
+
+// mappingEntry returns the key and value nodes of key in a mapping node, or nils.
+func mappingEntry(mapping *goyaml.Node, key string) (*goyaml.Node, *goyaml.Node) {
+	if mapping == nil || mapping.Kind != goyaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const malformedConfigMapYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: broken
+data:
+  key: [unclosed
+  other: value
+`
+
+const malformedSecretYAML = `apiVersion: v1
+kind: Secret
+metadata:
+  name: broken
+stringData:
+  password: "hunter22
+  user: admin
+`
+
+func TestSourceExcerpts(t *testing.T) {
+	render := func(t *testing.T, content string, opts ...yaml.RendererOption) error {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{"manifest.yaml": content}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+
+		return err
+	}
+
+	t.Run("should add an excerpt to decoding errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := render(t, malformedConfigMapYAML, yaml.WithSourceExcerpts(true))
+		g.Expect(err).To(MatchError(yaml.ErrParse))
+
+		var excerptErr *yaml.SourceExcerptError
+		g.Expect(errors.As(err, &excerptErr)).To(BeTrue())
+		g.Expect(excerptErr.File).To(Equal("manifest.yaml"))
+		g.Expect(excerptErr.Line).To(Equal(5))
+		g.Expect(excerptErr.Excerpt).To(Equal("" +
+			"  3 | metadata:\n" +
+			"  4 |   name: broken\n" +
+			"> 5 | data:\n" +
+			"  6 |   key: [unclosed\n" +
+			"  7 |   other: value",
+		))
+		g.Expect(err.Error()).To(ContainSubstring("manifest.yaml:5:\n  3 | metadata:"))
+	})
+
+	t.Run("should redact values of sensitive kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := render(t, malformedSecretYAML, yaml.WithSourceExcerpts(true))
+
+		var excerptErr *yaml.SourceExcerptError
+		g.Expect(errors.As(err, &excerptErr)).To(BeTrue())
+		g.Expect(excerptErr.Excerpt).To(ContainSubstring("password: " + yaml.RedactedValue))
+		g.Expect(err.Error()).ToNot(ContainSubstring("hunter22"))
+		g.Expect(err.Error()).ToNot(ContainSubstring("admin"))
+	})
+
+	t.Run("should add an excerpt of the rejected document to validation errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := render(t, multiDocYAML,
+			yaml.WithSourceExcerpts(true),
+			yaml.WithValidator(func(_ context.Context, obj unstructured.Unstructured) error {
+				if obj.GetKind() == "Secret" {
+					return errors.New("secrets are not allowed")
+				}
+
+				return nil
+			}),
+		)
+		g.Expect(err).To(MatchError(yaml.ErrValidation))
+
+		var excerptErr *yaml.SourceExcerptError
+		g.Expect(errors.As(err, &excerptErr)).To(BeTrue())
+		g.Expect(excerptErr.Line).To(Equal(11))
+		g.Expect(excerptErr.Excerpt).To(ContainSubstring("> 11 | kind: Secret"))
+		g.Expect(excerptErr.Excerpt).To(ContainSubstring("name: " + yaml.RedactedValue))
+	})
+
+	t.Run("should not add excerpts by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := render(t, malformedConfigMapYAML)
+
+		var excerptErr *yaml.SourceExcerptError
+		g.Expect(errors.As(err, &excerptErr)).To(BeFalse())
+	})
+}
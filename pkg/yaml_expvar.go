@@ -0,0 +1,75 @@
+package yaml
+
+import (
+	"context"
+	"expvar"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// expvarMu serializes lookups and registrations of expvar maps.
+var expvarMu sync.Mutex
+
+// ExpvarMetrics returns a post-render hook publishing render counters as the expvar map name,
+// served as JSON by the expvar handler (/debug/vars) without extra dependencies:
+//   - renders, errors: number of renders and of failed renders
+//   - cacheHits, sourcesRendered: sources served from the cache and sources read from files
+//   - objects: number of objects returned by the last successful render
+//   - lastRenderSeconds: duration of the last render
+//
+// Renderers using the same name share the map, aggregating their counters. Renders aborted by a
+// pre-render hook are not counted. It panics if name is already published as another kind of
+// variable, like expvar.Publish.
+func ExpvarMetrics(name string) PostRenderHook {
+	m, objects, lastDuration := expvarMap(name)
+
+	return func(_ context.Context, _ []unstructured.Unstructured, report RenderReport) error {
+		m.Add("renders", 1)
+		lastDuration.Set(report.Duration.Seconds())
+
+		if report.Err != nil {
+			m.Add("errors", 1)
+
+			return nil
+		}
+
+		objects.Set(int64(report.Objects))
+
+		for _, source := range report.Sources {
+			if source.Cached {
+				m.Add("cacheHits", 1)
+			} else {
+				m.Add("sourcesRendered", 1)
+			}
+		}
+
+		return nil
+	}
+}
+
+// expvarMap returns the published expvar map name and its gauges, publishing them if needed.
+func expvarMap(name string) (*expvar.Map, *expvar.Int, *expvar.Float) {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		// Panics if name is used by another kind of variable
+		m = expvar.NewMap(name)
+	}
+
+	objects, ok := m.Get("objects").(*expvar.Int)
+	if !ok {
+		objects = new(expvar.Int)
+		m.Set("objects", objects)
+	}
+
+	lastDuration, ok := m.Get("lastRenderSeconds").(*expvar.Float)
+	if !ok {
+		lastDuration = new(expvar.Float)
+		m.Set("lastRenderSeconds", lastDuration)
+	}
+
+	return m, objects, lastDuration
+}
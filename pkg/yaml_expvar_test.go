@@ -0,0 +1,54 @@
+package yaml_test
+
+import (
+	"expvar"
+	"testing"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	t.Run("should publish render counters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"pod.yaml": podYAML, "configmap.yaml": configMapYAML},
+			yaml.WithCache(),
+			yaml.WithExpvar("renderer_yaml_test"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for range 3 {
+			_, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		m, ok := expvar.Get("renderer_yaml_test").(*expvar.Map)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(m.Get("renders").String()).To(Equal("3"))
+		g.Expect(m.Get("sourcesRendered").String()).To(Equal("1"))
+		g.Expect(m.Get("cacheHits").String()).To(Equal("2"))
+		g.Expect(m.Get("objects").String()).To(Equal("2"))
+		g.Expect(m.Get("errors")).To(BeNil())
+		g.Expect(m.Get("lastRenderSeconds")).ToNot(BeNil())
+	})
+
+	t.Run("should count failed renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"bad.yaml": "\x00"},
+			yaml.WithExpvar("renderer_yaml_test_errors"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+
+		m, ok := expvar.Get("renderer_yaml_test_errors").(*expvar.Map)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(m.Get("errors").String()).To(Equal("1"))
+	})
+}
@@ -0,0 +1,91 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AnnotationFanOutNamespace is set by ProcessNamespaces on namespaced objects to the namespace
+// of the pass that rendered them.
+const AnnotationFanOutNamespace = "manifests.k8s-manifests-lib/fan-out.namespace"
+
+// FanOutNamespaceKey holds the namespace of the current ProcessNamespaces pass, so filters,
+// transformers, validators and hooks can adapt objects per tenant.
+var FanOutNamespaceKey = NewContextKey[string]("fan-out-namespace")
+
+// ProcessNamespaces renders the configured sources once per namespace, for deploying identical
+// stacks to several tenant namespaces. In each pass, decoded namespaced objects are moved to the
+// pass namespace and annotated with AnnotationFanOutNamespace before filters and transformers
+// run; the namespace is also available to the pipeline as FanOutNamespaceKey. Cluster-scoped
+// objects (see IsClusterScoped) are shared by all passes and returned once, from the first pass.
+// Objects are returned grouped by pass, in namespace order.
+//
+// Example:
+//
+//	objects, err := r.ProcessNamespaces(ctx, []string{"tenant-a", "tenant-b"})
+func (r *Renderer) ProcessNamespaces(ctx context.Context, namespaces []string) ([]unstructured.Unstructured, error) {
+	for i, namespace := range namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+
+		if slices.Contains(namespaces[:i], namespace) {
+			return nil, fmt.Errorf("duplicate namespace %q", namespace)
+		}
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for i, namespace := range namespaces {
+		objects, err := r.Process(context.WithValue(ctx, FanOutNamespaceKey, namespace), nil)
+
+		for _, obj := range objects {
+			// Cluster-scoped objects are identical in every pass
+			if i > 0 && IsClusterScoped(obj.GroupVersionKind().GroupKind()) {
+				continue
+			}
+
+			result = append(result, obj)
+		}
+
+		if err != nil {
+			if len(objects) > 0 {
+				return result, fmt.Errorf("namespace %s: %w", namespace, err)
+			}
+
+			return nil, fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+	}
+
+	return result, nil
+}
+
+// injectFanOutNamespace moves namespaced objects to the namespace of the current
+// ProcessNamespaces pass, if any.
+func injectFanOutNamespace(ctx context.Context, objects []unstructured.Unstructured) {
+	namespace, ok := PipelineValue(ctx, FanOutNamespaceKey)
+	if !ok {
+		return
+	}
+
+	for i := range objects {
+		if IsClusterScoped(objects[i].GroupVersionKind().GroupKind()) {
+			continue
+		}
+
+		objects[i].SetNamespace(namespace)
+
+		annotations := objects[i].GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+
+		annotations[AnnotationFanOutNamespace] = namespace
+		objects[i].SetAnnotations(annotations)
+	}
+}
@@ -0,0 +1,80 @@
+package yaml_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const stackYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app-reader
+`
+
+func TestProcessNamespaces(t *testing.T) {
+	t.Run("should render the stack once per namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tenantLabel := func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			if namespace, ok := yaml.PipelineValue(ctx, yaml.FanOutNamespaceKey); ok {
+				obj.SetLabels(map[string]string{"tenant": namespace})
+			}
+
+			return obj, nil
+		}
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"stack.yaml": stackYAML},
+			yaml.WithCache(),
+			yaml.WithTransformer(tenantLabel),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.ProcessNamespaces(t.Context(), []string{"tenant-a", "tenant-b"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[0].GetNamespace()).To(Equal("tenant-a"))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("tenant", "tenant-a"))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(yaml.AnnotationFanOutNamespace, "tenant-a"))
+
+		g.Expect(objects[1].GetKind()).To(Equal("ClusterRole"))
+		g.Expect(objects[1].GetNamespace()).To(BeEmpty())
+		g.Expect(objects[1].GetAnnotations()).ToNot(HaveKey(yaml.AnnotationFanOutNamespace))
+
+		g.Expect(objects[2].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[2].GetNamespace()).To(Equal("tenant-b"))
+		g.Expect(objects[2].GetLabels()).To(HaveKeyWithValue("tenant", "tenant-b"))
+
+		// Regular renders are not affected
+		objects, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects[0].GetNamespace()).To(BeEmpty())
+	})
+
+	t.Run("should reject invalid and duplicate namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{"stack.yaml": stackYAML})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.ProcessNamespaces(t.Context(), []string{"Tenant_A"})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid namespace")))
+
+		_, err = renderer.ProcessNamespaces(t.Context(), []string{"tenant-a", "tenant-a"})
+		g.Expect(err).To(MatchError(ContainSubstring("duplicate namespace")))
+	})
+}
@@ -0,0 +1,215 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ErrInvalidFieldPath is returned when a field path expression cannot be parsed.
+var ErrInvalidFieldPath = errors.New("invalid field path")
+
+// fieldSegment is a single step of a field path: a mapping key, a list index or all list items.
+type fieldSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// RemoveFields returns a transformer that removes the fields at the given paths from objects,
+// e.g. server-populated fields when re-rendering exported manifests. Missing fields are ignored.
+//
+// Paths are dot-separated keys ending with the key to remove. Keys containing dots or other
+// special characters are written in brackets and quoted, list items are selected by index, and
+// [*] selects all items:
+//
+//	metadata.annotations['kubectl.kubernetes.io/last-applied-configuration']
+//	spec.clusterIP
+//	spec.template.spec.containers[*].terminationMessagePath
+//
+// Combine it with TransformIf to remove fields from matching objects only.
+//
+// Example:
+//
+//	strip, err := yaml.RemoveFields(
+//		"metadata.annotations['kubectl.kubernetes.io/last-applied-configuration']",
+//		"spec.clusterIP",
+//	)
+func RemoveFields(paths ...string) (types.Transformer, error) {
+	parsed := make([][]fieldSegment, len(paths))
+
+	for i, p := range paths {
+		segments, err := parseFieldPath(p)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed[i] = segments
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		for _, segments := range parsed {
+			removeField(obj.Object, segments)
+		}
+
+		return obj, nil
+	}, nil
+}
+
+// removeField removes the field at segments from value. The last segment is always a key.
+func removeField(value any, segments []fieldSegment) {
+	seg := segments[0]
+	last := len(segments) == 1
+
+	switch v := value.(type) {
+	case map[string]any:
+		if seg.isIndex || seg.wildcard {
+			return
+		}
+
+		if last {
+			delete(v, seg.key)
+
+			return
+		}
+
+		if child, ok := v[seg.key]; ok {
+			removeField(child, segments[1:])
+		}
+	case []any:
+		switch {
+		case seg.wildcard:
+			for _, item := range v {
+				removeField(item, segments[1:])
+			}
+		case seg.isIndex && seg.index < len(v):
+			removeField(v[seg.index], segments[1:])
+		}
+	}
+}
+
+// hasField reports whether value has a field at segments. With [*], any list item counts.
+func hasField(value any, segments []fieldSegment) bool {
+	if len(segments) == 0 {
+		return true
+	}
+
+	seg := segments[0]
+
+	switch v := value.(type) {
+	case map[string]any:
+		if seg.isIndex || seg.wildcard {
+			return false
+		}
+
+		child, ok := v[seg.key]
+
+		return ok && hasField(child, segments[1:])
+	case []any:
+		switch {
+		case seg.wildcard:
+			for _, item := range v {
+				if hasField(item, segments[1:]) {
+					return true
+				}
+			}
+		case seg.isIndex && seg.index < len(v):
+			return hasField(v[seg.index], segments[1:])
+		}
+	}
+
+	return false
+}
+
+// parseFieldPath parses a field path expression into segments.
+func parseFieldPath(p string) ([]fieldSegment, error) {
+	var segments []fieldSegment
+
+	rest := p
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q: unterminated bracket", ErrInvalidFieldPath, p)
+			}
+
+			inner := rest[1:end]
+
+			// Quoted keys may contain "]"
+			if strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`) {
+				quote := inner[:1]
+
+				closing := strings.Index(rest[2:], quote+"]")
+				if closing < 0 {
+					return nil, fmt.Errorf("%w: %q: unterminated quoted key", ErrInvalidFieldPath, p)
+				}
+
+				segments = append(segments, fieldSegment{key: rest[2 : 2+closing]})
+				rest = rest[2+closing+2:]
+
+				break
+			}
+
+			segment, err := parseIndex(inner)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidFieldPath, p, err)
+			}
+
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("%w: %q: leading dot", ErrInvalidFieldPath, p)
+			}
+
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("%w: %q: empty key", ErrInvalidFieldPath, p)
+			}
+		default:
+			if len(segments) > 0 && !strings.HasPrefix(p[len(p)-len(rest)-1:], ".") {
+				return nil, fmt.Errorf("%w: %q: missing dot before %q", ErrInvalidFieldPath, p, rest)
+			}
+
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			segments = append(segments, fieldSegment{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidFieldPath)
+	}
+
+	if last := segments[len(segments)-1]; last.isIndex || last.wildcard {
+		return nil, fmt.Errorf("%w: %q: path must end with a key", ErrInvalidFieldPath, p)
+	}
+
+	return segments, nil
+}
+
+// parseIndex parses the content of an unquoted bracket: a list index or "*".
+func parseIndex(s string) (fieldSegment, error) {
+	if s == "*" {
+		return fieldSegment{wildcard: true}, nil
+	}
+
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return fieldSegment{}, fmt.Errorf("invalid list index %q", s)
+	}
+
+	return fieldSegment{index: index, isIndex: true}, nil
+}
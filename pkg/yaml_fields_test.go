@@ -0,0 +1,99 @@
+package yaml_test
+
+import (
+	"testing"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const exportedServiceYAML = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+    team: web
+spec:
+  clusterIP: 10.0.0.12
+  ports:
+  - name: http
+    port: 80
+    nodePort: 30080
+  - name: https
+    port: 443
+    nodePort: 30443
+`
+
+func TestRemoveFields(t *testing.T) {
+	t.Run("should remove fields by path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		strip, err := yaml.RemoveFields(
+			"metadata.annotations['kubectl.kubernetes.io/last-applied-configuration']",
+			"spec.clusterIP",
+			"spec.ports[*].nodePort",
+			"spec.missing.field",
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"service.yaml": exportedServiceYAML},
+			yaml.WithTransformer(strip),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetAnnotations()).To(Equal(map[string]string{"team": "web"}))
+		g.Expect(objects[0].Object["spec"]).To(Equal(map[string]any{
+			"ports": []any{
+				map[string]any{"name": "http", "port": int64(80)},
+				map[string]any{"name": "https", "port": int64(443)},
+			},
+		}))
+	})
+
+	t.Run("should remove fields of a single list item", func(t *testing.T) {
+		g := NewWithT(t)
+
+		strip, err := yaml.RemoveFields(`spec.ports[1]["nodePort"]`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"service.yaml": exportedServiceYAML},
+			yaml.WithTransformer(strip),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects[0].Object["spec"]).To(HaveKeyWithValue("ports", []any{
+			map[string]any{"name": "http", "port": int64(80), "nodePort": int64(30080)},
+			map[string]any{"name": "https", "port": int64(443)},
+		}))
+	})
+
+	t.Run("should reject invalid paths", func(t *testing.T) {
+		for _, p := range []string{
+			"",
+			".spec",
+			"spec..clusterIP",
+			"spec.",
+			"spec.ports[*]",
+			"spec.ports[x].name",
+			"spec.ports[0]name",
+			"metadata.annotations['unterminated",
+		} {
+			t.Run(p, func(t *testing.T) {
+				g := NewWithT(t)
+
+				_, err := yaml.RemoveFields(p)
+				g.Expect(err).To(MatchError(yaml.ErrInvalidFieldPath))
+			})
+		}
+	})
+}
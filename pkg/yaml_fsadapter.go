@@ -0,0 +1,227 @@
+package yaml
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AferoFS is the part of afero.Fs (github.com/spf13/afero) read by FromAfero. It is declared
+// here so the renderer does not depend on afero; any afero.Fs satisfies it.
+type AferoFS[F AferoFile] interface {
+	Open(name string) (F, error)
+	Stat(name string) (os.FileInfo, error)
+}
+
+// AferoFile is the part of afero.File read by FromAfero.
+type AferoFile interface {
+	io.ReadCloser
+	Stat() (os.FileInfo, error)
+	Readdir(count int) ([]os.FileInfo, error)
+}
+
+// BillyFS is the part of billy.Filesystem (github.com/go-git/go-billy/v5) read by FromBilly,
+// e.g. the worktree filesystem of a go-git repository. It is declared here so the renderer does
+// not depend on billy; any billy.Filesystem satisfies it.
+type BillyFS[F io.ReadCloser] interface {
+	Open(filename string) (F, error)
+	Stat(filename string) (os.FileInfo, error)
+	ReadDir(path string) ([]os.FileInfo, error)
+}
+
+// FromAfero adapts an afero filesystem to an fs.FS usable as Source.FS. The file type is
+// inferred from the filesystem:
+//
+//	yaml.Source{FS: yaml.FromAfero(afero.NewBasePathFs(afero.NewOsFs(), "/manifests")), Path: "**/*.yaml"}
+func FromAfero[F AferoFile](fsys AferoFS[F]) fs.FS {
+	return &adaptedFS{
+		open: func(name string) (io.ReadCloser, error) {
+			return fsys.Open(name)
+		},
+		stat: fsys.Stat,
+		readDir: func(name string) ([]os.FileInfo, error) {
+			dir, err := fsys.Open(name)
+			if err != nil {
+				return nil, err
+			}
+			defer dir.Close()
+
+			return dir.Readdir(-1)
+		},
+	}
+}
+
+// FromBilly adapts a billy filesystem to an fs.FS usable as Source.FS, so manifests can be
+// rendered from a go-git worktree (including in-memory clones) without checking them out:
+//
+//	yaml.Source{FS: yaml.FromBilly(worktree.Filesystem), Path: "deploy/*.yaml"}
+func FromBilly[F io.ReadCloser](fsys BillyFS[F]) fs.FS {
+	return &adaptedFS{
+		open: func(name string) (io.ReadCloser, error) {
+			return fsys.Open(name)
+		},
+		stat:    fsys.Stat,
+		readDir: fsys.ReadDir,
+	}
+}
+
+// adaptedFS is an fs.FS over the functions of a third-party filesystem. Names are validated
+// and converted to the platform's separators before they are passed on.
+type adaptedFS struct {
+	open    func(name string) (io.ReadCloser, error)
+	stat    func(name string) (os.FileInfo, error)
+	readDir func(name string) ([]os.FileInfo, error)
+}
+
+// native converts a validated fs.FS name to a name for the adapted filesystem.
+func native(op string, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	return filepath.FromSlash(name), nil
+}
+
+// Open implements fs.FS.
+func (a *adaptedFS) Open(name string) (fs.File, error) {
+	info, err := a.Stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: unwrapPathError(err)}
+	}
+
+	if info.IsDir() {
+		return &dirFile{name: name, info: info, list: func() ([]fs.DirEntry, error) {
+			return a.ReadDir(name)
+		}}, nil
+	}
+
+	p, err := native("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := a.open(p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: unwrapPathError(err)}
+	}
+
+	return &adaptedFile{ReadCloser: f, info: info}, nil
+}
+
+// Stat implements fs.StatFS.
+func (a *adaptedFS) Stat(name string) (fs.FileInfo, error) {
+	p, err := native("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := a.stat(p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: unwrapPathError(err)}
+	}
+
+	return info, nil
+}
+
+// ReadDir implements fs.ReadDirFS, returning entries sorted by name.
+func (a *adaptedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := native("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := a.readDir(p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: unwrapPathError(err)}
+	}
+
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
+
+// unwrapPathError returns the cause of a path error, so adapted errors name fs.FS paths.
+func unwrapPathError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+
+	return err
+}
+
+// adaptedFile is a regular file of an adaptedFS.
+type adaptedFile struct {
+	io.ReadCloser
+
+	info fs.FileInfo
+}
+
+// Stat implements fs.File.
+func (f *adaptedFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// dirFile is an opened directory whose entries are listed on the first ReadDir call.
+type dirFile struct {
+	name    string
+	info    fs.FileInfo
+	list    func() ([]fs.DirEntry, error)
+	entries []fs.DirEntry
+	read    bool
+}
+
+// Stat implements fs.File.
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Read implements fs.File.
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// Close implements fs.File.
+func (d *dirFile) Close() error {
+	return nil
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.list()
+		if err != nil {
+			return nil, err
+		}
+
+		d.entries, d.read = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+
+	return entries, nil
+}
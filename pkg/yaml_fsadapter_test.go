@@ -0,0 +1,168 @@
+package yaml_test
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+// aferoFile and aferoFs mirror the shape of afero.File and afero.Fs: Open returns an interface.
+type aferoFile interface {
+	io.ReadCloser
+	Name() string
+	Stat() (os.FileInfo, error)
+	Readdir(count int) ([]os.FileInfo, error)
+}
+
+type aferoFs interface {
+	Open(name string) (aferoFile, error)
+	Stat(name string) (os.FileInfo, error)
+	Name() string
+}
+
+// billyFile and billyFs mirror the shape of billy.File and billy.Filesystem.
+type billyFile interface {
+	io.ReadCloser
+	Name() string
+}
+
+type billyFs interface {
+	Open(filename string) (billyFile, error)
+	Stat(filename string) (os.FileInfo, error)
+	ReadDir(path string) ([]os.FileInfo, error)
+}
+
+// mapFs implements aferoFs and billyFs over an fstest.MapFS, with OS path names.
+type mapFs struct {
+	fstest.MapFS
+}
+
+type mapFile struct {
+	fs.File
+
+	name string
+}
+
+func (f *mapFile) Name() string {
+	return f.name
+}
+
+func (f *mapFile) Readdir(int) ([]os.FileInfo, error) {
+	entries, err := f.File.(fs.ReadDirFile).ReadDir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return entryInfos(entries)
+}
+
+func entryInfos(entries []fs.DirEntry) ([]os.FileInfo, error) {
+	infos := make([]os.FileInfo, len(entries))
+	for i, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		infos[i] = info
+	}
+
+	return infos, nil
+}
+
+func (m mapFs) open(name string) (*mapFile, error) {
+	f, err := m.MapFS.Open(filepath.ToSlash(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapFile{File: f, name: name}, nil
+}
+
+func (m mapFs) Stat(name string) (os.FileInfo, error) {
+	return m.MapFS.Stat(filepath.ToSlash(name))
+}
+
+func (m mapFs) Name() string {
+	return "map"
+}
+
+func (m mapFs) ReadDir(path string) ([]os.FileInfo, error) {
+	entries, err := m.MapFS.ReadDir(filepath.ToSlash(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return entryInfos(entries)
+}
+
+type aferoMapFs struct {
+	mapFs
+}
+
+func (m aferoMapFs) Open(name string) (aferoFile, error) {
+	return m.open(name)
+}
+
+type billyMapFs struct {
+	mapFs
+}
+
+func (m billyMapFs) Open(filename string) (billyFile, error) {
+	return m.open(filename)
+}
+
+func TestFilesystemAdapters(t *testing.T) {
+	files := fstest.MapFS{
+		"pod.yaml":              &fstest.MapFile{Data: []byte(podYAML)},
+		"config/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"config/multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	var afs aferoFs = aferoMapFs{mapFs{files}}
+	var bfs billyFs = billyMapFs{mapFs{files}}
+
+	adapters := map[string]fs.FS{
+		"afero": yaml.FromAfero(afs),
+		"billy": yaml.FromBilly(bfs),
+	}
+
+	for name, fsys := range adapters {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should satisfy fs.FS semantics", func(t *testing.T) {
+				g := NewWithT(t)
+
+				err := fstest.TestFS(fsys, "pod.yaml", "config/configmap.yaml", "config/multi-doc.yaml")
+				g.Expect(err).ToNot(HaveOccurred())
+			})
+
+			t.Run("should render sources from the adapted filesystem", func(t *testing.T) {
+				g := NewWithT(t)
+
+				renderer, err := yaml.New([]yaml.Source{{FS: fsys, Path: "**/*.yaml"}})
+				g.Expect(err).ToNot(HaveOccurred())
+
+				objects, err := renderer.Process(t.Context(), nil)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(objects).To(HaveLen(4))
+			})
+
+			t.Run("should report missing and invalid paths", func(t *testing.T) {
+				g := NewWithT(t)
+
+				_, err := fs.Stat(fsys, "missing.yaml")
+				g.Expect(err).To(MatchError(fs.ErrNotExist))
+
+				_, err = fsys.Open("../pod.yaml")
+				g.Expect(err).To(MatchError(fs.ErrInvalid))
+			})
+		})
+	}
+}
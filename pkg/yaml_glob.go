@@ -0,0 +1,353 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// expandBraces expands {a,b} alternations in a glob pattern, including nested groups
+// (e.g. "manifests/{base,prod}/*.y{a,}ml" yields four patterns). Braces can be escaped
+// with a backslash; groups without a comma are kept literally.
+func expandBraces(pattern string) ([]string, error) {
+	start, end, alternatives, err := findBraceGroup(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if start < 0 {
+		return []string{pattern}, nil
+	}
+
+	prefix := pattern[:start]
+	suffix := pattern[end+1:]
+
+	result := make([]string, 0, len(alternatives))
+	for _, alt := range alternatives {
+		expanded, err := expandBraces(prefix + alt + suffix)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, expanded...)
+	}
+
+	return result, nil
+}
+
+// findBraceGroup locates the first top-level brace group containing a comma and returns its
+// bounds and comma-separated alternatives. start is -1 if the pattern has no such group.
+func findBraceGroup(pattern string) (int, int, []string, error) {
+	depth := 0
+	start := -1
+	commas := make([]int, 0)
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			if depth == 0 {
+				start = i
+				commas = commas[:0]
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth == 0 {
+				return -1, -1, nil, fmt.Errorf("%w: %q: unmatched '}'", ErrInvalidPattern, pattern)
+			}
+
+			depth--
+			if depth == 0 && len(commas) > 0 {
+				alternatives := make([]string, 0, len(commas)+1)
+				prev := start + 1
+				for _, c := range commas {
+					alternatives = append(alternatives, pattern[prev:c])
+					prev = c + 1
+				}
+				alternatives = append(alternatives, pattern[prev:i])
+
+				return start, i, alternatives, nil
+			}
+		}
+	}
+
+	if depth > 0 {
+		return -1, -1, nil, fmt.Errorf("%w: %q: unmatched '{'", ErrInvalidPattern, pattern)
+	}
+
+	return -1, -1, nil, nil
+}
+
+// validatePattern checks that a pattern and all its brace expansions are well-formed.
+func validatePattern(pattern string) error {
+	patterns, err := expandBraces(pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidPattern, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// caseInsensitivePattern rewrites a glob pattern so that letters match regardless of case:
+// literal letters become character classes ("a" becomes "[aA]") and letters or letter ranges
+// inside existing classes gain their other-case counterparts.
+func caseInsensitivePattern(pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			writeCaseClass(&sb, pattern[i], true)
+		case c == '[':
+			end := classEnd(pattern, i)
+			if end < 0 {
+				sb.WriteString(pattern[i:])
+
+				return sb.String()
+			}
+
+			sb.WriteString(caseInsensitiveClass(pattern[i : end+1]))
+			i = end
+		default:
+			writeCaseClass(&sb, c, false)
+		}
+	}
+
+	return sb.String()
+}
+
+// writeCaseClass writes c, or a class matching both cases if c is an ASCII letter.
+// Non-letters are written unchanged, preceded by a backslash if escaped is set.
+func writeCaseClass(sb *strings.Builder, c byte, escaped bool) {
+	other := swapCase(c)
+	if other == c {
+		if escaped {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+
+		return
+	}
+
+	sb.WriteByte('[')
+	sb.WriteByte(c)
+	sb.WriteByte(other)
+	sb.WriteByte(']')
+}
+
+// classEnd returns the index of the ']' closing the character class starting at start, or -1.
+func classEnd(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// caseInsensitiveClass extends a character class with the other-case counterparts of its
+// letters and letter ranges.
+func caseInsensitiveClass(class string) string {
+	body := class[1 : len(class)-1]
+	negate := ""
+	if strings.HasPrefix(body, "^") {
+		negate = "^"
+		body = body[1:]
+	}
+
+	extra := make([]byte, 0)
+	for i := 0; i < len(body); i++ {
+		lo := body[i]
+		if lo == '\\' && i+1 < len(body) {
+			i++
+			lo = body[i]
+		}
+
+		if i+2 < len(body) && body[i+1] == '-' {
+			hi := body[i+2]
+			i += 2
+
+			if isLetter(lo) && isLetter(hi) && isUpper(lo) == isUpper(hi) {
+				extra = append(extra, swapCase(lo), '-', swapCase(hi))
+			}
+
+			continue
+		}
+
+		if other := swapCase(lo); other != lo {
+			extra = append(extra, other)
+		}
+	}
+
+	return "[" + negate + body + string(extra) + "]"
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func swapCase(c byte) byte {
+	switch {
+	case 'a' <= c && c <= 'z':
+		return c - 'a' + 'A'
+	case 'A' <= c && c <= 'Z':
+		return c - 'A' + 'a'
+	default:
+		return c
+	}
+}
+
+// glob returns the files in fsys matching pattern after brace expansion.
+// Results are deduplicated and ordered by expansion, then lexically within each expansion.
+// If caseInsensitive is set, ASCII letters match regardless of case.
+func glob(ctx context.Context, fsys fs.FS, pattern string, caseInsensitive bool) ([]string, error) {
+	// Literal paths (the common single-file source) skip expansion and matching entirely
+	if !caseInsensitive && !strings.ContainsAny(pattern, `*?[\{`) {
+		if _, err := fs.Stat(fsys, pattern); err != nil {
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+				return nil, nil
+			}
+
+			return nil, classify(ErrSourceUnavailable, err)
+		}
+
+		return []string{pattern}, nil
+	}
+
+	patterns, err := expandBraces(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if caseInsensitive {
+		for i := range patterns {
+			patterns[i] = caseInsensitivePattern(patterns[i])
+		}
+	}
+
+	if len(patterns) == 1 {
+		return globOne(ctx, fsys, patterns[0])
+	}
+
+	seen := make(map[string]struct{})
+	result := make([]string, 0)
+
+	for _, p := range patterns {
+		matches, err := globOne(ctx, fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		for _, m := range matches {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+
+			seen[m] = struct{}{}
+			result = append(result, m)
+		}
+	}
+
+	return result, nil
+}
+
+// globOne matches a single pattern, walking the filesystem when it contains a "**" segment.
+func globOne(ctx context.Context, fsys fs.FS, pattern string) ([]string, error) {
+	segments := strings.Split(pattern, "/")
+	if !slices.Contains(segments, "**") {
+		return fs.Glob(fsys, pattern)
+	}
+
+	return globRecursive(ctx, fsys, segments)
+}
+
+// globRecursive returns the files (not directories) matching pattern segments, where a "**"
+// segment matches zero or more directories. The walk starts at the longest literal prefix.
+func globRecursive(ctx context.Context, fsys fs.FS, segments []string) ([]string, error) {
+	root := "."
+	for i, segment := range segments {
+		if segment == "**" || hasMeta(segment) {
+			root = path.Join(append([]string{"."}, segments[:i]...)...)
+
+			break
+		}
+	}
+
+	result := make([]string, 0)
+
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			// Large trees are walked in full, so stop as soon as the render is canceled
+			return checkContext(ctx)
+		}
+
+		if matchSegments(segments, strings.Split(p, "/")) {
+			result = append(result, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, classify(ErrSourceUnavailable, fmt.Errorf("failed to walk %s: %w", root, err))
+	}
+
+	return result, nil
+}
+
+// hasMeta reports whether a pattern segment contains glob metacharacters.
+func hasMeta(segment string) bool {
+	return strings.ContainsAny(segment, `*?[\`)
+}
+
+// normalizeWindowsPath converts a Windows-style pattern into an io/fs compatible one:
+// backslashes become forward slashes, and a drive letter (C:) and leading separator are
+// removed so that absolute paths are interpreted relative to the filesystem root.
+// Backslashes can no longer be used as escape characters once converted.
+func normalizeWindowsPath(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+
+	if len(p) >= 2 && p[1] == ':' && isLetter(p[0]) {
+		p = p[2:]
+	}
+
+	return strings.TrimLeft(p, "/")
+}
@@ -0,0 +1,70 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ErrSourceUnreachable is returned by HealthCheck when a source filesystem cannot be accessed.
+var ErrSourceUnreachable = newClassError(ErrSourceUnavailable, "source unreachable")
+
+// HealthCheck verifies that every source is reachable by stating the longest literal prefix of
+// its pattern (the directory a glob starts from, or the file itself for literal paths), without
+// reading or decoding files. It is cheap enough for readiness probes of controllers that must not
+// become ready with broken manifest sources. Objects sources are always healthy. Failures of all
+// sources are joined, each wrapping ErrSourceUnreachable and the underlying error.
+func (r *Renderer) HealthCheck(ctx context.Context) error {
+	r.mu.RLock()
+	inputs := r.inputs
+	r.mu.RUnlock()
+
+	var errs []error
+
+	for _, holder := range inputs {
+		if holder.Objects != nil {
+			continue
+		}
+
+		if err := checkContext(ctx); err != nil {
+			return r.labelError(err)
+		}
+
+		root := "."
+		if !r.opts.CaseInsensitive {
+			root = literalRoot(holder.Path)
+		}
+
+		err := r.retry(ctx, root, func() error {
+			_, err := fs.Stat(holder.FS, root)
+
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s: %w", ErrSourceUnreachable, holder.Path, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return r.labelError(errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// literalRoot returns the longest prefix of a pattern without glob metacharacters, brace
+// expansions or ${NAME} placeholders, as a path valid for fs.Stat.
+func literalRoot(pattern string) string {
+	segments := strings.Split(pattern, "/")
+
+	for i, segment := range segments {
+		if segment == "**" || hasMeta(segment) || strings.ContainsAny(segment, "{$") {
+			return path.Join(append([]string{"."}, segments[:i]...)...)
+		}
+	}
+
+	return path.Clean(pattern)
+}
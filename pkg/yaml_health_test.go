@@ -0,0 +1,51 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHealthCheck(t *testing.T) {
+	testFS := fstest.MapFS{
+		"apps/web/pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		"config.yaml":       &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	t.Run("should succeed when all sources are reachable", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "apps/**/*.yaml"},
+			{FS: testFS, Path: "apps/${APP}/*.yaml"},
+			{FS: testFS, Path: "config.yaml"},
+			{Objects: []unstructured.Unstructured{}},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.HealthCheck(t.Context())).To(Succeed())
+	})
+
+	t.Run("should report unreachable sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "apps/**/*.yaml"},
+			{FS: testFS, Path: "missing/*.yaml"},
+			{FS: testFS, Path: "missing.yaml"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = renderer.HealthCheck(t.Context())
+		g.Expect(err).To(MatchError(yaml.ErrSourceUnreachable))
+		g.Expect(err).To(MatchError(And(
+			ContainSubstring("missing/*.yaml"),
+			ContainSubstring("missing.yaml"),
+			Not(ContainSubstring("apps/")),
+		)))
+	})
+}
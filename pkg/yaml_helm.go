@@ -0,0 +1,64 @@
+package yaml
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	helmHookAnnotation       = "helm.sh/hook"
+	helmHookWeightAnnotation = "helm.sh/hook-weight"
+)
+
+// Helm hook phases, in the order their objects are written.
+const (
+	helmPhasePre = iota
+	helmPhaseNone
+	helmPhasePost
+	helmPhaseTest
+)
+
+// compareHelmHooks orders objects by Helm hook phase and weight, then like compareObjects.
+func compareHelmHooks(a unstructured.Unstructured, b unstructured.Unstructured) int {
+	phaseA, weightA := helmHook(a)
+	phaseB, weightB := helmHook(b)
+
+	return cmp.Or(
+		cmp.Compare(phaseA, phaseB),
+		cmp.Compare(weightA, weightB),
+		compareObjects(a, b),
+	)
+}
+
+// helmHook returns the hook phase and weight of an object. Objects annotated with several hooks
+// are placed in the earliest phase; a missing or invalid weight counts as 0.
+func helmHook(obj unstructured.Unstructured) (int, int) {
+	annotations := obj.GetAnnotations()
+
+	hooks, ok := annotations[helmHookAnnotation]
+	if !ok {
+		return helmPhaseNone, 0
+	}
+
+	phase := helmPhaseTest
+	for hook := range strings.SplitSeq(hooks, ",") {
+		hook = strings.TrimSpace(hook)
+
+		switch {
+		case strings.HasPrefix(hook, "pre-"):
+			phase = min(phase, helmPhasePre)
+		case strings.HasPrefix(hook, "post-"):
+			phase = min(phase, helmPhasePost)
+		}
+	}
+
+	weight, err := strconv.Atoi(strings.TrimSpace(annotations[helmHookWeightAnnotation]))
+	if err != nil {
+		weight = 0
+	}
+
+	return phase, weight
+}
@@ -0,0 +1,111 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DocumentInfo describes where a decoded document comes from.
+type DocumentInfo struct {
+	// Source is the name of the source the document was read from, if any.
+	Source string
+
+	// Pattern is the source glob pattern that matched File.
+	Pattern string
+
+	// File is the path of the file within the source filesystem.
+	File string
+
+	// Index is the position of the object among the objects decoded from File.
+	// Empty documents are skipped and do not count.
+	Index int
+}
+
+// DocumentHook is called for every object right after it is decoded from a file
+// (and after source annotations are added), before renderer filters and transformers run.
+// The hook may modify the object in place; returning an error fails the render.
+// Hooks only run when files are decoded, so they are skipped for cached renders.
+type DocumentHook func(ctx context.Context, info DocumentInfo, obj *unstructured.Unstructured) error
+
+// RenderReport summarizes a single call to Renderer.Process.
+type RenderReport struct {
+	// Label is the label of the renderer instance, if set with WithLabel.
+	Label string
+
+	// Sources describes each rendered source, in render order.
+	// On failure, only the sources rendered before the failing one are listed.
+	Sources []SourceReport
+
+	// Objects is the total number of objects returned.
+	Objects int
+
+	// Duration is the wall-clock time spent rendering, excluding hooks.
+	Duration time.Duration
+
+	// Err is the render error, or nil if the render succeeded.
+	Err error
+}
+
+// SourceReport describes the rendering of a single source.
+type SourceReport struct {
+	// Name is the source name, if any.
+	Name string
+
+	// Path is the source glob pattern (or label, for Objects sources).
+	Path string
+
+	// Files lists the files the objects were read from. For cached sources, these are the files
+	// of the read that populated the cache entry.
+	Files []string
+
+	// Digests maps Files to their content digests ("sha256:<hex>") at the time they were read.
+	// Only recorded when provenance is enabled (WithProvenance).
+	Digests map[string]string
+
+	// Objects is the number of objects the source produced after filters and transformers.
+	Objects int
+
+	// Cached reports whether the source was served from the render cache.
+	Cached bool
+
+	// Timings lists the decode and transform time of each file read by this render, in order.
+	// It is empty when the source was served from the render cache.
+	Timings []FileTiming
+}
+
+// PreRenderHook is called at the start of Process, before any source is read.
+// Returning an error aborts the render; post-render hooks are not called in that case.
+type PreRenderHook func(ctx context.Context) error
+
+// PostRenderHook is called at the end of Process, after the full object list is assembled.
+// It is also called when the render fails, with nil objects and report.Err set, so hooks can
+// release resources acquired by a PreRenderHook. Objects must be treated as read-only.
+// Returning an error fails the render.
+type PostRenderHook func(ctx context.Context, objects []unstructured.Unstructured, report RenderReport) error
+
+// applyDocumentHooks runs all hooks over the objects decoded from a single file.
+func applyDocumentHooks(
+	ctx context.Context,
+	info DocumentInfo,
+	objects []unstructured.Unstructured,
+	hooks []DocumentHook,
+) error {
+	for i := range objects {
+		if err := checkContext(ctx); err != nil {
+			return err
+		}
+
+		info.Index = i
+
+		for _, hook := range hooks {
+			if err := hook(ctx, info, &objects[i]); err != nil {
+				return fmt.Errorf("document hook failed for document %d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
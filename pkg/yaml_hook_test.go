@@ -0,0 +1,232 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+var errRejected = errors.New("rejected")
+
+func TestDocumentHook(t *testing.T) {
+	testFS := fstest.MapFS{
+		"manifests/multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should call hook for every decoded document", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var infos []yaml.DocumentInfo
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Name: "app", FS: testFS, Path: "manifests/*.yaml"}},
+			yaml.WithDocumentHook(func(_ context.Context, info yaml.DocumentInfo, _ *unstructured.Unstructured) error {
+				infos = append(infos, info)
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(infos).To(Equal([]yaml.DocumentInfo{
+			{Source: "app", Pattern: "manifests/*.yaml", File: "manifests/multi-doc.yaml", Index: 0},
+			{Source: "app", Pattern: "manifests/*.yaml", File: "manifests/multi-doc.yaml", Index: 1},
+		}))
+	})
+
+	t.Run("should allow hooks to modify objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "manifests/*.yaml"}},
+			yaml.WithDocumentHook(func(_ context.Context, info yaml.DocumentInfo, obj *unstructured.Unstructured) error {
+				obj.SetLabels(map[string]string{"origin": info.File})
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("origin", "manifests/multi-doc.yaml"))
+		}
+	})
+
+	t.Run("should fail the render when a hook rejects a document", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "manifests/*.yaml"}},
+			yaml.WithDocumentHook(func(_ context.Context, _ yaml.DocumentInfo, obj *unstructured.Unstructured) error {
+				if obj.GetKind() == "Secret" {
+					return errRejected
+				}
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRejected))
+		g.Expect(err.Error()).To(ContainSubstring("document 1"))
+	})
+
+	t.Run("should not call hooks for cached renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		calls := 0
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "manifests/*.yaml"}},
+			yaml.WithCache(),
+			yaml.WithDocumentHook(func(_ context.Context, _ yaml.DocumentInfo, _ *unstructured.Unstructured) error {
+				calls++
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for range 3 {
+			_, err = renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		g.Expect(calls).To(Equal(2))
+	})
+}
+
+func TestRenderHooks(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should call hooks around the render with a report", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls []string
+		var reports []yaml.RenderReport
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Name: "all", FS: testFS, Path: "*.yaml"}},
+			yaml.WithCache(),
+			yaml.WithPreRenderHook(func(_ context.Context) error {
+				calls = append(calls, "pre")
+
+				return nil
+			}),
+			yaml.WithPostRenderHook(func(_ context.Context, objects []unstructured.Unstructured, report yaml.RenderReport) error {
+				calls = append(calls, "post")
+				reports = append(reports, report)
+
+				g.Expect(objects).To(HaveLen(report.Objects))
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for range 2 {
+			_, err = renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		g.Expect(calls).To(Equal([]string{"pre", "post", "pre", "post"}))
+		g.Expect(reports).To(HaveLen(2))
+
+		g.Expect(reports[0].Err).ToNot(HaveOccurred())
+		g.Expect(reports[0].Objects).To(Equal(3))
+
+		// Timings vary between runs and are covered by TestFileTimings
+		g.Expect(reports[0].Sources).To(HaveLen(1))
+		g.Expect(reports[0].Sources[0].Timings).To(HaveLen(2))
+		reports[0].Sources[0].Timings = nil
+
+		g.Expect(reports[0].Sources).To(Equal([]yaml.SourceReport{{
+			Name:    "all",
+			Path:    "*.yaml",
+			Files:   []string{"multi-doc.yaml", "pod.yaml"},
+			Objects: 3,
+			Cached:  false,
+		}}))
+
+		g.Expect(reports[1].Sources).To(HaveLen(1))
+		g.Expect(reports[1].Sources[0].Cached).To(BeTrue())
+		g.Expect(reports[1].Sources[0].Timings).To(BeEmpty())
+	})
+
+	t.Run("should abort the render when a pre-render hook fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		postCalled := false
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithPreRenderHook(func(_ context.Context) error {
+				return errRejected
+			}),
+			yaml.WithPostRenderHook(func(_ context.Context, _ []unstructured.Unstructured, _ yaml.RenderReport) error {
+				postCalled = true
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRejected))
+		g.Expect(postCalled).To(BeFalse())
+	})
+
+	t.Run("should call post-render hooks on failure", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var report yaml.RenderReport
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "missing.yaml"}},
+			yaml.WithPostRenderHook(func(_ context.Context, objects []unstructured.Unstructured, r yaml.RenderReport) error {
+				g.Expect(objects).To(BeNil())
+				report = r
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+		g.Expect(report.Err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+
+	t.Run("should fail the render when a post-render hook fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithPostRenderHook(func(_ context.Context, _ []unstructured.Unstructured, _ yaml.RenderReport) error {
+				return errRejected
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRejected))
+		g.Expect(objects).To(BeNil())
+	})
+}
@@ -0,0 +1,170 @@
+package yaml
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// DefaultIgnoreFile is the name of the ignore file honored at the root of a source filesystem.
+	DefaultIgnoreFile = ".renderignore"
+
+	// AnnotationIgnore excludes a document from the output when set to "true" and
+	// WithIgnoreAnnotation is enabled.
+	AnnotationIgnore = "manifests.k8s-manifests-lib/ignore"
+)
+
+// ignoreRule is a single gitignore-style pattern.
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// ignoreRules is an ordered list of gitignore-style patterns; later rules take precedence.
+type ignoreRules []ignoreRule
+
+// loadIgnoreRules reads and parses the ignore file at the root of fsys.
+// A missing file yields no rules.
+func loadIgnoreRules(fsys fs.FS, name string) (ignoreRules, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", name, err)
+	}
+
+	return parseIgnoreRules(content), nil
+}
+
+// parseIgnoreRules parses gitignore syntax: comments (#), negation (!), directory-only
+// patterns (trailing /), anchored patterns (leading or inner /) and ** wildcards.
+func parseIgnoreRules(content []byte) ignoreRules {
+	rules := make(ignoreRules, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(normalizeYAML(content)))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+
+		switch {
+		case strings.HasPrefix(line, "!"):
+			rule.negate = true
+			line = line[1:]
+		case strings.HasPrefix(line, `\`):
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		if line == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(line, "/")
+		if !anchored {
+			rule.segments = append([]string{"**"}, rule.segments...)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// Ignored reports whether a slash-separated file path is excluded. A file inside an excluded
+// directory is always excluded, as in git.
+func (rules ignoreRules) Ignored(p string) bool {
+	if len(rules) == 0 {
+		return false
+	}
+
+	segments := strings.Split(p, "/")
+
+	for i := 1; i < len(segments); i++ {
+		if rules.match(segments[:i], true) {
+			return true
+		}
+	}
+
+	return rules.match(segments, false)
+}
+
+func (rules ignoreRules) match(segments []string, isDir bool) bool {
+	ignored := false
+
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if matchSegments(rule.segments, segments) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, where "**" matches
+// zero or more segments and other segments use path.Match syntax.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	ok, err := path.Match(pattern[0], segments[0])
+	if err != nil || !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// dropIgnored removes the objects annotated with AnnotationIgnore set to a true value.
+func dropIgnored(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	return slices.DeleteFunc(objects, func(obj unstructured.Unstructured) bool {
+		ignored, err := strconv.ParseBool(obj.GetAnnotations()[AnnotationIgnore])
+
+		return err == nil && ignored
+	})
+}
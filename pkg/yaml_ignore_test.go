@@ -0,0 +1,135 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIgnoreFile(t *testing.T) {
+	testFS := fstest.MapFS{
+		".renderignore": &fstest.MapFile{Data: []byte(`
+# fixtures are never rendered
+fixtures/
+examples/**/*.yaml
+!examples/keep/*.yaml
+/root-only.yaml
+`)},
+		"app/pod.yaml":              &fstest.MapFile{Data: []byte(podYAML)},
+		"app/fixtures/cm.yaml":      &fstest.MapFile{Data: []byte(configMapYAML)},
+		"fixtures/cm.yaml":          &fstest.MapFile{Data: []byte(configMapYAML)},
+		"examples/a/cm.yaml":        &fstest.MapFile{Data: []byte(configMapYAML)},
+		"examples/keep/config.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"root-only.yaml":            &fstest.MapFile{Data: []byte(configMapYAML)},
+		"app/root-only.yaml":        &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	t.Run("should exclude ignored files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{
+				{FS: testFS, Path: "*/*.yaml"},
+				{FS: testFS, Path: "*/*/*.yaml"},
+			},
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		files := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			files = append(files, obj.GetAnnotations()[types.AnnotationSourceFile])
+		}
+
+		g.Expect(files).To(ConsistOf(
+			"app/pod.yaml",
+			"app/root-only.yaml",
+			"examples/keep/config.yaml",
+		))
+	})
+
+	t.Run("should apply anchored patterns at the root only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "root-only.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+
+	t.Run("should be disabled with an empty ignore file name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "fixtures/*.yaml"}},
+			yaml.WithIgnoreFile(""),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+const disabledResourcesYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: enabled
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: disabled
+  annotations:
+    manifests.k8s-manifests-lib/ignore: "true"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-disabled
+  annotations:
+    manifests.k8s-manifests-lib/ignore: "false"
+`
+
+func TestIgnoreAnnotation(t *testing.T) {
+	render := func(t *testing.T, opts ...yaml.RendererOption) []string {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{"config.yaml": disabledResourcesYAML}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		names := make([]string, len(objects))
+		for i := range objects {
+			names[i] = objects[i].GetName()
+		}
+
+		return names
+	}
+
+	t.Run("should drop annotated documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(render(t, yaml.WithIgnoreAnnotation(true))).To(Equal([]string{"enabled", "not-disabled"}))
+	})
+
+	t.Run("should keep annotated documents by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(render(t)).To(HaveLen(3))
+	})
+}
@@ -0,0 +1,119 @@
+package yaml
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// dockerHub is the registry of image references without a registry host.
+	dockerHub = "docker.io"
+
+	// dockerHubLibrary is the repository namespace of official images on Docker Hub.
+	dockerHubLibrary = "library/"
+)
+
+// MirrorRegistries returns a transformer that rewrites the images of workload containers, init
+// containers and ephemeral containers according to registry mirror rules, e.g. for air-gapped
+// clusters or to avoid rate limits. Rules map an image prefix (a registry, optionally followed by
+// repository path segments) to its replacement:
+//
+//	docker.io         -> registry.corp.local/dockerhub
+//	quay.io/prometheus -> registry.corp.local/prometheus
+//
+// Image references are normalized like the container runtime does before matching: "nginx:1.25"
+// is docker.io/library/nginx:1.25 and becomes registry.corp.local/dockerhub/library/nginx:1.25.
+// Tags and digests are kept. When several rules match, the longest prefix wins.
+//
+// Example:
+//
+//	yaml.WithTransformer(yaml.MirrorRegistries(map[string]string{
+//		"docker.io": "registry.corp.local/dockerhub",
+//	}))
+func MirrorRegistries(mirrors map[string]string) types.Transformer {
+	// Longest prefix first, so the most specific rule wins
+	prefixes := slices.SortedFunc(maps.Keys(mirrors), func(a string, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		spec, ok := podSpec(obj)
+		if !ok {
+			return obj, nil
+		}
+
+		for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			items, _ := spec[field].([]any)
+			for _, item := range items {
+				container, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+
+				if image, ok := container["image"].(string); ok {
+					container["image"] = mirrorImage(image, prefixes, mirrors)
+				}
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// mirrorImage rewrites an image reference using the first matching prefix, returning it unchanged
+// if no prefix matches.
+func mirrorImage(image string, prefixes []string, mirrors map[string]string) string {
+	name, suffix := splitImage(image)
+	name = normalizeImageName(name)
+
+	for _, prefix := range prefixes {
+		if name == prefix || strings.HasPrefix(name, prefix+"/") {
+			return mirrors[prefix] + strings.TrimPrefix(name, prefix) + suffix
+		}
+	}
+
+	return image
+}
+
+// splitImage splits an image reference into its name and its tag and/or digest suffix
+// (including the leading ":" or "@").
+func splitImage(image string) (string, string) {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+
+	// A colon after the last slash starts a tag; earlier colons belong to a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+
+	return name, image[len(name):]
+}
+
+// normalizeImageName adds the implicit Docker Hub registry and library namespace to an image name.
+func normalizeImageName(name string) string {
+	first, rest, found := strings.Cut(name, "/")
+
+	switch {
+	case !found:
+		return dockerHub + "/" + dockerHubLibrary + name
+	case first == "index.docker.io":
+		name = dockerHub + "/" + rest
+	case !strings.ContainsAny(first, ".:") && first != "localhost":
+		return dockerHub + "/" + name
+	}
+
+	if strings.HasPrefix(name, dockerHub+"/") && !strings.Contains(strings.TrimPrefix(name, dockerHub+"/"), "/") {
+		return dockerHub + "/" + dockerHubLibrary + strings.TrimPrefix(name, dockerHub+"/")
+	}
+
+	return name
+}
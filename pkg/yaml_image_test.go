@@ -0,0 +1,62 @@
+package yaml_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMirrorRegistries(t *testing.T) {
+	mirrors := map[string]string{
+		"docker.io":          "registry.corp.local/dockerhub",
+		"quay.io":            "registry.corp.local/quay",
+		"quay.io/prometheus": "registry.corp.local/prometheus",
+		"localhost:5000":     "registry.corp.local/dev",
+	}
+
+	for _, tc := range []struct {
+		image string
+		want  string
+	}{
+		{"nginx", "registry.corp.local/dockerhub/library/nginx"},
+		{"nginx:1.25", "registry.corp.local/dockerhub/library/nginx:1.25"},
+		{"bitnami/redis:7.2", "registry.corp.local/dockerhub/bitnami/redis:7.2"},
+		{"docker.io/nginx@sha256:abcd", "registry.corp.local/dockerhub/library/nginx@sha256:abcd"},
+		{"index.docker.io/library/nginx:1.25@sha256:abcd", "registry.corp.local/dockerhub/library/nginx:1.25@sha256:abcd"},
+		{"quay.io/jetstack/cert-manager:v1.14", "registry.corp.local/quay/jetstack/cert-manager:v1.14"},
+		{"quay.io/prometheus/node-exporter:v1.8", "registry.corp.local/prometheus/node-exporter:v1.8"},
+		{"quay.io/prometheusx/app", "registry.corp.local/quay/prometheusx/app"},
+		{"localhost:5000/app:dev", "registry.corp.local/dev/app:dev"},
+		{"ghcr.io/org/app:v1", "ghcr.io/org/app:v1"},
+	} {
+		t.Run(tc.image, func(t *testing.T) {
+			g := NewWithT(t)
+
+			renderer, err := yaml.NewFromStrings(
+				map[string]string{"pod.yaml": `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: ` + tc.image + `
+`},
+				yaml.WithTransformer(yaml.MirrorRegistries(mirrors)),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+
+			containers, _, _ := unstructured.NestedSlice(objects[0].Object, "spec", "containers")
+			g.Expect(containers).To(ConsistOf(HaveKeyWithValue("image", tc.want)))
+		})
+	}
+}
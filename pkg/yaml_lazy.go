@@ -0,0 +1,415 @@
+package yaml
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	goyaml "gopkg.in/yaml.v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// documentHeader is the part of a document needed to select it: its GVK and identifying metadata.
+type documentHeader struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name        string            `yaml:"name"`
+		Namespace   string            `yaml:"namespace"`
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+}
+
+// rawDocument is a single YAML document kept as raw bytes. Only its header is parsed; the
+// document is converted to unstructured objects on first access.
+type rawDocument struct {
+	data []byte
+}
+
+// header parses the apiVersion, kind and metadata of the document into an otherwise empty
+// object. The header is scanned line by line, so the rest of the document is never parsed;
+// headers the scanner does not handle (flow style, anchors, tags, escapes, multi-line scalars)
+// are parsed with yaml.v3 instead. It returns false if the header cannot be parsed, e.g. because
+// the document is not a mapping or is malformed.
+func (d rawDocument) header() (unstructured.Unstructured, bool) {
+	h, ok := scanHeader(d.data)
+	if !ok {
+		h = documentHeader{}
+		if err := goyaml.Unmarshal(d.data, &h); err != nil {
+			return unstructured.Unstructured{}, false
+		}
+	}
+
+	obj := unstructured.Unstructured{Object: make(map[string]any)}
+	obj.SetAPIVersion(h.APIVersion)
+	obj.SetKind(h.Kind)
+	obj.SetName(h.Metadata.Name)
+	obj.SetNamespace(h.Metadata.Namespace)
+	obj.SetLabels(h.Metadata.Labels)
+	obj.SetAnnotations(h.Metadata.Annotations)
+
+	return obj, true
+}
+
+// scanHeader reads the header of a block-style document from its top-level apiVersion and kind
+// keys and the name, namespace, labels and annotations of its metadata, skipping every other
+// block by indentation. It returns false as soon as it meets YAML it does not handle, so the
+// caller can fall back to a full parse.
+func scanHeader(data []byte) (documentHeader, bool) {
+	var h documentHeader
+
+	// section is the top-level key whose block is being read, field the metadata key
+	section, field := "", ""
+	fieldIndent, entryIndent := -1, -1
+
+	var entries map[string]string
+
+	seen := make(map[string]bool)
+
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		if trimmed[0] == '\t' {
+			return documentHeader{}, false
+		}
+
+		indent := len(line) - len(trimmed)
+
+		switch {
+		case indent == 0:
+			if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "...") || line[0] == '%' {
+				return documentHeader{}, false
+			}
+
+			// Sequence items of a skipped block may start at the indentation of its key
+			if section == "skip" && isSequenceItem(trimmed) {
+				continue
+			}
+
+			key, rest, ok := splitHeaderKey(trimmed)
+			if !ok || seen[key] {
+				return documentHeader{}, false
+			}
+
+			seen[key] = true
+			field = ""
+
+			switch key {
+			case "apiVersion", "kind":
+				value, ok := parseHeaderScalar(rest)
+				if !ok {
+					return documentHeader{}, false
+				}
+
+				if key == "kind" {
+					h.Kind = value
+				} else {
+					h.APIVersion = value
+				}
+
+				section = "scalar"
+			case "metadata":
+				if !isEmptyValue(rest) {
+					return documentHeader{}, false
+				}
+
+				section = "metadata"
+			default:
+				section = "skip"
+			}
+		case section == "skip":
+			continue
+		case section != "metadata":
+			// Continuation of a multi-line apiVersion or kind
+			return documentHeader{}, false
+		case fieldIndent < 0 || indent == fieldIndent:
+			fieldIndent = indent
+
+			if field == "skip" && isSequenceItem(trimmed) {
+				continue
+			}
+
+			key, rest, ok := splitHeaderKey(trimmed)
+			if !ok || seen["metadata."+key] {
+				return documentHeader{}, false
+			}
+
+			seen["metadata."+key] = true
+			field = key
+
+			switch key {
+			case "name", "namespace":
+				value, ok := parseHeaderScalar(rest)
+				if !ok {
+					return documentHeader{}, false
+				}
+
+				if key == "name" {
+					h.Metadata.Name = value
+				} else {
+					h.Metadata.Namespace = value
+				}
+
+				field = "scalar"
+			case "labels", "annotations":
+				if !isEmptyValue(rest) {
+					return documentHeader{}, false
+				}
+
+				entries, entryIndent = make(map[string]string), -1
+
+				if key == "labels" {
+					h.Metadata.Labels = entries
+				} else {
+					h.Metadata.Annotations = entries
+				}
+			default:
+				field = "skip"
+			}
+		case indent < fieldIndent:
+			return documentHeader{}, false
+		case field == "skip":
+			continue
+		case field == "labels" || field == "annotations":
+			if entryIndent < 0 {
+				entryIndent = indent
+			}
+
+			key, rest, ok := splitHeaderKey(trimmed)
+			if !ok || indent != entryIndent {
+				return documentHeader{}, false
+			}
+
+			value, ok := parseHeaderScalar(rest)
+			if _, exists := entries[key]; !ok || exists {
+				return documentHeader{}, false
+			}
+
+			entries[key] = value
+		default:
+			// Continuation of a multi-line name or namespace
+			return documentHeader{}, false
+		}
+	}
+
+	if len(h.Metadata.Labels) == 0 {
+		h.Metadata.Labels = nil
+	}
+
+	if len(h.Metadata.Annotations) == 0 {
+		h.Metadata.Annotations = nil
+	}
+
+	return h, true
+}
+
+// isSequenceItem reports whether a trimmed line starts a block sequence item.
+func isSequenceItem(trimmed string) bool {
+	return trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+}
+
+// isEmptyValue reports whether the value part of a mapping line is empty or only a comment,
+// i.e. the value is a block on the following lines.
+func isEmptyValue(rest string) bool {
+	rest = strings.TrimSpace(rest)
+
+	return rest == "" || rest[0] == '#'
+}
+
+// splitHeaderKey splits a block mapping line into its key and the rest of the line after the
+// colon. Keys may be plain or quoted without escapes.
+func splitHeaderKey(line string) (string, string, bool) {
+	if line[0] == '"' || line[0] == '\'' {
+		key, rest, ok := cutQuoted(line)
+		if !ok || !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+
+		rest = rest[1:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+
+		return key, rest, true
+	}
+
+	if strings.ContainsRune("-?:,[]{}#&*!|>@`%", rune(line[0])) {
+		return "", "", false
+	}
+
+	key, rest, found := strings.Cut(line, ": ")
+	if !found {
+		key, found = strings.CutSuffix(line, ":")
+		if !found {
+			return "", "", false
+		}
+	}
+
+	if strings.Contains(key, " #") {
+		return "", "", false
+	}
+
+	return strings.TrimRight(key, " "), rest, true
+}
+
+// parseHeaderScalar parses the value of a header field: a plain scalar or a quoted scalar
+// without escapes, followed by an optional comment. Null values are returned as "".
+func parseHeaderScalar(rest string) (string, bool) {
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return "", true
+	}
+
+	if rest[0] == '"' || rest[0] == '\'' {
+		value, after, ok := cutQuoted(rest)
+		if !ok || !isEmptyValue(after) {
+			return "", false
+		}
+
+		return value, true
+	}
+
+	if strings.ContainsRune("-?:,[]{}#&*!|>@`%", rune(rest[0])) {
+		return "", false
+	}
+
+	if i := strings.Index(rest, " #"); i >= 0 {
+		rest = strings.TrimRight(rest[:i], " ")
+	}
+
+	if strings.Contains(rest, ": ") || strings.HasSuffix(rest, ":") {
+		return "", false
+	}
+
+	switch rest {
+	case "~", "null", "Null", "NULL":
+		return "", true
+	}
+
+	return rest, true
+}
+
+// cutQuoted splits a single- or double-quoted scalar at the start of s from the text after it.
+// Scalars with escape sequences or spanning lines are not handled.
+func cutQuoted(s string) (string, string, bool) {
+	quote := s[0]
+
+	end := strings.IndexByte(s[1:], quote)
+	if end < 0 {
+		return "", "", false
+	}
+
+	value, after := s[1:1+end], s[2+end:]
+
+	if quote == '"' && strings.Contains(value, "\\") {
+		return "", "", false
+	}
+
+	if quote == '\'' && strings.HasPrefix(after, "'") {
+		return "", "", false
+	}
+
+	return value, after, true
+}
+
+// objects fully decodes the document.
+func (d rawDocument) objects(decoder Decoder) ([]unstructured.Unstructured, error) {
+	return decodeYAML(decoder, d.data)
+}
+
+// splitDocuments splits a YAML stream into raw documents at "---" separators.
+func splitDocuments(content []byte) ([]rawDocument, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+
+	docs := make([]rawDocument, 0)
+
+	for {
+		data, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, rawDocument{data: data})
+	}
+}
+
+// decodeDocuments decodes YAML content into unstructured objects. With header filters, documents
+// are kept as raw bytes until the filters accepted their header, so rejected documents are never
+// converted. Documents whose header cannot be parsed are always decoded, so malformed content is
+// reported the same way with and without header filters.
+func (r *Renderer) decodeDocuments(ctx context.Context, content []byte) ([]unstructured.Unstructured, error) {
+	if len(r.opts.HeaderFilters) == 0 {
+		return decodeYAML(r.opts.Decoder, content)
+	}
+
+	docs, err := splitDocuments(normalizeYAML(content))
+	if err != nil {
+		return decodeYAML(r.opts.Decoder, content)
+	}
+
+	objects := make([]unstructured.Unstructured, 0, len(docs))
+
+	for _, doc := range docs {
+		if header, ok := doc.header(); ok {
+			// Documents without a GVK are skipped by the decoder anyway
+			if header.GetAPIVersion() == "" || header.GetKind() == "" {
+				continue
+			}
+
+			accepted, err := r.acceptHeader(ctx, header)
+			if err != nil {
+				return nil, err
+			}
+
+			if !accepted {
+				continue
+			}
+		}
+
+		decoded, err := doc.objects(r.opts.Decoder)
+		if err != nil {
+			// Decode the whole content again, so errors report positions in the file
+			if _, fullErr := decodeYAML(r.opts.Decoder, content); fullErr != nil {
+				return nil, fullErr
+			}
+
+			return nil, err
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return objects, nil
+}
+
+// acceptHeader reports whether all header filters accept a document header.
+func (r *Renderer) acceptHeader(ctx context.Context, header unstructured.Unstructured) (bool, error) {
+	for _, filter := range r.opts.HeaderFilters {
+		accepted, err := filter(ctx, header)
+		if err != nil {
+			return false, fmt.Errorf("header filter failed for %s %s: %w", header.GetKind(), header.GetName(), err)
+		}
+
+		if !accepted {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,204 @@
+package yaml_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/gvk"
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/labels"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const labeledPodsYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: frontend
+  labels:
+    tier: frontend
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: backend
+  labels:
+    tier: backend
+spec:
+  containers:
+  - name: app
+    image: app
+`
+
+func TestHeaderFilters(t *testing.T) {
+	t.Run("should only decode documents accepted by header filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		decoded := make([]string, 0)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"all.yaml": multiDocYAML + "---\n" + labeledPodsYAML},
+			yaml.WithHeaderFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+			yaml.WithHeaderFilter(labels.MatchLabels(map[string]string{"tier": "backend"})),
+			yaml.WithDocumentHook(func(_ context.Context, _ yaml.DocumentInfo, obj *unstructured.Unstructured) error {
+				decoded = append(decoded, obj.GetName())
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("backend"))
+		g.Expect(objects[0].Object).To(HaveKey("spec"))
+		g.Expect(decoded).To(Equal([]string{"backend"}))
+	})
+
+	t.Run("should report decode errors with positions in the file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"pod.yaml": podYAML + "---\napiVersion: v1\nkind: Pod\nmetadata:\n\tname: tabbed\n"},
+			yaml.WithHeaderFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("ConfigMap"))),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrTabIndentation))
+		g.Expect(err.Error()).To(ContainSubstring("line 17, column 1"))
+	})
+}
+
+// headerVariantsYAML covers header syntax handled by the header scanner and syntax it leaves to
+// a full parse.
+const headerVariantsYAML = `
+# leading comment
+apiVersion: v1 # trailing comment
+kind: "ConfigMap"
+metadata:
+  name: 'plain-quoted'
+  namespace: team-a
+  finalizers:
+  - example.com/cleanup
+  labels:
+    "app.kubernetes.io/name": web
+    tier: 'front # end'
+    release: v1.10 # comment
+  annotations:
+    note: "a: b"
+data:
+  script: |
+    kind: Secret
+    metadata:
+      name: inner
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: flow, labels: {tier: back}}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels: &labels
+    tier: anchored
+  name: "escaped\u0041"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: multi
+    line
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: empty-labels
+  labels:
+`
+
+func TestHeaderScanning(t *testing.T) {
+	t.Run("should read headers like a full parse", func(t *testing.T) {
+		g := NewWithT(t)
+
+		headers := make(map[string]unstructured.Unstructured)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"all.yaml": headerVariantsYAML},
+			yaml.WithHeaderFilter(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				headers[obj.GetName()] = obj
+
+				return true, nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(5))
+		g.Expect(headers).To(HaveLen(5))
+
+		for _, obj := range objects {
+			header := headers[obj.GetName()]
+			g.Expect(header.GetAPIVersion()).To(Equal(obj.GetAPIVersion()))
+			g.Expect(header.GetKind()).To(Equal(obj.GetKind()))
+			g.Expect(header.GetNamespace()).To(Equal(obj.GetNamespace()))
+			g.Expect(header.GetLabels()).To(Equal(obj.GetLabels()), obj.GetName())
+			g.Expect(header.GetAnnotations()).To(Equal(obj.GetAnnotations()), obj.GetName())
+		}
+
+		header := headers["plain-quoted"]
+		g.Expect(header.GetLabels()).To(Equal(map[string]string{
+			"app.kubernetes.io/name": "web",
+			"tier":                   "front # end",
+			"release":                "v1.10",
+		}))
+	})
+}
+
+func BenchmarkHeaderFilter(b *testing.B) {
+	var sb strings.Builder
+	for i := range 200 {
+		tier := "backend"
+		if i%20 == 0 {
+			tier = "frontend"
+		}
+
+		sb.WriteString(strings.ReplaceAll(labeledPodsYAML, "tier: backend", "tier: "+tier))
+		sb.WriteString("---\n")
+	}
+
+	files := map[string]string{"all.yaml": sb.String()}
+	selector := labels.MatchLabels(map[string]string{"tier": "frontend"})
+
+	for name, opt := range map[string]yaml.RendererOption{
+		"eager":  yaml.WithFilter(selector),
+		"header": yaml.WithHeaderFilter(selector),
+	} {
+		b.Run(name, func(b *testing.B) {
+			renderer, err := yaml.NewFromStrings(files, opt)
+			if err != nil {
+				b.Fatalf("failed to create renderer: %v", err)
+			}
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				if _, err := renderer.Process(b.Context(), nil); err != nil {
+					b.Fatalf("failed to render: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	goyaml "gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrLimitExceeded is returned when a file exceeds a configured resource limit.
+	ErrLimitExceeded = errors.New("limit exceeded")
+
+	// ErrMergeKeyNotAllowed is returned when a document uses a YAML merge key ("<<") and merge keys are disallowed.
+	ErrMergeKeyNotAllowed = newClassError(ErrParse, "YAML merge keys are not allowed")
+)
+
+// mergeTag is the resolved tag of a YAML merge key.
+const mergeTag = "!!merge"
+
+// checkFileSize verifies a file size against the configured maximum.
+func (r *Renderer) checkFileSize(size int64) error {
+	if r.opts.MaxFileSize > 0 && size > r.opts.MaxFileSize {
+		return fmt.Errorf("%w: file size %d exceeds %d bytes", ErrLimitExceeded, size, r.opts.MaxFileSize)
+	}
+
+	return nil
+}
+
+// limitReader bounds reads to one byte past the configured maximum file size,
+// so oversized files are detected without being read entirely.
+func (r *Renderer) limitReader(reader io.Reader) io.Reader {
+	if r.opts.MaxFileSize <= 0 {
+		return reader
+	}
+
+	return io.LimitReader(reader, r.opts.MaxFileSize+1)
+}
+
+// checkStructure scans YAML content, without expanding aliases, for the configured
+// document count, nesting depth, alias and merge key limits. It is a no-op if none are set.
+func (r *Renderer) checkStructure(content []byte) error {
+	if r.opts.MaxDocuments <= 0 && r.opts.MaxDepth <= 0 && r.opts.AllowAliases && r.opts.AllowMergeKeys {
+		return nil
+	}
+
+	dec := goyaml.NewDecoder(bytes.NewReader(normalizeYAML(content)))
+
+	for documents := 1; ; documents++ {
+		var node goyaml.Node
+
+		err := dec.Decode(&node)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			// Syntax errors are reported with more context by the regular decoder
+			return nil //nolint:nilerr // decoding errors are not limit violations
+		}
+
+		if r.opts.MaxDocuments > 0 && documents > r.opts.MaxDocuments {
+			return fmt.Errorf("%w: more than %d documents", ErrLimitExceeded, r.opts.MaxDocuments)
+		}
+
+		if err := r.checkNode(&node, 0); err != nil {
+			return fmt.Errorf("document %d: %w", documents-1, err)
+		}
+	}
+}
+
+// checkNode recursively checks a node's nesting depth, aliases and merge keys.
+func (r *Renderer) checkNode(node *goyaml.Node, depth int) error {
+	if node.Kind == goyaml.AliasNode && !r.opts.AllowAliases {
+		return fmt.Errorf("%w: line %d: YAML aliases are not allowed", ErrLimitExceeded, node.Line)
+	}
+
+	if node.Kind == goyaml.ScalarNode && node.Tag == mergeTag && !r.opts.AllowMergeKeys {
+		return fmt.Errorf("%w: line %d", ErrMergeKeyNotAllowed, node.Line)
+	}
+
+	if node.Kind == goyaml.MappingNode || node.Kind == goyaml.SequenceNode {
+		depth++
+
+		if r.opts.MaxDepth > 0 && depth > r.opts.MaxDepth {
+			return fmt.Errorf("%w: line %d: nesting depth exceeds %d", ErrLimitExceeded, node.Line, r.opts.MaxDepth)
+		}
+	}
+
+	for _, child := range node.Content {
+		if err := r.checkNode(child, depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package yaml_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const aliasYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: aliased
+  labels: &labels
+    app: test
+  annotations: *labels
+`
+
+const mergeKeyYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: merged
+defaults: &defaults
+  replicas: "1"
+  tier: backend
+data:
+  <<: *defaults
+  replicas: "3"
+`
+
+func TestMergeKeys(t *testing.T) {
+	testFS := fstest.MapFS{
+		"merge.yaml": &fstest.MapFile{Data: []byte(mergeKeyYAML)},
+	}
+
+	t.Run("should render merged mappings by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "merge.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(Equal(map[string]any{
+			"replicas": "3",
+			"tier":     "backend",
+		}))
+	})
+
+	t.Run("should reject merge keys when disallowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "merge.yaml"}},
+			yaml.WithAllowMergeKeys(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrMergeKeyNotAllowed))
+		g.Expect(err.Error()).To(ContainSubstring("line 10"))
+	})
+
+	t.Run("should accept plain anchors when merge keys are disallowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: fstest.MapFS{
+				"alias.yaml": &fstest.MapFile{Data: []byte(aliasYAML)},
+			}, Path: "alias.yaml"}},
+			yaml.WithAllowMergeKeys(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+func TestLimits(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"alias.yaml":     &fstest.MapFile{Data: []byte(aliasYAML)},
+	}
+
+	t.Run("should reject files larger than the maximum size", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithMaxFileSize(16),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLimitExceeded))
+		g.Expect(err.Error()).To(ContainSubstring("pod.yaml"))
+	})
+
+	t.Run("should reject files with too many documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "multi-doc.yaml"}},
+			yaml.WithMaxDocuments(1),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLimitExceeded))
+	})
+
+	t.Run("should reject documents nested too deeply", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithMaxDepth(3),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLimitExceeded))
+		g.Expect(err.Error()).To(ContainSubstring("nesting depth exceeds 3"))
+	})
+
+	t.Run("should reject aliases when disallowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "alias.yaml"}},
+			yaml.WithAllowAliases(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLimitExceeded))
+		g.Expect(err.Error()).To(ContainSubstring("line 8"))
+	})
+
+	t.Run("should render within limits", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithMaxFileSize(1024),
+			yaml.WithMaxDocuments(2),
+			yaml.WithMaxDepth(8),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+	})
+}
+
+func TestSecurityProfiles(t *testing.T) {
+
+	t.Run("should deny cluster-wide RBAC in the strict profile", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"binding.yaml": `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: admin-binding
+`},
+			yaml.SecurityProfileStrict(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrDeniedKind))
+	})
+
+	t.Run("should reject aliases in the strict profile", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"alias.yaml": aliasYAML},
+			yaml.SecurityProfileStrict(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLimitExceeded))
+	})
+
+	t.Run("should let later options override the profile", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"alias.yaml": aliasYAML},
+			yaml.SecurityProfileStrict(),
+			yaml.WithAllowAliases(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should bound file size in the baseline profile", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"large.yaml": podYAML + strings.Repeat("#", 10<<20)},
+			yaml.SecurityProfileBaseline(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLimitExceeded))
+	})
+}
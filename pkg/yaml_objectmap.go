@@ -0,0 +1,68 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ErrDuplicateObject is returned by RenderMap when several rendered objects share an ObjectKey.
+var ErrDuplicateObject = errors.New("duplicate object")
+
+// ObjectKey identifies a rendered object by GroupVersionKind, namespace and name.
+type ObjectKey struct {
+	schema.GroupVersionKind
+
+	Namespace string
+	Name      string
+}
+
+// KeyOf returns the ObjectKey of obj.
+func KeyOf(obj unstructured.Unstructured) ObjectKey {
+	return ObjectKey{
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+	}
+}
+
+// String returns the key as "group/version, Kind=kind namespace/name", omitting an empty namespace.
+func (k ObjectKey) String() string {
+	if k.Namespace == "" {
+		return fmt.Sprintf("%s %s", k.GroupVersionKind, k.Name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", k.GroupVersionKind, k.Namespace, k.Name)
+}
+
+// RenderMap renders all configured inputs like Process and returns the objects keyed by
+// ObjectKey, the index most appliers build from the object list. Objects sharing a key fail the
+// render with ErrDuplicateObject, naming the source files of both when source annotations are
+// enabled.
+func (r *Renderer) RenderMap(ctx context.Context) (map[ObjectKey]unstructured.Unstructured, error) {
+	objects, err := r.Process(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[ObjectKey]unstructured.Unstructured, len(objects))
+
+	for _, obj := range objects {
+		key := KeyOf(obj)
+
+		if existing, ok := result[key]; ok {
+			if file, other := sourceFile(obj), sourceFile(existing); file != "" || other != "" {
+				return nil, r.labelError(fmt.Errorf("%w: %s in %s and %s", ErrDuplicateObject, key, other, file))
+			}
+
+			return nil, r.labelError(fmt.Errorf("%w: %s", ErrDuplicateObject, key))
+		}
+
+		result[key] = obj
+	}
+
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package yaml_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderMap(t *testing.T) {
+	t.Run("should key objects by identity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{
+			"pod.yaml":       podYAML,
+			"configmap.yaml": configMapYAML,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.RenderMap(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		key := yaml.ObjectKey{
+			GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Name:             "test-pod",
+		}
+		g.Expect(objects).To(HaveKey(key))
+		g.Expect(yaml.KeyOf(objects[key])).To(Equal(key))
+	})
+
+	t.Run("should reject duplicate objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{
+				"a/pod.yaml": podYAML,
+				"b/pod.yaml": podYAML,
+			},
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.RenderMap(t.Context())
+		g.Expect(err).To(MatchError(yaml.ErrDuplicateObject))
+		g.Expect(err).To(MatchError(ContainSubstring("/v1, Kind=Pod test-pod in a/pod.yaml and b/pod.yaml")))
+	})
+}
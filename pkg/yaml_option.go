@@ -1,9 +1,17 @@
 package yaml
 
 import (
+	"io"
+	"io/fs"
+	"maps"
+	"strings"
+	"time"
+
 	"github.com/k8s-manifest-kit/engine/pkg/types"
 	"github.com/k8s-manifest-kit/pkg/util"
 	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // RendererOption is a generic option for RendererOptions.
@@ -14,21 +22,307 @@ type RendererOptions struct {
 	// Filters are renderer-specific filters applied during Process().
 	Filters []types.Filter
 
+	// HeaderFilters are filters applied to the apiVersion, kind and metadata of each document
+	// before it is fully decoded.
+	HeaderFilters []types.Filter
+
 	// Transformers are post-processing transformers applied after YAML rendering.
 	Transformers []types.Transformer
 
+	// StagedTransformers are transformers assigned to a stage. Transformers without a stage
+	// run in StageMutate.
+	StagedTransformers []StagedTransformer
+
+	// TransformerErrorMode controls how transformer errors are handled (default abort).
+	TransformerErrorMode TransformerErrorMode
+
+	// DocumentHooks are called for every object right after it is decoded.
+	DocumentHooks []DocumentHook
+
+	// PreRenderHooks are called before sources are read.
+	PreRenderHooks []PreRenderHook
+
+	// PostRenderHooks are called after the object list is assembled.
+	PostRenderHooks []PostRenderHook
+
+	// ProvenanceHandlers receive the provenance document of every successful render.
+	ProvenanceHandlers []ProvenanceHandler
+
+	// EventListeners receive render pipeline events.
+	EventListeners []EventListener
+
+	// Validators are checks applied to rendered objects after filters and transformers.
+	Validators []Validator
+
+	// DeniedKinds are kinds rejected by the renderer ("Kind" or "Kind.group").
+	DeniedKinds []string
+
 	// CacheOptions holds cache configuration. nil = caching disabled.
 	CacheOptions *cache.Options
 
+	// PartialResults returns the objects rendered so far when the render deadline expires.
+	PartialResults bool
+
+	// SlowFileThreshold is the decode and transform time above which a file is reported with a
+	// slow-file warning. 0 = disabled.
+	SlowFileThreshold time.Duration
+
+	// SourceConcurrency is the maximum number of sources rendered concurrently. 0 or 1 = sequential.
+	SourceConcurrency int
+
+	// ServeStale serves the last result of a cached source when reading it fails.
+	ServeStale bool
+
+	// StaleWhileRevalidate serves expired results of cached sources while refreshing them in the
+	// background.
+	StaleWhileRevalidate bool
+
+	// CacheDeepCopy deep-copies objects stored in and returned from the cache (default true).
+	// Disabling it avoids the copies but shares cached objects with callers and transformers.
+	CacheDeepCopy bool
+
+	// CacheCompression stores cached results gzip-compressed, decoding them on every hit.
+	CacheCompression bool
+
+	// CacheMaxBytes caps the estimated size of each cache, evicting least recently used entries.
+	// 0 = unlimited.
+	CacheMaxBytes int64
+
+	// Label identifies the renderer instance in errors, events, reports and source annotations.
+	Label string
+
 	// SourceAnnotations enables automatic addition of source tracking annotations.
 	SourceAnnotations bool
+
+	// SourcePrefix is a directory prefix removed from the source file annotation.
+	SourcePrefix string
+
+	// PipelineValues are values added to the render context, keyed by *ContextKey.
+	// Use WithPipelineContext to set them with type checking.
+	PipelineValues map[any]any
+
+	// WarningHandler receives non-fatal issues reported during Process(). nil = warnings discarded.
+	WarningHandler WarningHandler
+
+	// KubernetesVersion is the target Kubernetes version used by version-aware checks (e.g. "1.29").
+	KubernetesVersion string
+
+	// DeprecatedAPIPolicy controls detection of deprecated and removed apiVersions.
+	// PolicyIgnore (default) disables the check.
+	DeprecatedAPIPolicy Policy
+
+	// SchemaBundles holds schema bundles validating objects offline (see LoadSchemaBundles).
+	SchemaBundles fs.FS
+
+	// VersionSkewPolicy controls detection of apiVersions and fields not served by KubernetesVersion.
+	VersionSkewPolicy Policy
+
+	// SkipHidden excludes dotfiles, dot-directories and editor backup files from matched files.
+	SkipHidden bool
+
+	// IgnoreFile is the name of a gitignore-style file at the source root listing paths to exclude.
+	// Empty = no ignore file.
+	IgnoreFile string
+
+	// TargetSelector keeps only objects whose labels or annotations match this label selector
+	// expression. Empty = no selection.
+	TargetSelector string
+
+	// IgnoreAnnotation drops documents annotated with AnnotationIgnore set to "true".
+	IgnoreAnnotation bool
+
+	// ChecksumFile is the name of a sha256sum-style manifest at the source root. When present,
+	// every matched file must be listed with a matching digest. Empty = no verification.
+	ChecksumFile string
+
+	// RequireChecksums fails the render when the checksum manifest is missing.
+	RequireChecksums bool
+
+	// CaseInsensitive makes source patterns and the YAML extension check ignore ASCII letter case.
+	CaseInsensitive bool
+
+	// WindowsPaths treats backslashes in source paths as separators and strips drive letters.
+	WindowsPaths bool
+
+	// RequireMatch makes a source pattern matching zero files fail the render with ErrNoFilesMatched.
+	// When false, a warning is reported instead.
+	RequireMatch bool
+
+	// AllowedExtensions are the file extensions rendered from matched files (e.g. ".yaml").
+	// Defaults to DefaultAllowedExtensions(); an empty list keeps the defaults.
+	AllowedExtensions []string
+
+	// ExtensionPolicy controls handling of matched files without an allowed extension.
+	// PolicyIgnore (default) skips silently, PolicyWarn skips with a warning, PolicyError fails the render.
+	ExtensionPolicy Policy
+
+	// BinaryFilePolicy controls handling of matched files that are binary or not valid UTF-8.
+	// PolicyError (default) fails the render, PolicyWarn skips with a warning, PolicyIgnore skips silently.
+	BinaryFilePolicy Policy
+
+	// EmptyDocumentPolicy controls handling of empty, null and comment-only documents.
+	// PolicyIgnore (default) skips silently, PolicyWarn skips with a warning, PolicyError fails the render.
+	EmptyDocumentPolicy Policy
+
+	// Decoder converts YAML streams to unstructured objects. nil keeps the current decoder
+	// (YAMLv3Decoder by default).
+	Decoder Decoder
+
+	// ScalarResolution controls how plain scalars that differ between YAML 1.1 and 1.2 are interpreted.
+	// ScalarResolutionKubernetes (default) follows kubectl and the API server.
+	ScalarResolution ScalarResolution
+
+	// AmbiguousScalarPolicy controls reporting of plain scalars that differ between YAML 1.1 and 1.2.
+	// PolicyWarn (default) reports a warning, PolicyError fails the render, PolicyIgnore is silent.
+	AmbiguousScalarPolicy Policy
+
+	// LossyNumberPolicy controls reporting of numbers that cannot be represented exactly.
+	// PolicyWarn (default) reports a warning, PolicyError fails the render, PolicyIgnore is silent.
+	LossyNumberPolicy Policy
+
+	// PreserveLossyNumbers decodes numbers that cannot be represented exactly as strings.
+	PreserveLossyNumbers bool
+
+	// MaxFileSize is the maximum size in bytes of a matched file. 0 = unlimited.
+	MaxFileSize int64
+
+	// MaxDocuments is the maximum number of YAML documents in a single file. 0 = unlimited.
+	MaxDocuments int
+
+	// MaxDepth is the maximum nesting depth of mappings and sequences in a document. 0 = unlimited.
+	MaxDepth int
+
+	// AllowAliases permits YAML anchors and aliases (default true).
+	AllowAliases bool
+
+	// AllowMergeKeys permits YAML merge keys ("<<") (default true).
+	AllowMergeKeys bool
+
+	// Redact removes data and stringData values of sensitive kinds from render errors (default true).
+	Redact bool
+
+	// SourceExcerpts adds annotated source lines to decoding and validation errors.
+	SourceExcerpts bool
+
+	// SensitiveKinds are the kinds whose data and stringData values are redacted.
+	// Defaults to DefaultSensitiveKinds().
+	SensitiveKinds []schema.GroupKind
+
+	// Retry configures retries of transient read failures. nil = no retries.
+	Retry *RetryPolicy
+
+	// Originals records original YAML nodes for format-preserving output. nil = disabled.
+	Originals *Originals
 }
 
 // ApplyTo applies the renderer options to the target configuration.
+// Pipeline lists (filters, transformers, staged transformers, validators, denied kinds, hooks, listeners and
+// provenance handlers) are appended to the target's, so several RendererOptions compose.
+// Fields left at their zero value (false, 0, empty strings, nil lists) leave the target
+// unchanged, so a partial RendererOptions keeps the defaults of New. Use the corresponding With*
+// option to reset a setting to its zero value, e.g. WithRequireMatch(false).
 func (opts RendererOptions) ApplyTo(target *RendererOptions) {
-	target.Filters = opts.Filters
-	target.Transformers = opts.Transformers
-	target.SourceAnnotations = opts.SourceAnnotations
+	target.Filters = append(target.Filters, opts.Filters...)
+	target.HeaderFilters = append(target.HeaderFilters, opts.HeaderFilters...)
+	target.Transformers = append(target.Transformers, opts.Transformers...)
+	target.StagedTransformers = append(target.StagedTransformers, opts.StagedTransformers...)
+	target.Validators = append(target.Validators, opts.Validators...)
+	target.DeniedKinds = append(target.DeniedKinds, opts.DeniedKinds...)
+	target.DocumentHooks = append(target.DocumentHooks, opts.DocumentHooks...)
+	target.PreRenderHooks = append(target.PreRenderHooks, opts.PreRenderHooks...)
+	target.PostRenderHooks = append(target.PostRenderHooks, opts.PostRenderHooks...)
+	target.EventListeners = append(target.EventListeners, opts.EventListeners...)
+	target.ProvenanceHandlers = append(target.ProvenanceHandlers, opts.ProvenanceHandlers...)
+	override(&target.TransformerErrorMode, opts.TransformerErrorMode)
+	override(&target.PartialResults, opts.PartialResults)
+	override(&target.SourceConcurrency, opts.SourceConcurrency)
+	override(&target.SlowFileThreshold, opts.SlowFileThreshold)
+	override(&target.ServeStale, opts.ServeStale)
+	override(&target.StaleWhileRevalidate, opts.StaleWhileRevalidate)
+	override(&target.CacheDeepCopy, opts.CacheDeepCopy)
+	override(&target.CacheCompression, opts.CacheCompression)
+	override(&target.SourceExcerpts, opts.SourceExcerpts)
+	override(&target.CacheMaxBytes, opts.CacheMaxBytes)
+	override(&target.SourceAnnotations, opts.SourceAnnotations)
+	override(&target.DeprecatedAPIPolicy, opts.DeprecatedAPIPolicy)
+	override(&target.VersionSkewPolicy, opts.VersionSkewPolicy)
+	if opts.SchemaBundles != nil {
+		target.SchemaBundles = opts.SchemaBundles
+	}
+	override(&target.BinaryFilePolicy, opts.BinaryFilePolicy)
+	override(&target.ExtensionPolicy, opts.ExtensionPolicy)
+	override(&target.EmptyDocumentPolicy, opts.EmptyDocumentPolicy)
+	if opts.Decoder != nil {
+		target.Decoder = opts.Decoder
+	}
+	override(&target.ScalarResolution, opts.ScalarResolution)
+	override(&target.AmbiguousScalarPolicy, opts.AmbiguousScalarPolicy)
+	override(&target.LossyNumberPolicy, opts.LossyNumberPolicy)
+	override(&target.PreserveLossyNumbers, opts.PreserveLossyNumbers)
+	override(&target.SkipHidden, opts.SkipHidden)
+	override(&target.IgnoreAnnotation, opts.IgnoreAnnotation)
+	override(&target.RequireChecksums, opts.RequireChecksums)
+	override(&target.RequireMatch, opts.RequireMatch)
+	override(&target.CaseInsensitive, opts.CaseInsensitive)
+	override(&target.WindowsPaths, opts.WindowsPaths)
+	override(&target.MaxFileSize, opts.MaxFileSize)
+	override(&target.MaxDocuments, opts.MaxDocuments)
+	override(&target.MaxDepth, opts.MaxDepth)
+	override(&target.AllowAliases, opts.AllowAliases)
+	override(&target.AllowMergeKeys, opts.AllowMergeKeys)
+	override(&target.Redact, opts.Redact)
+
+	if opts.Label != "" {
+		target.Label = opts.Label
+	}
+
+	if opts.SourcePrefix != "" {
+		target.SourcePrefix = opts.SourcePrefix
+	}
+
+	if opts.KubernetesVersion != "" {
+		target.KubernetesVersion = opts.KubernetesVersion
+	}
+
+	if opts.IgnoreFile != "" {
+		target.IgnoreFile = opts.IgnoreFile
+	}
+
+	if opts.TargetSelector != "" {
+		target.TargetSelector = opts.TargetSelector
+	}
+
+	if opts.ChecksumFile != "" {
+		target.ChecksumFile = opts.ChecksumFile
+	}
+
+	if len(opts.AllowedExtensions) > 0 {
+		target.AllowedExtensions = opts.AllowedExtensions
+	}
+
+	if opts.SensitiveKinds != nil {
+		target.SensitiveKinds = opts.SensitiveKinds
+	}
+
+	if len(opts.PipelineValues) > 0 {
+		if target.PipelineValues == nil {
+			target.PipelineValues = make(map[any]any, len(opts.PipelineValues))
+		}
+
+		maps.Copy(target.PipelineValues, opts.PipelineValues)
+	}
+
+	if opts.WarningHandler != nil {
+		target.WarningHandler = opts.WarningHandler
+	}
+
+	if opts.Originals != nil {
+		target.Originals = opts.Originals
+	}
+
+	if opts.Retry != nil {
+		target.Retry = opts.Retry
+	}
 
 	if opts.CacheOptions != nil {
 		if target.CacheOptions == nil {
@@ -38,6 +332,14 @@ func (opts RendererOptions) ApplyTo(target *RendererOptions) {
 	}
 }
 
+// override sets *target to value unless value is the zero value, i.e. was not set.
+func override[T comparable](target *T, value T) {
+	var zero T
+	if value != zero {
+		*target = value
+	}
+}
+
 // WithFilter adds a renderer-specific filter to this YAML renderer's processing chain.
 // Renderer-specific filters are applied during Process(), before results are returned to the engine.
 // For engine-level filtering applied to all renderers, use engine.WithFilter.
@@ -47,6 +349,31 @@ func WithFilter(filter types.Filter) RendererOption {
 	})
 }
 
+// WithFilters adds renderer-specific filters to this YAML renderer's processing chain, in order.
+// It is equivalent to one WithFilter option per filter.
+func WithFilters(filters ...types.Filter) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Filters = append(opts.Filters, filters...)
+	})
+}
+
+// WithHeaderFilter adds a filter evaluated before documents are decoded. The filter receives an
+// object holding only the apiVersion, kind and metadata (name, namespace, labels, annotations) of
+// a document as written in the source, and documents it rejects are never converted to
+// unstructured objects. Filters that only look at the GVK or metadata, such as the engine's gvk,
+// labels, name and namespace filters, can be used here to save the conversion of unselected
+// objects. Checks running before decoding (limits, scalar resolution) still parse whole files,
+// so the saving is a fraction of the render time (see BenchmarkHeaderFilter).
+//
+// Example:
+//
+//	yaml.WithHeaderFilter(gvk.Filter(appsv1.SchemeGroupVersion.WithKind("Deployment")))
+func WithHeaderFilter(filter types.Filter) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.HeaderFilters = append(opts.HeaderFilters, filter)
+	})
+}
+
 // WithTransformer adds a renderer-specific transformer to this YAML renderer's processing chain.
 // Renderer-specific transformers are applied during Process(), before results are returned to the engine.
 // For engine-level transformation applied to all renderers, use engine.WithTransformer.
@@ -56,6 +383,150 @@ func WithTransformer(transformer types.Transformer) RendererOption {
 	})
 }
 
+// WithTransformers adds renderer-specific transformers to this YAML renderer's processing chain,
+// in order. It is equivalent to one WithTransformer option per transformer.
+func WithTransformers(transformers ...types.Transformer) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Transformers = append(opts.Transformers, transformers...)
+	})
+}
+
+// WithStagedTransformer adds renderer-specific transformers to the given stage. Transformers run
+// in ascending stage order (StageSanitize, StageMutate, StageFinalize), and in registration order
+// within a stage, so the outcome does not depend on the order in which options from different
+// packages are composed. Transformers added with WithTransformer run in StageMutate.
+//
+// Example:
+//
+//	yaml.WithStagedTransformer(yaml.StageFinalize, hashTransformer)
+//	yaml.WithStagedTransformer(yaml.StageSanitize, stripStatus)
+func WithStagedTransformer(stage Stage, transformers ...types.Transformer) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		for _, t := range transformers {
+			opts.StagedTransformers = append(opts.StagedTransformers, StagedTransformer{Stage: stage, Transformer: t})
+		}
+	})
+}
+
+// WithTransformerErrorMode controls how renderer transformer errors are handled. By default
+// (TransformerErrorAbort) a failing transformer aborts the render. TransformerErrorSkipTransformer
+// keeps the object as it was before the failing transformer and continues with the next one, and
+// TransformerErrorSkipObject drops the object; both report a "transformer-error" warning.
+// Use a skipping mode for best-effort enrichment that must not block a deployment.
+func WithTransformerErrorMode(mode TransformerErrorMode) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.TransformerErrorMode = mode
+	})
+}
+
+// WithPipelineContext adds a value to the context passed to filters, transformers, validators
+// and hooks during Process(), e.g. cluster information, tenant IDs or feature flags. Read it with
+// PipelineValue using the same key. Setting a key again replaces its value.
+//
+// Example:
+//
+//	var TenantKey = yaml.NewContextKey[string]("tenant")
+//	yaml.WithPipelineContext(TenantKey, "team-a")
+func WithPipelineContext[T any](key *ContextKey[T], value T) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		if opts.PipelineValues == nil {
+			opts.PipelineValues = make(map[any]any)
+		}
+
+		opts.PipelineValues[key] = value
+	})
+}
+
+// WithValidator adds a validator to this YAML renderer's processing chain.
+// Validators run during Process(), after renderer-specific filters and transformers,
+// and cause the render to fail if any object is rejected.
+func WithValidator(validator Validator) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Validators = append(opts.Validators, validator)
+	})
+}
+
+// WithDeniedKinds rejects objects of the given kinds, failing the render with ErrDeniedKind.
+// Kinds are written as "Kind" (any group) or "Kind.group". The check runs on the final objects,
+// after filters and transformers, and before other validators.
+//
+// Example:
+//
+//	yaml.WithDeniedKinds("Secret", "ClusterRoleBinding.rbac.authorization.k8s.io")
+func WithDeniedKinds(kinds ...string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.DeniedKinds = append(opts.DeniedKinds, kinds...)
+	})
+}
+
+// WithDocumentHook adds a hook called for every object right after it is decoded from a file,
+// before renderer-specific filters and transformers. Hooks run in the order they are added.
+func WithDocumentHook(hook DocumentHook) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.DocumentHooks = append(opts.DocumentHooks, hook)
+	})
+}
+
+// WithPreRenderHook adds a hook called at the start of every Process call, before sources are read.
+// Hooks run in the order they are added; the first error aborts the render.
+func WithPreRenderHook(hook PreRenderHook) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.PreRenderHooks = append(opts.PreRenderHooks, hook)
+	})
+}
+
+// WithPostRenderHook adds a hook called at the end of every Process call with the rendered
+// objects and a RenderReport. Hooks run in the order they are added, also when the render fails.
+func WithPostRenderHook(hook PostRenderHook) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.PostRenderHooks = append(opts.PostRenderHooks, hook)
+	})
+}
+
+// WithAuditLog appends an audit record (JSON lines) for every object emitted by a successful
+// render to w: GVK, namespace, name, source and content digest. See AuditLog.
+//
+// Example:
+//
+//	f, _ := os.OpenFile("audit.jsonl", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+//	yaml.WithAuditLog(f)
+func WithAuditLog(w io.Writer) RendererOption {
+	return WithPostRenderHook(AuditLog(w))
+}
+
+// WithExpvar publishes render counters (renders, errors, cache hits, last render duration) as
+// the expvar map name. See ExpvarMetrics.
+//
+// Example:
+//
+//	yaml.WithExpvar("renderer_yaml")
+func WithExpvar(name string) RendererOption {
+	return WithPostRenderHook(ExpvarMetrics(name))
+}
+
+// WithProvenance generates an SLSA v1 provenance document (an in-toto statement) for every
+// successful render and passes it to handler. Enabling provenance records the digest of every
+// input file read, which are listed as resolved dependencies; rendered objects are the subjects.
+//
+// Example:
+//
+//	yaml.WithProvenance(func(_ context.Context, p yaml.Provenance) error {
+//		return json.NewEncoder(f).Encode(p)
+//	})
+func WithProvenance(handler ProvenanceHandler) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ProvenanceHandlers = append(opts.ProvenanceHandlers, handler)
+	})
+}
+
+// WithEventListener registers a listener receiving structured render pipeline events
+// (RenderStarted, FileMatched, FileParsed, ObjectEmitted, RenderFinished).
+func WithEventListener(listener EventListener) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.EventListeners = append(opts.EventListeners, listener)
+	})
+}
+
 // WithCache enables render result caching with the specified options.
 // If no options are provided, uses default TTL of 5 minutes.
 // By default, caching is NOT enabled.
@@ -76,6 +547,104 @@ func WithCache(opts ...cache.Option) RendererOption {
 	})
 }
 
+// WithPartialResults controls whether a render whose context deadline expires returns the
+// objects of the sources rendered so far (default false). Process then returns them together
+// with an *IncompleteRenderError listing the remaining sources, so time-sliced batch jobs keep
+// their progress. Cancellation without a deadline still fails the render.
+func WithPartialResults(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.PartialResults = enabled
+	})
+}
+
+// WithSourceConcurrency renders up to n sources concurrently (default 1, sequential), for
+// renderers with many sources such as large multi-source platform repositories. Objects and
+// source reports are merged in source order, so the output matches a sequential render, and the
+// first failing source in that order is reported. Filters, transformers, validators, hooks and
+// handlers are then called concurrently and must be safe for concurrent use.
+func WithSourceConcurrency(n int) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SourceConcurrency = n
+	})
+}
+
+// WithSlowFileThreshold reports a "slow-file" warning for every file whose decode and transform
+// time exceeds d, to find the manifest slowing down every render. Timings of all files are
+// listed in SourceReport.Timings regardless. Default: 0 (disabled).
+func WithSlowFileThreshold(d time.Duration) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SlowFileThreshold = d
+	})
+}
+
+// WithServeStale controls whether a cached source whose read fails (e.g. during a remote outage)
+// is served from its last successful result, even if the cache entry expired, instead of failing
+// the render (default false). Each stale result is reported as a "stale-result" warning. Only
+// sources with a cache (WithCache or Source.Cache) keep results to serve; cancellation is never
+// masked. The last result of every source is retained in memory in addition to the cache; only
+// the latest cache key of a source is kept, and with WithCacheMaxBytes the retained copy counts
+// toward the cap and is dropped if it does not fit.
+func WithServeStale(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ServeStale = enabled
+	})
+}
+
+// WithStaleWhileRevalidate controls whether expired cache entries are served immediately while
+// a background goroutine re-reads the source and replaces the entry (default false). Renders in
+// hot paths then never wait for a source read once it was cached, at the price of returning
+// results up to one refresh behind. Only one refresh runs per source at a time; refresh failures
+// are reported as "revalidate-error" warnings and the expired result keeps being served.
+// Like WithServeStale, the last result of every cached source is retained in memory.
+func WithStaleWhileRevalidate(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.StaleWhileRevalidate = enabled
+	})
+}
+
+// WithCacheDeepCopy controls whether cached render results are deep-copied (default true).
+// Copying keeps the cache isolated from callers that mutate returned objects.
+// Only disable it when all renderer transformers and every consumer of the results treat
+// objects as read-only; otherwise mutations leak into subsequent renders.
+func WithCacheDeepCopy(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.CacheDeepCopy = enabled
+	})
+}
+
+// WithCacheCompression controls whether cached render results are stored gzip-compressed
+// (default false), using the encoding of MarshalCacheEntry. It trades CPU on every cache hit and
+// store for a much smaller memory footprint, e.g. for catalogs caching thousands of large
+// manifests. Hits decode fresh objects, so WithCacheDeepCopy has no effect. Entries that fail to
+// encode are not cached.
+func WithCacheCompression(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.CacheCompression = enabled
+	})
+}
+
+// WithCacheMaxBytes caps the estimated memory held by each cache (the renderer cache and every
+// Source.Cache) at maxBytes, evicting the least recently used entries when a new entry does not
+// fit. Results larger than the cap are not cached. Sizes are estimated from the decoded objects,
+// or are the exact entry sizes with WithCacheCompression. Default: unlimited.
+func WithCacheMaxBytes(maxBytes int64) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.CacheMaxBytes = maxBytes
+	})
+}
+
+// WithLabel sets a label identifying this renderer instance, so engines with several YAML
+// renderers can attribute results to the instance that produced them. Errors returned by
+// Process are prefixed with the label, events and render reports carry it, and with source
+// annotations enabled objects are annotated with AnnotationSourceLabel.
+// Name still returns RendererType.
+// Default: none.
+func WithLabel(label string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Label = label
+	})
+}
+
 // WithSourceAnnotations enables or disables automatic addition of source tracking annotations.
 // When enabled, the renderer adds metadata annotations to track the source type and file path.
 // Annotations added: k8s-manifest-kit.io/source.type, source.file.
@@ -85,3 +654,388 @@ func WithSourceAnnotations(enabled bool) RendererOption {
 		opts.SourceAnnotations = enabled
 	})
 }
+
+// WithSourcePrefix removes a directory prefix from the source file annotation added by
+// WithSourceAnnotations, so annotations show repository-relative paths instead of paths that
+// depend on where the filesystem is rooted (e.g. with os.DirFS("/")).
+// Files outside the prefix keep their full path.
+//
+// Example:
+//
+//	yaml.WithSourcePrefix("home/ci/checkout/repo")
+//	// home/ci/checkout/repo/apps/pod.yaml is annotated as apps/pod.yaml
+func WithSourcePrefix(prefix string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SourcePrefix = prefix
+	})
+}
+
+// WithWarningHandler sets the handler receiving non-fatal issues reported during Process(),
+// such as deprecated API usage. By default, warnings are discarded.
+func WithWarningHandler(handler WarningHandler) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.WarningHandler = handler
+	})
+}
+
+// WithKubernetesVersion sets the target Kubernetes version (e.g. "1.29" or "v1.29.3")
+// used by version-aware checks such as WithDeprecatedAPIPolicy and WithVersionSkewPolicy.
+func WithKubernetesVersion(version string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.KubernetesVersion = version
+	})
+}
+
+// WithDeprecatedAPIPolicy enables detection of deprecated and removed apiVersions
+// using the built-in DeprecatedAPIs() data and the version set via WithKubernetesVersion.
+// Deprecated APIs are always reported as warnings; removed APIs are reported as warnings
+// with PolicyWarn or fail the render with PolicyError.
+// Default: PolicyIgnore (disabled).
+func WithDeprecatedAPIPolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.DeprecatedAPIPolicy = policy
+	})
+}
+
+// WithVersionSkewPolicy enables detection of apiVersions and fields not served by the version set
+// via WithKubernetesVersion, which is required: APIs and fields introduced later (the built-in
+// APIAvailabilities() and FieldAvailabilities() data) and APIs removed earlier (DeprecatedAPIs()).
+// Violations are reported as warnings with PolicyWarn or fail the render with PolicyError.
+// Combined with WithDeprecatedAPIPolicy, removed APIs are reported by the deprecated API check only.
+// Default: PolicyIgnore (disabled).
+func WithVersionSkewPolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.VersionSkewPolicy = policy
+	})
+}
+
+// WithSchemaBundles validates objects against the schema bundle in fsys matching the version set
+// via WithKubernetesVersion (see LoadSchemaBundles and SelectSchemaBundle), so schema validation
+// works fully offline. Bundles are typically embedded in the application with go:embed; the
+// renderer ships none. Violations fail the render with ErrSchemaViolation.
+//
+// Example:
+//
+//	//go:embed schemas/*.json
+//	var schemas embed.FS
+//
+//	sub, _ := fs.Sub(schemas, "schemas")
+//	yaml.WithKubernetesVersion("1.29"), yaml.WithSchemaBundles(sub)
+func WithSchemaBundles(fsys fs.FS) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SchemaBundles = fsys
+	})
+}
+
+// WithOriginals records the original YAML nodes of every decoded document into originals,
+// so that writers configured with WithPreservedFormatting can keep comments and key order
+// for documents passed through unmodified.
+// Default: disabled.
+func WithOriginals(originals *Originals) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Originals = originals
+	})
+}
+
+// WithAllowedExtensions sets the file extensions rendered from matched files, replacing the
+// defaults. Extensions are compared including the leading dot, which is added if missing;
+// JSON files (".json") decode like YAML. Use WithExtensionPolicy to control how other matched
+// files are handled. Without extensions, the defaults are restored, as an empty list would skip
+// every file.
+// Default: DefaultAllowedExtensions() (".yaml", ".yml").
+func WithAllowedExtensions(extensions ...string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		if len(extensions) == 0 {
+			opts.AllowedExtensions = DefaultAllowedExtensions()
+
+			return
+		}
+
+		opts.AllowedExtensions = make([]string, 0, len(extensions))
+		for _, ext := range extensions {
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+
+			opts.AllowedExtensions = append(opts.AllowedExtensions, ext)
+		}
+	})
+}
+
+// WithExtensionPolicy controls how matched files without an allowed extension are handled,
+// e.g. a README.md matched by "config/*". PolicyIgnore skips the file silently, PolicyWarn skips
+// the file and reports a warning, PolicyError fails the render with ErrUnsupportedExtension.
+// Default: PolicyIgnore.
+func WithExtensionPolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ExtensionPolicy = policy
+	})
+}
+
+// WithEmptyDocumentPolicy controls how empty documents in multi-document files are handled:
+// a "---" separator followed by nothing or only comments, an explicit null ("null", "~") or an
+// empty mapping. PolicyIgnore skips them silently, PolicyWarn skips them and reports a warning
+// per document, PolicyError fails the render with ErrEmptyDocument naming the file and line.
+// Note that a trailing "---" at the end of a file also starts an empty document.
+// Default: PolicyIgnore.
+func WithEmptyDocumentPolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.EmptyDocumentPolicy = policy
+	})
+}
+
+// WithDecoder replaces the backend converting YAML streams to unstructured objects, e.g. to
+// instrument decoding or to plug in a decoder provided by the consumer. Backends should be
+// checked with yamltest.DecoderConformance. Default: YAMLv3Decoder.
+//
+// Example:
+//
+//	yaml.WithDecoder(yaml.DecoderFunc(func(content []byte) ([]unstructured.Unstructured, error) {
+//		decodedBytes.Add(int64(len(content)))
+//
+//		return yaml.YAMLv3Decoder{}.Decode(content)
+//	}))
+func WithDecoder(decoder Decoder) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Decoder = decoder
+	})
+}
+
+// WithScalarResolution controls how unquoted scalars whose meaning differs between YAML 1.1
+// and YAML 1.2 are interpreted: yes/no/on/off/y/n and numbers with a leading zero (0755).
+// ScalarResolutionKubernetes treats them as booleans and octal numbers, as kubectl does;
+// ScalarResolutionYAML12 treats them as strings and decimal numbers. Mapping keys are always strings,
+// and so are values in fields that only accept strings (labels, annotations, ConfigMap data and
+// binaryData, Secret data and stringData), where the API server would reject a boolean.
+// Default: ScalarResolutionKubernetes.
+func WithScalarResolution(resolution ScalarResolution) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ScalarResolution = resolution
+	})
+}
+
+// WithAmbiguousScalarPolicy controls how unquoted scalars whose meaning differs between YAML 1.1
+// and YAML 1.2 are reported. PolicyWarn reports a warning naming the file, line and both readings,
+// PolicyError fails the render with ErrAmbiguousScalar so such values must be quoted,
+// PolicyIgnore resolves them silently per WithScalarResolution.
+// Default: PolicyWarn.
+func WithAmbiguousScalarPolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.AmbiguousScalarPolicy = policy
+	})
+}
+
+// WithLossyNumberPolicy controls how unquoted numbers that cannot be represented exactly by the
+// int64 and float64 values of unstructured objects are reported, e.g. integers beyond int64 or
+// decimals with more significant digits than a float64 holds. Integers within int64 and quantity
+// strings ("500m", "1Gi") always survive unchanged. PolicyWarn reports a warning naming the file
+// and line, PolicyError fails the render with ErrLossyNumber, PolicyIgnore is silent.
+// Default: PolicyWarn.
+func WithLossyNumberPolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.LossyNumberPolicy = policy
+	})
+}
+
+// WithPreserveLossyNumbers decodes unquoted numbers that cannot be represented exactly as strings
+// holding the written digits, instead of rounding them to a float64 (or failing the conversion of
+// unsigned integers beyond int64). Fields typed as resource.Quantity accept either form.
+// Default: false.
+func WithPreserveLossyNumbers(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.PreserveLossyNumbers = enabled
+	})
+}
+
+// WithBinaryFilePolicy controls how matched files that are binary or not valid UTF-8 are handled.
+// PolicyError fails the render with ErrBinaryContent naming the file, PolicyWarn skips the file
+// and reports a warning, PolicyIgnore skips the file silently.
+// Default: PolicyError.
+func WithBinaryFilePolicy(policy Policy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.BinaryFilePolicy = policy
+	})
+}
+
+// WithSkipHidden excludes hidden files from rendering even when they match the glob pattern:
+// files or directories whose name starts with "." (e.g. .git, .pod.yaml.swp) and editor
+// backup files (pod.yaml~, #pod.yaml#).
+// Default: false (all matched files are rendered).
+func WithSkipHidden(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SkipHidden = enabled
+	})
+}
+
+// WithIgnoreFile sets the name of the gitignore-style ignore file read from the root of each
+// source filesystem. Matched files listed in it are excluded from rendering. Pass an empty
+// name to disable ignore file support.
+// Default: DefaultIgnoreFile (".renderignore"); a missing file excludes nothing.
+func WithIgnoreFile(name string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.IgnoreFile = name
+	})
+}
+
+// WithTargetSelector keeps only objects whose labels or annotations match a label selector
+// expression, so a single tree can serve several environments through metadata alone. Objects
+// are selected as decoded, before renderer filters and transformers; objects without the
+// selected keys do not match an equality selector. New fails on an invalid expression.
+//
+// Example:
+//
+//	yaml.WithTargetSelector("env=prod")
+//	yaml.WithTargetSelector("env in (prod,staging),!experimental")
+func WithTargetSelector(selector string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.TargetSelector = selector
+	})
+}
+
+// WithIgnoreAnnotation controls whether documents annotated with AnnotationIgnore set to "true"
+// are dropped right after decoding, before hooks, filters and transformers (default false). It
+// lets manifest authors disable resources without deleting files or changing renderer
+// configuration.
+func WithIgnoreAnnotation(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.IgnoreAnnotation = enabled
+	})
+}
+
+// WithChecksumFile sets the name of the sha256sum-style checksum manifest read from the root of
+// each source filesystem ("<sha256>  <path>" per line). When the manifest exists, every matched
+// file must be listed and match its digest, or the render fails with ErrMissingChecksum or
+// ErrChecksumMismatch. An empty name disables verification.
+// Default: DefaultChecksumFile ("SHA256SUMS").
+func WithChecksumFile(name string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ChecksumFile = name
+	})
+}
+
+// WithRequireChecksums makes a missing checksum manifest fail the render with ErrMissingChecksum,
+// instead of skipping verification.
+func WithRequireChecksums(required bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.RequireChecksums = required
+	})
+}
+
+// WithRequireMatch controls whether a source pattern matching zero files is an error.
+// When enabled, Process() fails with ErrNoFilesMatched so that a mistyped Path fails loudly.
+// When disabled, the source renders zero objects and a "no-match" warning is reported.
+// Default: true.
+func WithRequireMatch(required bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.RequireMatch = required
+	})
+}
+
+// WithCaseInsensitiveMatch enables case-insensitive matching of source path patterns and
+// file extensions, so that "*.yaml" also matches "POD.YAML" and "config.Yml".
+// Useful for sources originating from case-insensitive filesystems or third-party bundles.
+// Default: false.
+func WithCaseInsensitiveMatch(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.CaseInsensitive = enabled
+	})
+}
+
+// WithWindowsPaths enables normalization of Windows-style source paths: backslashes are
+// treated as path separators and converted to forward slashes, and drive letters (C:) and
+// leading separators are removed so that paths are resolved relative to the Source.FS root
+// (e.g. os.DirFS(`C:\`)). When enabled, backslashes cannot be used to escape glob metacharacters.
+// Default: true when running on Windows, false otherwise.
+func WithWindowsPaths(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.WindowsPaths = enabled
+	})
+}
+
+// WithMaxFileSize fails the render with ErrLimitExceeded for matched files larger than size bytes.
+// A value of 0 disables the limit.
+func WithMaxFileSize(size int64) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.MaxFileSize = size
+	})
+}
+
+// WithMaxDocuments fails the render with ErrLimitExceeded for files containing more than n YAML documents.
+// A value of 0 disables the limit.
+func WithMaxDocuments(n int) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.MaxDocuments = n
+	})
+}
+
+// WithMaxDepth fails the render with ErrLimitExceeded for documents whose mappings and sequences
+// are nested deeper than n levels. A value of 0 disables the limit.
+func WithMaxDepth(n int) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.MaxDepth = n
+	})
+}
+
+// WithAllowAliases controls whether YAML anchors and aliases are accepted (default true).
+// Disallowing them rules out alias expansion attacks ("billion laughs"); documents using
+// an alias fail the render with ErrLimitExceeded.
+func WithAllowAliases(allow bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.AllowAliases = allow
+	})
+}
+
+// WithAllowMergeKeys controls whether YAML merge keys ("<<: *anchor") are accepted (default true).
+// Merge keys are a YAML 1.1 extension: accepted documents render with the merged mappings, keys
+// set explicitly taking precedence over merged ones. Disallowing them keeps manifests portable to
+// YAML 1.2 parsers; documents using a merge key fail the render with ErrMergeKeyNotAllowed.
+func WithAllowMergeKeys(allow bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.AllowMergeKeys = allow
+	})
+}
+
+// WithRetry retries transient filesystem failures (stat, open, read) with exponential backoff,
+// so hiccups on network filesystems do not fail a render. Parse and validation errors are never
+// retried. Each retry is reported as a "read-retry" warning.
+//
+// Example:
+//
+//	yaml.WithRetry(yaml.DefaultRetryPolicy())
+func WithRetry(policy RetryPolicy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Retry = &policy
+	})
+}
+
+// WithSourceExcerpts controls whether decoding and validation errors include a few numbered lines
+// of the offending source, the failing line marked with ">" (default false). Errors are wrapped in
+// a *SourceExcerptError. Values of sensitive kinds are replaced with RedactedValue in excerpts
+// unless redaction is disabled. Validation excerpts locate the document by kind and name in the
+// files of the source, so objects renamed by transformers get none.
+func WithSourceExcerpts(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SourceExcerpts = enabled
+	})
+}
+
+// WithRedaction controls whether data and stringData values of sensitive kinds (Secrets by default)
+// are removed from decoding, hook, filter, transformer and validation errors (default true).
+// Redacted errors keep the original error in their chain for errors.Is and errors.As.
+func WithRedaction(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Redact = enabled
+	})
+}
+
+// WithSensitiveKinds adds kinds whose data and stringData values are redacted,
+// in addition to DefaultSensitiveKinds().
+//
+// Example:
+//
+//	yaml.WithSensitiveKinds(schema.GroupKind{Group: "example.com", Kind: "Credentials"})
+func WithSensitiveKinds(kinds ...schema.GroupKind) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SensitiveKinds = append(opts.SensitiveKinds, kinds...)
+	})
+}
@@ -0,0 +1,151 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+	goyaml "gopkg.in/yaml.v3"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxOriginalFiles caps the number of files whose documents Originals retains. When exceeded, the
+// documents of the least recently recorded file are dropped.
+const maxOriginalFiles = 10000
+
+// Originals records the original YAML nodes of decoded documents so that output writers can
+// re-emit documents passed through unmodified with their comments and mapping order intact.
+// Only the documents of the latest read of each file are retained, for up to 10000 files.
+//
+// Share a single instance between the renderer (WithOriginals) and the writer
+// (WithPreservedFormatting). Originals is safe for concurrent use.
+type Originals struct {
+	mu        sync.RWMutex
+	documents map[string]originalDocument
+
+	// files holds the identities recorded from each file, and order the files from least to
+	// most recently recorded.
+	files map[string][]string
+	order []string
+}
+
+type originalDocument struct {
+	file   string
+	node   *goyaml.Node
+	object unstructured.Unstructured
+}
+
+// NewOriginals creates an empty Originals registry.
+func NewOriginals() *Originals {
+	return &Originals{
+		documents: make(map[string]originalDocument),
+		files:     make(map[string][]string),
+	}
+}
+
+// record decodes content into YAML nodes and stores each Kubernetes document by object identity,
+// replacing the documents previously recorded for file.
+func (o *Originals) record(file string, content []byte) error {
+	dec := goyaml.NewDecoder(bytes.NewReader(normalizeYAML(content)))
+
+	documents := make(map[string]originalDocument)
+
+	for {
+		var node goyaml.Node
+
+		err := dec.Decode(&node)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return fmt.Errorf("failed to decode YAML node: %w", err)
+		}
+
+		var out map[string]any
+		if err := node.Decode(&out); err != nil {
+			return fmt.Errorf("failed to decode YAML node: %w", err)
+		}
+
+		kind, _ := out["kind"].(string)
+		apiVersion, _ := out["apiVersion"].(string)
+		if kind == "" || apiVersion == "" {
+			continue
+		}
+
+		obj, err := k8s.ToUnstructured(&out)
+		if err != nil {
+			return fmt.Errorf("failed to convert YAML node: %w", err)
+		}
+
+		documents[originalKey(*obj)] = originalDocument{file: file, node: &node, object: *obj}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.forget(file)
+
+	keys := make([]string, 0, len(documents))
+	for key, doc := range documents {
+		o.documents[key] = doc
+		keys = append(keys, key)
+	}
+
+	o.files[file] = keys
+	o.order = append(o.order, file)
+
+	for len(o.order) > maxOriginalFiles {
+		o.forget(o.order[0])
+	}
+
+	return nil
+}
+
+// forget drops the documents recorded for file, unless a later file recorded the same identity.
+// The caller must hold the write lock.
+func (o *Originals) forget(file string) {
+	keys, ok := o.files[file]
+	if !ok {
+		return
+	}
+
+	for _, key := range keys {
+		if o.documents[key].file == file {
+			delete(o.documents, key)
+		}
+	}
+
+	delete(o.files, file)
+
+	if i := slices.Index(o.order, file); i >= 0 {
+		o.order = slices.Delete(o.order, i, i+1)
+	}
+}
+
+// lookup returns the original node for obj if obj is semantically unchanged since it was decoded.
+func (o *Originals) lookup(obj unstructured.Unstructured) (*goyaml.Node, bool) {
+	if o == nil {
+		return nil, false
+	}
+
+	o.mu.RLock()
+	doc, ok := o.documents[originalKey(obj)]
+	o.mu.RUnlock()
+
+	if !ok || !equality.Semantic.DeepEqual(doc.object.Object, obj.Object) {
+		return nil, false
+	}
+
+	return doc.node, true
+}
+
+func originalKey(obj unstructured.Unstructured) string {
+	return obj.GetAPIVersion() + "/" + obj.GetKind() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,98 @@
+package yaml_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/labels"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const commentedYAML = `# Application config
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: commented
+data:
+  # port used by the server
+  port: "8080"
+`
+
+func TestPreservedFormatting(t *testing.T) {
+	testFS := fstest.MapFS{
+		"config.yaml": &fstest.MapFile{Data: []byte(commentedYAML)},
+	}
+
+	t.Run("should preserve comments and key order for unmodified documents", func(t *testing.T) {
+		g := NewWithT(t)
+		originals := yaml.NewOriginals()
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithOriginals(originals),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = yaml.Write(objects, &buf, yaml.WithPreservedFormatting(originals))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(buf.String()).To(Equal(commentedYAML))
+	})
+
+	t.Run("should re-encode modified documents", func(t *testing.T) {
+		g := NewWithT(t)
+		originals := yaml.NewOriginals()
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithOriginals(originals),
+			yaml.WithTransformer(labels.Set(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = yaml.Write(objects, &buf, yaml.WithPreservedFormatting(originals))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(buf.String()).To(HavePrefix("apiVersion: v1\n"))
+		g.Expect(buf.String()).ToNot(ContainSubstring("#"))
+		g.Expect(buf.String()).To(ContainSubstring("env: prod"))
+	})
+	t.Run("should drop documents of earlier reads of a file", func(t *testing.T) {
+		g := NewWithT(t)
+		originals := yaml.NewOriginals()
+
+		changingFS := fstest.MapFS{
+			"config.yaml": &fstest.MapFile{Data: []byte(commentedYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: changingFS, Path: "*.yaml"}},
+			yaml.WithOriginals(originals),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		previous, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		changingFS["config.yaml"] = &fstest.MapFile{Data: []byte(strings.ReplaceAll(commentedYAML, "name: commented", "name: renamed"))}
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = yaml.Write(previous, &buf, yaml.WithPreservedFormatting(originals))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(buf.String()).ToNot(ContainSubstring("#"))
+	})
+}
@@ -0,0 +1,148 @@
+package yaml
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// OverlayFS returns a filesystem presenting the union of layers as a single tree, e.g. a base
+// tree and an environment tree overriding some of its files. Later layers shadow earlier ones: a
+// file is read from the last layer containing its path, and directories list the entries of all
+// layers in which the path is a directory, down to the first layer holding a file at that path or
+// at one of its parent directories.
+//
+// Overlays can only add or replace files. To hide files of a base layer, add an ignore file
+// (DefaultIgnoreFile) to an upper layer; it shadows the base layer's ignore file.
+//
+// Example:
+//
+//	fsys := yaml.OverlayFS(os.DirFS("base"), os.DirFS("overlays/prod"))
+//	r, err := yaml.New([]yaml.Source{{FS: fsys, Path: "**/*.yaml"}})
+func OverlayFS(layers ...fs.FS) fs.FS {
+	return overlayFS(slices.Clone(layers))
+}
+
+// overlayFS is a union of filesystems, lowest layer first.
+type overlayFS []fs.FS
+
+// lookup returns the info of name in the top-most layer containing it, and the layers serving
+// it, top-most first: the top-most layer for files, and all layers down to the first one not
+// holding a directory for directories.
+func (o overlayFS) lookup(op string, name string) (fs.FileInfo, []fs.FS, error) {
+	if !fs.ValidPath(name) {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	var (
+		top    fs.FileInfo
+		layers []fs.FS
+	)
+
+	for i := len(o) - 1; i >= 0; i-- {
+		info, err := fs.Stat(o[i], name)
+		if err != nil {
+			// A file replacing a parent directory hides the path in all lower layers
+			if hasFileParent(o[i], name) {
+				break
+			}
+
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, nil, err
+		}
+
+		if top == nil {
+			top = info
+		} else if !info.IsDir() {
+			// A file hides everything below it
+			break
+		}
+
+		layers = append(layers, o[i])
+
+		if !top.IsDir() {
+			break
+		}
+	}
+
+	if top == nil {
+		return nil, nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return top, layers, nil
+}
+
+// hasFileParent reports whether a parent directory of name is a file in fsys.
+func hasFileParent(fsys fs.FS, name string) bool {
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if info, err := fs.Stat(fsys, dir); err == nil {
+			return !info.IsDir()
+		}
+	}
+
+	return false
+}
+
+// Open implements fs.FS.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	info, layers, err := o.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return layers[0].Open(name)
+	}
+
+	return &dirFile{name: name, info: info, list: func() ([]fs.DirEntry, error) {
+		return o.ReadDir(name)
+	}}, nil
+}
+
+// Stat implements fs.StatFS.
+func (o overlayFS) Stat(name string) (fs.FileInfo, error) {
+	info, _, err := o.lookup("stat", name)
+
+	return info, err
+}
+
+// ReadDir implements fs.ReadDirFS, merging the entries of all layers serving the directory.
+// Entries of upper layers shadow entries with the same name in lower layers.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	info, layers, err := o.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	seen := make(map[string]bool)
+	entries := make([]fs.DirEntry, 0)
+
+	for _, layer := range layers {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range layerEntries {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,92 @@
+package yaml_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const prodConfigMapYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-config
+data:
+  env: prod
+`
+
+func TestOverlayFS(t *testing.T) {
+	base := fstest.MapFS{
+		"pod.yaml":              &fstest.MapFile{Data: []byte(podYAML)},
+		"config/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"config/legacy/a.yaml":  &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	prod := fstest.MapFS{
+		"config/configmap.yaml": &fstest.MapFile{Data: []byte(prodConfigMapYAML)},
+		"config/legacy":         &fstest.MapFile{Data: []byte("# replaced directory\n")},
+		"extra/multi-doc.yaml":  &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	fsys := yaml.OverlayFS(base, prod)
+
+	t.Run("should satisfy fs.FS semantics", func(t *testing.T) {
+		g := NewWithT(t)
+
+		err := fstest.TestFS(fsys, "pod.yaml", "config/configmap.yaml", "config/legacy", "extra/multi-doc.yaml")
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("should shadow files and directories of lower layers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		content, err := fs.ReadFile(fsys, "config/configmap.yaml")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal(prodConfigMapYAML))
+
+		info, err := fs.Stat(fsys, "config/legacy")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(info.IsDir()).To(BeFalse())
+
+		_, err = fs.Stat(fsys, "config/legacy/a.yaml")
+		g.Expect(err).To(MatchError(fs.ErrNotExist))
+
+		entries, err := fs.ReadDir(fsys, ".")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+
+		g.Expect(names).To(Equal([]string{"config", "extra", "pod.yaml"}))
+	})
+
+	t.Run("should render the merged tree", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: fsys, Path: "**/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+
+		for _, obj := range objects {
+			if obj.GetKind() == "ConfigMap" {
+				g.Expect(obj.Object["data"]).To(Equal(map[string]any{"env": "prod"}))
+			}
+		}
+	})
+
+	t.Run("should reject invalid paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := fsys.Open("/pod.yaml")
+		g.Expect(err).To(MatchError(fs.ErrInvalid))
+	})
+}
@@ -0,0 +1,162 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ErrAlreadyOwned is returned when a controller owner reference is set on an object that already
+// has a different controller.
+var ErrAlreadyOwned = errors.New("object is already owned by another controller")
+
+// OwnerReferenceOptions configures the OwnerReference transformer.
+type OwnerReferenceOptions struct {
+	// Controller marks the owner as the managing controller of the objects.
+	Controller bool
+
+	// BlockOwnerDeletion prevents the owner from being deleted in foreground deletion until
+	// the objects are deleted.
+	BlockOwnerDeletion bool
+
+	// ClusterScoped reports whether objects of a kind are cluster-scoped. nil = IsClusterScoped,
+	// which only knows built-in kinds; provide a function (e.g. backed by a RESTMapper) if the
+	// manifests contain cluster-scoped custom resources.
+	ClusterScoped func(gk schema.GroupKind) bool
+}
+
+// OwnerReference returns a transformer that sets an owner reference to owner on objects, e.g.
+// for operators rendering bundled manifests owned by a custom resource. An existing reference to
+// the same owner is replaced.
+//
+// Objects that cannot be owned by a namespaced owner are left unchanged: cluster-scoped objects
+// and objects in another namespace (the garbage collector would treat the owner as absent).
+// Objects without a namespace are assumed to be created in the owner's namespace. Setting a
+// controller reference on an object with a different controller fails with ErrAlreadyOwned.
+//
+// Example:
+//
+//	yaml.WithTransformer(yaml.OwnerReference(
+//		myApp,
+//		myappv1.GroupVersion.WithKind("MyApp"),
+//		yaml.OwnerReferenceOptions{Controller: true, BlockOwnerDeletion: true},
+//	))
+func OwnerReference(
+	owner metav1.Object,
+	gvk schema.GroupVersionKind,
+	opts OwnerReferenceOptions,
+) types.Transformer {
+	ref := metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}
+
+	if opts.Controller {
+		controller := true
+		ref.Controller = &controller
+	}
+
+	if opts.BlockOwnerDeletion {
+		block := true
+		ref.BlockOwnerDeletion = &block
+	}
+
+	clusterScoped := opts.ClusterScoped
+	if clusterScoped == nil {
+		clusterScoped = IsClusterScoped
+	}
+
+	namespace := owner.GetNamespace()
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if namespace != "" {
+			if clusterScoped(obj.GroupVersionKind().GroupKind()) {
+				return obj, nil
+			}
+
+			if ns := obj.GetNamespace(); ns != "" && ns != namespace {
+				return obj, nil
+			}
+		}
+
+		refs := obj.GetOwnerReferences()
+		refs = slices.DeleteFunc(refs, func(r metav1.OwnerReference) bool {
+			return sameOwner(r, ref)
+		})
+
+		if opts.Controller {
+			if i := slices.IndexFunc(refs, func(r metav1.OwnerReference) bool {
+				return r.Controller != nil && *r.Controller
+			}); i >= 0 {
+				return unstructured.Unstructured{}, fmt.Errorf(
+					"%w: %s %s is controlled by %s %s",
+					ErrAlreadyOwned,
+					obj.GetKind(),
+					obj.GetName(),
+					refs[i].Kind,
+					refs[i].Name,
+				)
+			}
+		}
+
+		obj.SetOwnerReferences(append(refs, ref))
+
+		return obj, nil
+	}
+}
+
+// sameOwner reports whether two owner references point to the same object. References are
+// compared by UID if both have one, and by group, kind and name otherwise.
+func sameOwner(a metav1.OwnerReference, b metav1.OwnerReference) bool {
+	if a.UID != "" && b.UID != "" {
+		return a.UID == b.UID
+	}
+
+	agv, _ := schema.ParseGroupVersion(a.APIVersion)
+	bgv, _ := schema.ParseGroupVersion(b.APIVersion)
+
+	return agv.Group == bgv.Group && a.Kind == b.Kind && a.Name == b.Name
+}
+
+// ClusterScopedKinds returns the built-in Kubernetes kinds that are cluster-scoped.
+func ClusterScopedKinds() []schema.GroupKind {
+	return []schema.GroupKind{
+		{Group: "", Kind: "Namespace"},
+		{Group: "", Kind: "Node"},
+		{Group: "", Kind: "PersistentVolume"},
+		{Group: "", Kind: "ComponentStatus"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+		{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+		{Group: "apiregistration.k8s.io", Kind: "APIService"},
+		{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"},
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"},
+		{Group: "storage.k8s.io", Kind: "StorageClass"},
+		{Group: "storage.k8s.io", Kind: "CSIDriver"},
+		{Group: "storage.k8s.io", Kind: "CSINode"},
+		{Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+		{Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+		{Group: "networking.k8s.io", Kind: "IngressClass"},
+		{Group: "node.k8s.io", Kind: "RuntimeClass"},
+		{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"},
+		{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"},
+		{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"},
+	}
+}
+
+// IsClusterScoped reports whether gk is one of the cluster-scoped built-in kinds listed by
+// ClusterScopedKinds.
+func IsClusterScoped(gk schema.GroupKind) bool {
+	return slices.Contains(ClusterScopedKinds(), gk)
+}
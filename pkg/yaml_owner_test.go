@@ -0,0 +1,170 @@
+package yaml_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const ownedYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: same-namespace
+  namespace: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: no-namespace
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other-namespace
+  namespace: other
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: cluster-scoped
+`
+
+func TestOwnerReference(t *testing.T) {
+	ownerGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "MyApp"}
+
+	owner := &unstructured.Unstructured{}
+	owner.SetGroupVersionKind(ownerGVK)
+	owner.SetName("my-app")
+	owner.SetNamespace("apps")
+	owner.SetUID("1234")
+
+	render := func(t *testing.T, opts yaml.OwnerReferenceOptions) map[string][]metav1.OwnerReference {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"owned.yaml": ownedYAML},
+			yaml.WithTransformer(yaml.OwnerReference(owner, ownerGVK, opts)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		refs := make(map[string][]metav1.OwnerReference, len(objects))
+		for _, obj := range objects {
+			refs[obj.GetName()] = obj.GetOwnerReferences()
+		}
+
+		return refs
+	}
+
+	t.Run("should set owner references on objects in the owner namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		refs := render(t, yaml.OwnerReferenceOptions{})
+
+		g.Expect(refs["same-namespace"]).To(Equal([]metav1.OwnerReference{{
+			APIVersion: "example.com/v1",
+			Kind:       "MyApp",
+			Name:       "my-app",
+			UID:        "1234",
+		}}))
+		g.Expect(refs["no-namespace"]).To(HaveLen(1))
+		g.Expect(refs["other-namespace"]).To(BeEmpty())
+		g.Expect(refs["cluster-scoped"]).To(BeEmpty())
+	})
+
+	t.Run("should set controller and blockOwnerDeletion flags", func(t *testing.T) {
+		g := NewWithT(t)
+
+		refs := render(t, yaml.OwnerReferenceOptions{Controller: true, BlockOwnerDeletion: true})
+
+		g.Expect(refs["same-namespace"]).To(ConsistOf(And(
+			HaveField("Controller", HaveValue(BeTrue())),
+			HaveField("BlockOwnerDeletion", HaveValue(BeTrue())),
+		)))
+	})
+
+	t.Run("should set owner references on cluster-scoped objects for cluster-scoped owners", func(t *testing.T) {
+		g := NewWithT(t)
+		clusterOwner := owner.DeepCopy()
+		clusterOwner.SetNamespace("")
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"owned.yaml": ownedYAML},
+			yaml.WithTransformer(yaml.OwnerReference(clusterOwner, ownerGVK, yaml.OwnerReferenceOptions{})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveEach(WithTransform(
+			func(obj unstructured.Unstructured) []metav1.OwnerReference { return obj.GetOwnerReferences() },
+			HaveLen(1),
+		)))
+	})
+
+	t.Run("should replace an existing reference to the same owner", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"owned.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: owned
+  ownerReferences:
+  - apiVersion: example.com/v1beta1
+    kind: MyApp
+    name: my-app
+  - apiVersion: v1
+    kind: ConfigMap
+    name: other
+    uid: "5678"
+`},
+			yaml.WithTransformer(yaml.OwnerReference(owner, ownerGVK, yaml.OwnerReferenceOptions{Controller: true})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetOwnerReferences()).To(ConsistOf(
+			HaveField("UID", BeEquivalentTo("5678")),
+			HaveField("UID", BeEquivalentTo("1234")),
+		))
+	})
+
+	t.Run("should fail if another controller owns the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"owned.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: owned
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: other
+    uid: "5678"
+    controller: true
+`},
+			yaml.WithTransformer(yaml.OwnerReference(owner, ownerGVK, yaml.OwnerReferenceOptions{Controller: true})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrAlreadyOwned))
+		g.Expect(err.Error()).To(ContainSubstring("ConfigMap owned is controlled by Deployment other"))
+	})
+}
@@ -0,0 +1,101 @@
+package yaml
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// sourceResult is the outcome of rendering a single source.
+type sourceResult struct {
+	objects []unstructured.Unstructured
+	report  SourceReport
+	err     error
+}
+
+// renderSources renders inputs and returns their results in input order, together with the
+// index of the failed source (-1 if all succeeded). Results after the failed source are not
+// meaningful. With WithSourceConcurrency, sources are rendered by a bounded pool of goroutines.
+func (r *Renderer) renderSources(ctx context.Context, inputs []*sourceHolder) ([]sourceResult, int) {
+	results := make([]sourceResult, len(inputs))
+
+	if r.opts.SourceConcurrency <= 1 || len(inputs) <= 1 {
+		for i, holder := range inputs {
+			objects, report, err := r.renderSource(ctx, holder)
+			results[i] = sourceResult{objects: objects, report: report, err: err}
+
+			if err != nil {
+				return results, i
+			}
+		}
+
+		return results, -1
+	}
+
+	// Each source has its own context, so a failure only cancels the sources after it and the
+	// first failure in input order is always reported, like in a sequential render
+	contexts := make([]context.Context, len(inputs))
+	cancels := make([]context.CancelFunc, len(inputs))
+
+	for i := range inputs {
+		contexts[i], cancels[i] = context.WithCancel(ctx)
+	}
+
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	var (
+		mu     sync.Mutex
+		failed = len(inputs)
+	)
+
+	fail := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if i >= failed {
+			return
+		}
+
+		failed = i
+		for _, cancel := range cancels[i+1:] {
+			cancel()
+		}
+	}
+
+	slots := make(chan struct{}, r.opts.SourceConcurrency)
+
+	var wg sync.WaitGroup
+
+	for i, holder := range inputs {
+		slots <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			objects, report, err := r.renderSource(contexts[i], holder)
+			results[i] = sourceResult{objects: objects, report: report, err: err}
+
+			if err != nil {
+				fail(i)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if failed == len(inputs) {
+		return results, -1
+	}
+
+	return results, failed
+}
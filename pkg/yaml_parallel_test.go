@@ -0,0 +1,107 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSourceConcurrency(t *testing.T) {
+	sources := func(n int) []yaml.Source {
+		inputs := make([]yaml.Source, n)
+		for i := range inputs {
+			name := fmt.Sprintf("config-%d", i)
+			inputs[i] = yaml.Source{
+				FS: fstest.MapFS{name + ".yaml": &fstest.MapFile{Data: []byte(
+					"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n",
+				)}},
+				Path: name + ".yaml",
+			}
+		}
+
+		return inputs
+	}
+
+	t.Run("should merge objects in source order with bounded concurrency", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var running, peak atomic.Int32
+		var report yaml.RenderReport
+
+		renderer, err := yaml.New(sources(8),
+			yaml.WithSourceConcurrency(3),
+			yaml.WithDocumentHook(func(context.Context, yaml.DocumentInfo, *unstructured.Unstructured) error {
+				current := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					p := peak.Load()
+					if current <= p || peak.CompareAndSwap(p, current) {
+						break
+					}
+				}
+
+				time.Sleep(5 * time.Millisecond)
+
+				return nil
+			}),
+			yaml.WithPostRenderHook(func(_ context.Context, _ []unstructured.Unstructured, r yaml.RenderReport) error {
+				report = r
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(8))
+		g.Expect(report.Sources).To(HaveLen(8))
+
+		for i := range objects {
+			g.Expect(objects[i].GetName()).To(Equal(fmt.Sprintf("config-%d", i)))
+			g.Expect(report.Sources[i].Path).To(Equal(fmt.Sprintf("config-%d.yaml", i)))
+		}
+
+		g.Expect(peak.Load()).To(BeNumerically("<=", 3))
+	})
+
+	t.Run("should report the first failing source in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errFirst := errors.New("first failure")
+		errLater := errors.New("later failure")
+
+		renderer, err := yaml.New(sources(6),
+			yaml.WithSourceConcurrency(6),
+			yaml.WithDocumentHook(func(_ context.Context, _ yaml.DocumentInfo, obj *unstructured.Unstructured) error {
+				switch obj.GetName() {
+				case "config-1":
+					// Fail after the later source, which must not cancel this one
+					time.Sleep(20 * time.Millisecond)
+
+					return errFirst
+				case "config-4":
+					return errLater
+				}
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errFirst))
+		g.Expect(err).To(MatchError(ContainSubstring("config-1.yaml")))
+	})
+}
@@ -0,0 +1,36 @@
+package yaml
+
+import (
+	"fmt"
+)
+
+// IncompleteRenderError is returned by Process, together with the objects rendered so far, when
+// the render deadline expires and partial results are enabled with WithPartialResults.
+// Objects are only returned for sources that were rendered completely.
+type IncompleteRenderError struct {
+	// Remaining lists the patterns of the sources that were not rendered, in order.
+	Remaining []string
+
+	// Err is the error that interrupted the render; it wraps context.DeadlineExceeded.
+	Err error
+}
+
+// newIncompleteRenderError creates an IncompleteRenderError for the given unrendered sources.
+func newIncompleteRenderError(remaining []*sourceHolder, err error) *IncompleteRenderError {
+	paths := make([]string, len(remaining))
+	for i, holder := range remaining {
+		paths[i] = holder.Path
+	}
+
+	return &IncompleteRenderError{Remaining: paths, Err: err}
+}
+
+// Error implements error.
+func (e *IncompleteRenderError) Error() string {
+	return fmt.Sprintf("incomplete render, %d sources not rendered: %v", len(e.Remaining), e.Err)
+}
+
+// Unwrap returns the error that interrupted the render.
+func (e *IncompleteRenderError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,73 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPartialResults(t *testing.T) {
+	testFS := fstest.MapFS{
+		"a/pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"b/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"c/multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	sources := []yaml.Source{
+		{FS: testFS, Path: "a/*.yaml"},
+		{FS: testFS, Path: "b/*.yaml"},
+		{FS: testFS, Path: "c/*.yaml"},
+	}
+
+	// slowHook blocks on the second source until the render deadline expires
+	slowHook := yaml.WithDocumentHook(func(ctx context.Context, info yaml.DocumentInfo, _ *unstructured.Unstructured) error {
+		if info.Pattern != "b/*.yaml" {
+			return nil
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	t.Run("should return objects rendered before the deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(sources, slowHook, yaml.WithPartialResults(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(context.DeadlineExceeded))
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+
+		var incomplete *yaml.IncompleteRenderError
+		g.Expect(errors.As(err, &incomplete)).To(BeTrue())
+		g.Expect(incomplete.Remaining).To(Equal([]string{"b/*.yaml", "c/*.yaml"}))
+	})
+
+	t.Run("should fail without partial results", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(sources, slowHook)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(context.DeadlineExceeded))
+		g.Expect(objects).To(BeNil())
+	})
+}
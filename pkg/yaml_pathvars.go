@@ -0,0 +1,79 @@
+package yaml
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrUndefinedVariable is returned when a source path placeholder has no render-time value.
+var ErrUndefinedVariable = errors.New("undefined path variable")
+
+// pathVariable matches ${NAME} placeholders in source paths.
+var pathVariable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// renderValuesKey is the context key of the values passed to Process.
+type renderValuesKey struct{}
+
+// withRenderValues returns a context carrying the render-time values passed to Process.
+func withRenderValues(ctx context.Context, values map[string]any) context.Context {
+	if len(values) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, renderValuesKey{}, values)
+}
+
+// hasPathVariables reports whether a source path contains placeholders.
+func hasPathVariables(pattern string) bool {
+	return strings.Contains(pattern, "${")
+}
+
+// validatePathVariables checks the placeholders of a source path and returns the pattern with
+// each placeholder replaced by a literal, for glob validation.
+func validatePathVariables(pattern string) (string, error) {
+	literal := pathVariable.ReplaceAllString(pattern, "x")
+	if strings.Contains(literal, "${") {
+		return "", fmt.Errorf("%w: %q: malformed ${NAME} placeholder", ErrInvalidPattern, pattern)
+	}
+
+	return literal, nil
+}
+
+// resolvePath replaces the ${NAME} placeholders of a source path with the render-time values of
+// ctx. Values must be non-empty scalars without glob metacharacters, so they cannot widen the
+// pattern.
+func resolvePath(ctx context.Context, pattern string) (string, error) {
+	if !hasPathVariables(pattern) {
+		return pattern, nil
+	}
+
+	values, _ := ctx.Value(renderValuesKey{}).(map[string]any)
+
+	var resolveErr error
+
+	resolved := pathVariable.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-1]
+
+		value, ok := values[name]
+		if !ok || value == nil {
+			resolveErr = cmp.Or(resolveErr, fmt.Errorf("%w: %s", ErrUndefinedVariable, name))
+
+			return placeholder
+		}
+
+		s := fmt.Sprint(value)
+		if s == "" || strings.ContainsAny(s, `*?[]{}\`) {
+			resolveErr = cmp.Or(resolveErr, fmt.Errorf("%w: invalid value %q for %s", ErrInvalidPattern, s, name))
+
+			return placeholder
+		}
+
+		return s
+	})
+
+	return resolved, resolveErr
+}
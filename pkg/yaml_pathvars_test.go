@@ -0,0 +1,76 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPathVariables(t *testing.T) {
+	testFS := fstest.MapFS{
+		"clusters/east/apps/pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"clusters/west/apps/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	t.Run("should resolve placeholders from render-time values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var keys []string
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "clusters/${CLUSTER}/**/*.yaml"}},
+			yaml.WithCache(cache.WithKeyFunc(func(key any) string {
+				spec, _ := key.(yaml.YAMLSpec)
+				keys = append(keys, spec.Path)
+
+				return spec.Path
+			})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), map[string]any{"CLUSTER": "east"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+
+		objects, err = renderer.Process(t.Context(), map[string]any{"CLUSTER": "west"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+
+		g.Expect(keys).To(ContainElements("clusters/east/**/*.yaml", "clusters/west/**/*.yaml"))
+	})
+
+	t.Run("should fail on undefined variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "clusters/${CLUSTER}/**/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrUndefinedVariable))
+		g.Expect(err).To(MatchError(ContainSubstring("CLUSTER")))
+	})
+
+	t.Run("should reject values widening the pattern", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "clusters/${CLUSTER}/**/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), map[string]any{"CLUSTER": "*"})
+		g.Expect(err).To(MatchError(yaml.ErrInvalidPattern))
+	})
+
+	t.Run("should reject malformed placeholders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{{FS: testFS, Path: "clusters/${CLUSTER/*.yaml"}})
+		g.Expect(err).To(MatchError(yaml.ErrInvalidPattern))
+	})
+}
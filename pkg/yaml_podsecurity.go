@@ -0,0 +1,149 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PodSecurityException exempts workloads from DefaultPodSecurity.
+type PodSecurityException struct {
+	// Kind is the exempted kind ("Kind" or "Kind.group"). Empty matches all workload kinds.
+	Kind string
+
+	// Name is a path.Match pattern of exempted object names. Empty matches all names.
+	Name string
+}
+
+// PodSecurityDefaults are the securityContext baselines applied by DefaultPodSecurity.
+type PodSecurityDefaults struct {
+	// RunAsNonRoot sets the pod runAsNonRoot to true.
+	RunAsNonRoot bool
+
+	// SeccompRuntimeDefault sets the pod seccomp profile to RuntimeDefault.
+	SeccompRuntimeDefault bool
+
+	// DropAllCapabilities adds ALL to the dropped capabilities of every container.
+	DropAllCapabilities bool
+
+	// DisallowPrivilegeEscalation sets allowPrivilegeEscalation to false on every container.
+	DisallowPrivilegeEscalation bool
+
+	// Override replaces values that contradict the baseline (e.g. runAsNonRoot: false) instead of
+	// leaving them unchanged.
+	Override bool
+
+	// Exceptions are workloads left unchanged.
+	Exceptions []PodSecurityException
+}
+
+// RestrictedPodSecurity returns defaults covering the securityContext requirements of the
+// "restricted" Pod Security Standard: non-root users, the RuntimeDefault seccomp profile, all
+// capabilities dropped and no privilege escalation.
+func RestrictedPodSecurity() PodSecurityDefaults {
+	return PodSecurityDefaults{
+		RunAsNonRoot:                true,
+		SeccompRuntimeDefault:       true,
+		DropAllCapabilities:         true,
+		DisallowPrivilegeEscalation: true,
+	}
+}
+
+// DefaultPodSecurity returns a transformer that applies securityContext baselines to the pod
+// templates of workloads, e.g. to produce output compliant with the restricted Pod Security
+// Standard from legacy YAML. Fields that are not set are added; fields contradicting the
+// baseline are only replaced with Override. Workloads matching an exception and other objects
+// are returned unchanged.
+//
+// Example:
+//
+//	defaults := yaml.RestrictedPodSecurity()
+//	defaults.Exceptions = []yaml.PodSecurityException{{Kind: "DaemonSet", Name: "node-exporter"}}
+//	t, err := yaml.DefaultPodSecurity(defaults)
+func DefaultPodSecurity(defaults PodSecurityDefaults) (types.Transformer, error) {
+	exceptions := make([]schema.GroupKind, len(defaults.Exceptions))
+	for i, e := range defaults.Exceptions {
+		if _, err := path.Match(e.Name, ""); err != nil {
+			return nil, fmt.Errorf("invalid exception name pattern %q: %w", e.Name, err)
+		}
+
+		exceptions[i] = schema.ParseGroupKind(e.Kind)
+	}
+
+	exempt := func(obj unstructured.Unstructured) bool {
+		gk := obj.GroupVersionKind().GroupKind()
+
+		for i, e := range defaults.Exceptions {
+			if exceptions[i].Kind != "" && (exceptions[i].Kind != gk.Kind ||
+				exceptions[i].Group != "" && exceptions[i].Group != gk.Group) {
+				continue
+			}
+
+			if matched, _ := path.Match(e.Name, obj.GetName()); e.Name == "" || matched {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		spec, ok := podSpec(obj)
+		if !ok || exempt(obj) {
+			return obj, nil
+		}
+
+		podContext := nestedMap(spec, "securityContext")
+
+		if defaults.RunAsNonRoot {
+			setDefault(podContext, "runAsNonRoot", true, defaults.Override)
+		}
+
+		if defaults.SeccompRuntimeDefault {
+			setDefault(nestedMap(podContext, "seccompProfile"), "type", "RuntimeDefault", defaults.Override)
+		}
+
+		for _, container := range podContainers(spec) {
+			containerContext := nestedMap(container, "securityContext")
+
+			if defaults.DisallowPrivilegeEscalation {
+				setDefault(containerContext, "allowPrivilegeEscalation", false, defaults.Override)
+			}
+
+			if defaults.DropAllCapabilities {
+				capabilities := nestedMap(containerContext, "capabilities")
+
+				drop, _ := capabilities["drop"].([]any)
+				if !slices.Contains(drop, any("ALL")) {
+					capabilities["drop"] = append(drop, "ALL")
+				}
+			}
+		}
+
+		return obj, nil
+	}, nil
+}
+
+// nestedMap returns the map stored at key, creating it if needed.
+func nestedMap(m map[string]any, key string) map[string]any {
+	child, ok := m[key].(map[string]any)
+	if !ok {
+		child = make(map[string]any)
+		m[key] = child
+	}
+
+	return child
+}
+
+// setDefault sets key to value if it is not set, or unconditionally with override.
+func setDefault(m map[string]any, key string, value any, override bool) {
+	if _, exists := m[key]; !exists || override {
+		m[key] = value
+	}
+}
@@ -0,0 +1,119 @@
+package yaml_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const legacyWorkloadsYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: false
+      containers:
+      - name: web
+        image: nginx
+        securityContext:
+          capabilities:
+            drop: [NET_RAW]
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: node-exporter
+spec:
+  template:
+    spec:
+      containers:
+      - name: exporter
+        image: exporter
+`
+
+func TestDefaultPodSecurity(t *testing.T) {
+	render := func(t *testing.T, defaults yaml.PodSecurityDefaults) map[string]map[string]any {
+		t.Helper()
+		g := NewWithT(t)
+
+		transformer, err := yaml.DefaultPodSecurity(defaults)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"workloads.yaml": legacyWorkloadsYAML},
+			yaml.WithTransformer(transformer),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		specs := make(map[string]map[string]any, len(objects))
+		for _, obj := range objects {
+			specs[obj.GetName()], _, _ = unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		}
+
+		return specs
+	}
+
+	t.Run("should add restricted securityContext fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		specs := render(t, yaml.RestrictedPodSecurity())
+
+		g.Expect(specs["node-exporter"]["securityContext"]).To(Equal(map[string]any{
+			"runAsNonRoot":   true,
+			"seccompProfile": map[string]any{"type": "RuntimeDefault"},
+		}))
+		g.Expect(specs["node-exporter"]["containers"]).To(ConsistOf(HaveKeyWithValue("securityContext", map[string]any{
+			"allowPrivilegeEscalation": false,
+			"capabilities":             map[string]any{"drop": []any{"ALL"}},
+		})))
+
+		// Explicit values are kept, capabilities are added to
+		g.Expect(specs["web"]["securityContext"]).To(HaveKeyWithValue("runAsNonRoot", false))
+		g.Expect(specs["web"]["containers"]).To(ConsistOf(HaveKeyWithValue("securityContext", HaveKeyWithValue(
+			"capabilities", map[string]any{"drop": []any{"NET_RAW", "ALL"}},
+		))))
+	})
+
+	t.Run("should override contradicting values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaults := yaml.RestrictedPodSecurity()
+		defaults.Override = true
+
+		specs := render(t, defaults)
+
+		g.Expect(specs["web"]["securityContext"]).To(HaveKeyWithValue("runAsNonRoot", true))
+	})
+
+	t.Run("should skip exempted workloads", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaults := yaml.RestrictedPodSecurity()
+		defaults.Exceptions = []yaml.PodSecurityException{{Kind: "DaemonSet.apps", Name: "node-*"}}
+
+		specs := render(t, defaults)
+
+		g.Expect(specs["node-exporter"]).ToNot(HaveKey("securityContext"))
+		g.Expect(specs["web"]["securityContext"]).To(HaveKey("seccompProfile"))
+	})
+
+	t.Run("should reject invalid exception patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.DefaultPodSecurity(yaml.PodSecurityDefaults{
+			Exceptions: []yaml.PodSecurityException{{Name: "["}},
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid exception name pattern")))
+	})
+}
@@ -0,0 +1,283 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TransformerStep describes the effect of a single transformer on an object.
+type TransformerStep struct {
+	// Transformer is the position of the transformer in execution order.
+	Transformer int
+
+	// Diff is a line diff of the object YAML before and after the transformer (see LineDiff).
+	// It is empty if the transformer did not change the object.
+	Diff string
+
+	// Err is the error returned by the transformer. The object is passed on unchanged.
+	Err error
+}
+
+// Changed reports whether the transformer changed the object.
+func (s TransformerStep) Changed() bool {
+	return s.Diff != ""
+}
+
+// ObjectPreview describes how the transformer chain changes a single object.
+type ObjectPreview struct {
+	// Source is the name of the source the object was read from, or its pattern if unnamed.
+	Source string
+
+	// Before is the object as decoded and filtered, before any transformer.
+	Before unstructured.Unstructured
+
+	// After is the object after all transformers.
+	After unstructured.Unstructured
+
+	// Steps holds one entry per transformer, in execution order.
+	Steps []TransformerStep
+}
+
+// PreviewTransformers renders all sources and runs the renderer transformers step by step,
+// returning the effect of every transformer on every object without producing render output:
+// validators, hooks and events are not run. Transformer errors are recorded in the steps instead
+// of aborting the preview. Secret values are redacted from diffs and errors if redaction is
+// enabled.
+//
+// Example:
+//
+//	previews, _ := r.PreviewTransformers(ctx)
+//	for _, p := range previews {
+//		for _, s := range p.Steps {
+//			if s.Changed() {
+//				fmt.Printf("%s: transformer %d\n%s", p.After.GetName(), s.Transformer, s.Diff)
+//			}
+//		}
+//	}
+func (r *Renderer) PreviewTransformers(ctx context.Context) ([]ObjectPreview, error) {
+	ctx = withWarningHandler(ctx, r.opts.WarningHandler)
+	ctx = withPipelineValues(ctx, r.opts.PipelineValues)
+
+	r.mu.RLock()
+	inputs := r.inputs
+	r.mu.RUnlock()
+
+	var previews []ObjectPreview
+
+	for _, holder := range inputs {
+		result, _, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, r.labelError(fmt.Errorf("error rendering YAML pattern %s: %w", holder.Path, err))
+		}
+
+		objects := result.objects
+
+		filtered, err := pipeline.ApplyFilters(ctx, objects, r.opts.Filters)
+		if err != nil {
+			return nil, r.labelError(fmt.Errorf(
+				"error applying filters to YAML pattern %s: %w",
+				holder.Path,
+				r.redactError(err, objects),
+			))
+		}
+
+		source := holder.Name
+		if source == "" {
+			source = holder.Path
+		}
+
+		for _, obj := range filtered {
+			preview, err := r.previewObject(ctx, source, obj)
+			if err != nil {
+				return nil, r.labelError(err)
+			}
+
+			previews = append(previews, preview)
+		}
+	}
+
+	return previews, nil
+}
+
+// previewObject runs the transformers on a single object, recording each step.
+func (r *Renderer) previewObject(
+	ctx context.Context,
+	source string,
+	obj unstructured.Unstructured,
+) (ObjectPreview, error) {
+	preview := ObjectPreview{
+		Source: source,
+		Before: *obj.DeepCopy(),
+		Steps:  make([]TransformerStep, 0, len(r.opts.Transformers)),
+	}
+
+	current := obj
+
+	before, err := r.previewYAML(current)
+	if err != nil {
+		return ObjectPreview{}, err
+	}
+
+	for i, t := range r.opts.Transformers {
+		step := TransformerStep{Transformer: i}
+
+		result, err := t(ctx, *current.DeepCopy())
+		if err != nil {
+			step.Err = r.redactError(err, []unstructured.Unstructured{current})
+			preview.Steps = append(preview.Steps, step)
+
+			continue
+		}
+
+		after, err := r.previewYAML(result)
+		if err != nil {
+			return ObjectPreview{}, err
+		}
+
+		if after != before {
+			step.Diff = LineDiff(before, after)
+		}
+
+		preview.Steps = append(preview.Steps, step)
+		current, before = result, after
+	}
+
+	preview.After = current
+
+	return preview, nil
+}
+
+// previewYAML returns the YAML of an object for diffing, redacted if redaction is enabled.
+func (r *Renderer) previewYAML(obj unstructured.Unstructured) (string, error) {
+	if r.opts.Redact {
+		obj = Redact(obj, r.opts.SensitiveKinds...)
+	}
+
+	var buf bytes.Buffer
+	if err := Write([]unstructured.Unstructured{obj}, &buf); err != nil {
+		return "", fmt.Errorf("failed to write object for preview: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// LineDiff returns a line diff turning want into got: removed lines are prefixed with "-",
+// added lines with "+" and unchanged lines with a space. The diff is minimal and computed with
+// the linear-space variant of Myers' algorithm, so large objects can be diffed.
+func LineDiff(want string, got string) string {
+	var sb strings.Builder
+
+	writeLineDiff(&sb, strings.Split(want, "\n"), strings.Split(got, "\n"))
+
+	return sb.String()
+}
+
+// writeLineDiff writes the diff of two line slices, splitting it at the middle snake of the
+// shortest edit script until one side is empty.
+func writeLineDiff(sb *strings.Builder, a []string, b []string) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	writeLines(sb, "  ", a[:prefix])
+
+	ca, cb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	switch {
+	case len(ca) == 0:
+		writeLines(sb, "+ ", cb)
+	case len(cb) == 0:
+		writeLines(sb, "- ", ca)
+	default:
+		x, y, u, v := middleSnake(ca, cb)
+		writeLineDiff(sb, ca[:x], cb[:y])
+		writeLines(sb, "  ", ca[x:u])
+		writeLineDiff(sb, ca[u:], cb[v:])
+	}
+
+	writeLines(sb, "  ", a[len(a)-suffix:])
+}
+
+// writeLines writes lines with a diff prefix.
+func writeLines(sb *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+}
+
+// middleSnake returns the middle snake a[x:u] == b[y:v] of a shortest edit script of a into b,
+// searching forward from the start and backward from the end until the paths overlap. The
+// sequences must differ in their first and last lines, so both halves are strictly smaller.
+func middleSnake(a []string, b []string) (int, int, int, int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// forward[k] and backward[k] are the furthest x reached on diagonal k (x - y) from the start,
+	// and from the end of the reversed sequences
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := forward[offset+k-1] + 1
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			}
+
+			y := x - k
+			x0, y0 := x, y
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+
+			if r := delta - k; odd && r >= -(d-1) && r <= d-1 && x+backward[offset+r] >= n {
+				return x0, y0, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			x := backward[offset+k-1] + 1
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			}
+
+			y := x - k
+			x0, y0 := x, y
+
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+
+			backward[offset+k] = x
+
+			if f := delta - k; !odd && f >= -d && f <= d && x+forward[offset+f] >= n {
+				return n - x, m - y, n - x0, m - y0
+			}
+		}
+	}
+
+	// unreachable: the paths overlap within (n + m + 1) / 2 steps
+	return 0, 0, 0, 0
+}
@@ -0,0 +1,215 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/engine/pkg/filter/meta/gvk"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/labels"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPreviewTransformers(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	noop := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		return obj, nil
+	}
+
+	t.Run("should report a diff per transformer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Name: "app", FS: testFS, Path: "*.yaml"}},
+			yaml.WithFilters(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+			yaml.WithTransformers(
+				labels.Set(map[string]string{"env": "test"}),
+				noop,
+			),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		previews, err := renderer.PreviewTransformers(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(previews).To(HaveLen(1))
+
+		preview := previews[0]
+		g.Expect(preview.Source).To(Equal("app"))
+		g.Expect(preview.Before.GetLabels()).ToNot(HaveKey("env"))
+		g.Expect(preview.After.GetLabels()).To(HaveKeyWithValue("env", "test"))
+		g.Expect(preview.Steps).To(HaveLen(2))
+		g.Expect(preview.Steps[0].Changed()).To(BeTrue())
+		g.Expect(preview.Steps[0].Diff).To(ContainSubstring("+     env: test"))
+		g.Expect(preview.Steps[1].Changed()).To(BeFalse())
+	})
+
+	t.Run("should record transformer errors and continue", func(t *testing.T) {
+		g := NewWithT(t)
+		errBroken := errors.New("broken")
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithTransformers(
+				func(_ context.Context, _ unstructured.Unstructured) (unstructured.Unstructured, error) {
+					return unstructured.Unstructured{}, errBroken
+				},
+				labels.Set(map[string]string{"env": "test"}),
+			),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		previews, err := renderer.PreviewTransformers(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(previews).To(HaveLen(1))
+		g.Expect(previews[0].Source).To(Equal("pod.yaml"))
+		g.Expect(previews[0].Steps[0].Err).To(MatchError(errBroken))
+		g.Expect(previews[0].Steps[1].Changed()).To(BeTrue())
+		g.Expect(previews[0].After.GetLabels()).To(HaveKeyWithValue("env", "test"))
+	})
+
+	t.Run("should not modify rendered objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithCache(),
+			yaml.WithTransformer(labels.Set(map[string]string{"env": "test"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.PreviewTransformers(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("env", "test"))
+	})
+
+	t.Run("should redact secret values from diffs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: fstest.MapFS{
+				"secret.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+			}, Path: "secret.yaml"}},
+			yaml.WithTransformer(labels.Set(map[string]string{"env": "test"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		previews, err := renderer.PreviewTransformers(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(previews).To(HaveLen(2))
+		g.Expect(previews[1].Before.GetKind()).To(Equal("Secret"))
+		g.Expect(previews[1].Steps[0].Diff).To(ContainSubstring(yaml.RedactedValue))
+		g.Expect(previews[1].Steps[0].Diff).ToNot(ContainSubstring("cGFzc3dvcmQ="))
+	})
+}
+
+func TestLineDiff(t *testing.T) {
+	// apply rebuilds want and got from a diff
+	apply := func(diff string) (string, string, int) {
+		var want, got []string
+
+		changes := 0
+
+		for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+			switch line[:2] {
+			case "  ":
+				want = append(want, line[2:])
+				got = append(got, line[2:])
+			case "- ":
+				want = append(want, line[2:])
+				changes++
+			case "+ ":
+				got = append(got, line[2:])
+				changes++
+			}
+		}
+
+		return strings.Join(want, "\n"), strings.Join(got, "\n"), changes
+	}
+
+	// lcs returns the length of the longest common subsequence of two line slices
+	lcs := func(a []string, b []string) int {
+		row := make([]int, len(b)+1)
+		for i := range a {
+			prev := 0
+			for j := range b {
+				current := row[j+1]
+				if a[i] == b[j] {
+					row[j+1] = prev + 1
+				} else {
+					row[j+1] = max(row[j+1], row[j])
+				}
+
+				prev = current
+			}
+		}
+
+		return row[len(b)]
+	}
+
+	t.Run("should produce minimal diffs", func(t *testing.T) {
+		g := NewWithT(t)
+		rng := rand.New(rand.NewPCG(1, 2))
+
+		randomLines := func() []string {
+			lines := make([]string, rng.IntN(12))
+			for i := range lines {
+				lines[i] = string(rune('a' + rng.IntN(4)))
+			}
+
+			return lines
+		}
+
+		for range 500 {
+			a, b := randomLines(), randomLines()
+			want, got := strings.Join(a, "\n"), strings.Join(b, "\n")
+
+			diff := yaml.LineDiff(want, got)
+
+			rebuiltWant, rebuiltGot, changes := apply(diff)
+			g.Expect(rebuiltWant).To(Equal(want), diff)
+			g.Expect(rebuiltGot).To(Equal(got), diff)
+
+			wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+			g.Expect(changes).To(Equal(len(wantLines)+len(gotLines)-2*lcs(wantLines, gotLines)), diff)
+		}
+	})
+
+	t.Run("should diff large inputs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a := make([]string, 100000)
+		for i := range a {
+			a[i] = fmt.Sprintf("line %d", i)
+		}
+
+		b := slices.Clone(a)
+		for i := 500; i < len(b); i += 1000 {
+			b[i] = fmt.Sprintf("changed %d", i)
+		}
+
+		diff := yaml.LineDiff(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		g.Expect(diff).To(ContainSubstring("  line 50499\n- line 50500\n+ changed 50500\n  line 50501\n"))
+		g.Expect(strings.Count(diff, "\n- ")).To(Equal(100))
+		g.Expect(strings.Count(diff, "\n+ ")).To(Equal(100))
+	})
+}
@@ -0,0 +1,53 @@
+package yaml
+
+import (
+	"github.com/k8s-manifest-kit/pkg/util"
+)
+
+// StrictDeniedKinds returns the kinds denied by SecurityProfileStrict: cluster-wide RBAC
+// and admission webhooks, which let a bundle escalate privileges beyond its namespace.
+func StrictDeniedKinds() []string {
+	return []string{
+		"ClusterRole.rbac.authorization.k8s.io",
+		"ClusterRoleBinding.rbac.authorization.k8s.io",
+		"MutatingWebhookConfiguration.admissionregistration.k8s.io",
+		"ValidatingWebhookConfiguration.admissionregistration.k8s.io",
+	}
+}
+
+// SecurityProfileBaseline bounds resource usage for trusted manifests:
+//   - files up to 10 MiB, with at most 10000 documents nested at most 100 levels deep
+//   - binary files fail the render
+//   - secret values are redacted from errors
+//
+// Options passed after the profile override its settings.
+func SecurityProfileBaseline() RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.MaxFileSize = 10 << 20
+		opts.MaxDocuments = 10000
+		opts.MaxDepth = 100
+		opts.BinaryFilePolicy = PolicyError
+		opts.Redact = true
+	})
+}
+
+// SecurityProfileStrict guards renders of untrusted, user-supplied bundles:
+//   - files up to 1 MiB, with at most 1000 documents nested at most 32 levels deep
+//   - YAML aliases are rejected
+//   - hidden files are skipped and binary files fail the render
+//   - the kinds listed by StrictDeniedKinds are denied
+//   - secret values are redacted from errors
+//
+// Options passed after the profile override its settings; denied kinds are added to.
+func SecurityProfileStrict() RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.MaxFileSize = 1 << 20
+		opts.MaxDocuments = 1000
+		opts.MaxDepth = 32
+		opts.AllowAliases = false
+		opts.SkipHidden = true
+		opts.BinaryFilePolicy = PolicyError
+		opts.DeniedKinds = append(opts.DeniedKinds, StrictDeniedKinds()...)
+		opts.Redact = true
+	})
+}
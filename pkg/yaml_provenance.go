@@ -0,0 +1,266 @@
+package yaml
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ProvenanceStatementType is the in-toto statement type of provenance documents.
+	ProvenanceStatementType = "https://in-toto.io/Statement/v1"
+
+	// ProvenancePredicateType is the SLSA provenance predicate type of provenance documents.
+	ProvenancePredicateType = "https://slsa.dev/provenance/v1"
+
+	// ProvenanceBuildType identifies YAML renders in provenance documents.
+	ProvenanceBuildType = "https://github.com/k8s-manifest-kit/renderer-yaml/render@v1"
+
+	modulePath = "github.com/k8s-manifest-kit/renderer-yaml"
+)
+
+// Provenance is an in-toto statement carrying SLSA v1 provenance for a render.
+// Subjects are the rendered objects; resolved dependencies are the input files.
+// It is suitable for signing (e.g. as a DSSE payload) and attaching to hydrated artifacts.
+type Provenance struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     ProvenancePredicate  `json:"predicate"`
+}
+
+// ResourceDescriptor identifies an artifact by name and digest.
+type ResourceDescriptor struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// ProvenancePredicate is the SLSA v1 provenance predicate.
+type ProvenancePredicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a render.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies"`
+}
+
+// ExternalParameters are the render parameters: configured sources and an options fingerprint.
+type ExternalParameters struct {
+	Sources            []ProvenanceSource `json:"sources"`
+	OptionsFingerprint string             `json:"optionsFingerprint"`
+}
+
+// ProvenanceSource describes a configured source.
+type ProvenanceSource struct {
+	Name    string `json:"name,omitempty"`
+	Pattern string `json:"pattern"`
+}
+
+// RunDetails describes the renderer that performed a render.
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the renderer and its version.
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+// BuildMetadata holds timing information about a render.
+type BuildMetadata struct {
+	StartedOn  time.Time `json:"startedOn"`
+	FinishedOn time.Time `json:"finishedOn"`
+}
+
+// ProvenanceHandler receives the provenance document of every successful render.
+// Returning an error fails the render.
+type ProvenanceHandler func(ctx context.Context, provenance Provenance) error
+
+// provenanceHook is the post-render hook generating provenance for the configured handlers.
+func (r *Renderer) provenanceHook(ctx context.Context, objects []unstructured.Unstructured, report RenderReport) error {
+	if report.Err != nil {
+		return nil
+	}
+
+	provenance, err := r.provenance(objects, report, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to generate provenance: %w", err)
+	}
+
+	for _, handler := range r.opts.ProvenanceHandlers {
+		if err := handler(ctx, provenance); err != nil {
+			return fmt.Errorf("provenance handler failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// provenance builds the provenance document of a render.
+func (r *Renderer) provenance(objects []unstructured.Unstructured, report RenderReport, finished time.Time) (Provenance, error) {
+	subjects := make([]ResourceDescriptor, len(objects))
+	for i := range objects {
+		digest, err := objectDigest(objects[i])
+		if err != nil {
+			return Provenance{}, err
+		}
+
+		subjects[i] = ResourceDescriptor{
+			Name:   objectName(objects[i]),
+			Digest: digestMap(digest),
+		}
+	}
+
+	sources := make([]ProvenanceSource, len(report.Sources))
+	dependencies := make([]ResourceDescriptor, 0)
+
+	for i, source := range report.Sources {
+		sources[i] = ProvenanceSource{Name: source.Name, Pattern: source.Path}
+
+		for _, file := range source.Files {
+			digest, ok := source.Digests[file]
+			if !ok {
+				continue
+			}
+
+			dependencies = append(dependencies, ResourceDescriptor{
+				Name:   file,
+				Digest: digestMap(digest),
+			})
+		}
+	}
+
+	return Provenance{
+		Type:          ProvenanceStatementType,
+		Subject:       subjects,
+		PredicateType: ProvenancePredicateType,
+		Predicate: ProvenancePredicate{
+			BuildDefinition: BuildDefinition{
+				BuildType: ProvenanceBuildType,
+				ExternalParameters: ExternalParameters{
+					Sources:            sources,
+					OptionsFingerprint: r.fingerprint,
+				},
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{
+					ID:      "https://" + modulePath,
+					Version: map[string]string{modulePath: rendererVersion()},
+				},
+				Metadata: BuildMetadata{
+					StartedOn:  finished.Add(-report.Duration),
+					FinishedOn: finished,
+				},
+			},
+		},
+	}, nil
+}
+
+// objectName returns a stable identifier for an object: apiVersion/kind/namespace/name.
+func objectName(obj unstructured.Unstructured) string {
+	return strings.Join([]string{obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace(), obj.GetName()}, "/")
+}
+
+// digestMap converts a "sha256:<hex>" digest into an in-toto digest set.
+func digestMap(digest string) map[string]string {
+	algorithm, value, _ := strings.Cut(digest, ":")
+
+	return map[string]string{algorithm: value}
+}
+
+// rendererVersion returns the version of this module as recorded in the build information.
+func rendererVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+}
+
+// optionsFingerprint returns a digest of the renderer options that affect rendered output.
+// Function-valued options (filters, transformers, validators, hooks) cannot be compared,
+// so only their number contributes to the fingerprint.
+func optionsFingerprint(opts RendererOptions) (string, error) {
+	deniedKinds := slices.Clone(opts.DeniedKinds)
+	slices.Sort(deniedKinds)
+
+	sensitiveKinds := make([]string, len(opts.SensitiveKinds))
+	for i, gk := range opts.SensitiveKinds {
+		sensitiveKinds[i] = gk.String()
+	}
+	slices.Sort(sensitiveKinds)
+
+	data, err := json.Marshal(map[string]any{
+		"headerFilters":       len(opts.HeaderFilters),
+		"transformerErrors":   opts.TransformerErrorMode,
+		"validators":          len(opts.Validators),
+		"pipelineValues":      len(opts.PipelineValues),
+		"documentHooks":       len(opts.DocumentHooks),
+		"label":               opts.Label,
+		"sourceAnnotations":   opts.SourceAnnotations,
+		"sourcePrefix":        opts.SourcePrefix,
+		"kubernetesVersion":   opts.KubernetesVersion,
+		"deprecatedAPIPolicy": opts.DeprecatedAPIPolicy,
+		"versionSkewPolicy":   opts.VersionSkewPolicy,
+		"schemaBundles":       opts.SchemaBundles != nil,
+		"skipHidden":          opts.SkipHidden,
+		"ignoreFile":          opts.IgnoreFile,
+		"ignoreAnnotation":    opts.IgnoreAnnotation,
+		"targetSelector":      opts.TargetSelector,
+		"checksumFile":        opts.ChecksumFile,
+		"requireChecksums":    opts.RequireChecksums,
+		"caseInsensitive":     opts.CaseInsensitive,
+		"windowsPaths":        opts.WindowsPaths,
+		"requireMatch":        opts.RequireMatch,
+		"partialResults":      opts.PartialResults,
+		"serveStale":          opts.ServeStale,
+		"staleRevalidate":     opts.StaleWhileRevalidate,
+		"binaryFilePolicy":    opts.BinaryFilePolicy,
+		"allowedExtensions":   opts.AllowedExtensions,
+		"extensionPolicy":     opts.ExtensionPolicy,
+		"emptyDocumentPolicy": opts.EmptyDocumentPolicy,
+		"decoder":             fmt.Sprintf("%T", opts.Decoder),
+		"scalarResolution":    opts.ScalarResolution,
+		"ambiguousScalars":    opts.AmbiguousScalarPolicy,
+		"lossyNumbers":        opts.LossyNumberPolicy,
+		"preserveNumbers":     opts.PreserveLossyNumbers,
+		"deniedKinds":         deniedKinds,
+		"maxFileSize":         opts.MaxFileSize,
+		"maxDocuments":        opts.MaxDocuments,
+		"maxDepth":            opts.MaxDepth,
+		"allowAliases":        opts.AllowAliases,
+		"allowMergeKeys":      opts.AllowMergeKeys,
+		"redact":              opts.Redact,
+		"sourceExcerpts":      opts.SourceExcerpts,
+		"sensitiveKinds":      sensitiveKinds,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode options: %w", err)
+	}
+
+	return contentDigest(data), nil
+}
@@ -0,0 +1,153 @@
+package yaml_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestProvenance(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should describe sources, inputs and outputs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var provenance yaml.Provenance
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Name: "all", FS: testFS, Path: "*.yaml"}},
+			yaml.WithProvenance(func(_ context.Context, p yaml.Provenance) error {
+				provenance = p
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(provenance.Type).To(Equal(yaml.ProvenanceStatementType))
+		g.Expect(provenance.PredicateType).To(Equal(yaml.ProvenancePredicateType))
+
+		g.Expect(provenance.Subject).To(HaveLen(len(objects)))
+		g.Expect(provenance.Subject[2].Name).To(Equal("v1/Pod//test-pod"))
+
+		data, err := yaml.CanonicalJSON(objects[2])
+		g.Expect(err).ToNot(HaveOccurred())
+		sum := sha256.Sum256(data)
+		g.Expect(provenance.Subject[2].Digest).To(Equal(map[string]string{"sha256": hex.EncodeToString(sum[:])}))
+
+		build := provenance.Predicate.BuildDefinition
+		g.Expect(build.BuildType).To(Equal(yaml.ProvenanceBuildType))
+		g.Expect(build.ExternalParameters.Sources).To(Equal([]yaml.ProvenanceSource{{Name: "all", Pattern: "*.yaml"}}))
+		g.Expect(build.ExternalParameters.OptionsFingerprint).To(HavePrefix("sha256:"))
+
+		podSum := sha256.Sum256([]byte(podYAML))
+		g.Expect(build.ResolvedDependencies).To(HaveLen(2))
+		g.Expect(build.ResolvedDependencies[1]).To(Equal(yaml.ResourceDescriptor{
+			Name:   "pod.yaml",
+			Digest: map[string]string{"sha256": hex.EncodeToString(podSum[:])},
+		}))
+
+		details := provenance.Predicate.RunDetails
+		g.Expect(details.Builder.ID).To(Equal("https://github.com/k8s-manifest-kit/renderer-yaml"))
+		g.Expect(details.Metadata.FinishedOn).ToNot(BeTemporally("<", details.Metadata.StartedOn))
+
+		_, err = json.Marshal(provenance)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("should change the options fingerprint with options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fingerprints := make([]string, 0, 2)
+		handler := func(_ context.Context, p yaml.Provenance) error {
+			fingerprints = append(fingerprints, p.Predicate.BuildDefinition.ExternalParameters.OptionsFingerprint)
+
+			return nil
+		}
+
+		for _, annotations := range []bool{false, true} {
+			renderer, err := yaml.New(
+				[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+				yaml.WithSourceAnnotations(annotations),
+				yaml.WithProvenance(handler),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		g.Expect(fingerprints).To(HaveLen(2))
+		g.Expect(fingerprints[0]).ToNot(Equal(fingerprints[1]))
+	})
+
+	t.Run("should describe the inputs of cached renders", func(t *testing.T) {
+		clustersFS := fstest.MapFS{
+			"clusters/a/pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+			"clusters/b/cm.yaml":  &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		for name, opts := range map[string][]yaml.RendererOption{
+			"deep copy":   nil,
+			"compression": {yaml.WithCacheCompression(true)},
+			"size cap":    {yaml.WithCacheMaxBytes(1 << 20)},
+		} {
+			t.Run(name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				var dependencies []string
+
+				renderer, err := yaml.New(
+					[]yaml.Source{{FS: clustersFS, Path: "clusters/${CLUSTER}/*.yaml"}},
+					append(opts,
+						yaml.WithCache(),
+						yaml.WithProvenance(func(_ context.Context, p yaml.Provenance) error {
+							dependencies = dependencies[:0]
+							for _, dep := range p.Predicate.BuildDefinition.ResolvedDependencies {
+								dependencies = append(dependencies, dep.Name)
+							}
+
+							return nil
+						}),
+					)...,
+				)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				for _, cluster := range []string{"a", "b", "a"} {
+					_, err = renderer.Process(t.Context(), map[string]any{"CLUSTER": cluster})
+					g.Expect(err).ToNot(HaveOccurred())
+				}
+
+				g.Expect(dependencies).To(Equal([]string{"clusters/a/pod.yaml"}))
+			})
+		}
+	})
+
+	t.Run("should fail the render when the handler fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithProvenance(func(_ context.Context, _ yaml.Provenance) error {
+				return errRejected
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRejected))
+	})
+}
@@ -0,0 +1,214 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RedactedValue replaces sensitive values in errors and redacted objects.
+const RedactedValue = "[REDACTED]"
+
+// minRedactLength is the minimum length of a sensitive value scrubbed anywhere in error messages.
+// Shorter values are too likely to collide with unrelated text (e.g. line numbers), so they are
+// only scrubbed where they appear as a whole quoted fragment.
+const minRedactLength = 4
+
+// redactQuotes are the quotes around value fragments in error messages.
+var redactQuotes = []string{"`", `"`, "'"}
+
+// redactClasses are the error classes kept in the chain of redacted errors.
+var redactClasses = []error{ErrParse, ErrValidation, ErrSourceUnavailable, ErrNoMatch}
+
+// sensitiveFields are the top-level fields holding sensitive values in sensitive kinds.
+var sensitiveFields = []string{"data", "stringData"}
+
+var (
+	// quotedFragment matches the value fragments quoted by YAML decoding errors.
+	quotedFragment = regexp.MustCompile("`[^`]*`")
+
+	// kindLine matches a kind declaration in YAML content: a block-style key at column 0, a quoted
+	// key at the start of a line (e.g. indented JSON) or a key inside a flow mapping or JSON object.
+	kindLine = regexp.MustCompile(`(?m)(?:^|^\s*["']|[{,]\s*["']?)kind["']?\s*:\s*["']?([A-Za-z0-9]+)`)
+)
+
+// DefaultSensitiveKinds returns the kinds whose data and stringData values are redacted by default.
+func DefaultSensitiveKinds() []schema.GroupKind {
+	return []schema.GroupKind{{Group: "", Kind: "Secret"}}
+}
+
+// Redact returns a deep copy of obj with the values of its data and stringData fields replaced by
+// RedactedValue if obj is of one of the given kinds (DefaultSensitiveKinds if none are given).
+// It is intended for logging and debug output of rendered objects.
+func Redact(obj unstructured.Unstructured, kinds ...schema.GroupKind) unstructured.Unstructured {
+	if len(kinds) == 0 {
+		kinds = DefaultSensitiveKinds()
+	}
+
+	out := *obj.DeepCopy()
+	if !isSensitive(obj, kinds) {
+		return out
+	}
+
+	for _, field := range sensitiveFields {
+		values, ok := out.Object[field].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for k := range values {
+			values[k] = RedactedValue
+		}
+	}
+
+	return out
+}
+
+// isSensitive reports whether obj is of one of the given kinds.
+func isSensitive(obj unstructured.Unstructured, kinds []schema.GroupKind) bool {
+	return slices.Contains(kinds, obj.GroupVersionKind().GroupKind())
+}
+
+// redactedError is an error whose message has sensitive values removed.
+// Only the error class of the original error is in its chain, so errors.As and errors.Unwrap
+// cannot reach the unredacted error. errors.Is still matches sentinels of the original chain.
+type redactedError struct {
+	msg   string
+	class error
+	err   error
+}
+
+// newRedactedError returns err with message msg, keeping its error class.
+func newRedactedError(msg string, err error) error {
+	e := &redactedError{msg: msg, err: err}
+
+	for _, class := range redactClasses {
+		if errors.Is(err, class) {
+			e.class = class
+
+			break
+		}
+	}
+
+	return e
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Is reports whether target is in the chain of the original error.
+func (e *redactedError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.class
+}
+
+// redactError removes the data and stringData values of sensitive objects from the error message.
+// Values of data fields are removed both base64-encoded and decoded.
+func (r *Renderer) redactError(err error, objects []unstructured.Unstructured) error {
+	if err == nil || !r.opts.Redact {
+		return err
+	}
+
+	values := make([]string, 0)
+
+	for _, obj := range objects {
+		if !isSensitive(obj, r.opts.SensitiveKinds) {
+			continue
+		}
+
+		for _, field := range sensitiveFields {
+			fields, _, _ := unstructured.NestedMap(obj.Object, field)
+			for _, v := range fields {
+				s, ok := v.(string)
+				if !ok {
+					continue
+				}
+
+				values = append(values, s)
+
+				if field == "data" {
+					if decoded, err := base64.StdEncoding.DecodeString(s); err == nil {
+						values = append(values, string(decoded))
+					}
+				}
+			}
+		}
+	}
+
+	msg := err.Error()
+	redacted := msg
+
+	// Replace longer values first, so a value containing another one is fully removed
+	slices.SortFunc(values, func(a, b string) int { return len(b) - len(a) })
+
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		if len(v) >= minRedactLength {
+			redacted = strings.ReplaceAll(redacted, v, RedactedValue)
+
+			continue
+		}
+
+		for _, q := range redactQuotes {
+			redacted = strings.ReplaceAll(redacted, q+v+q, q+RedactedValue+q)
+		}
+	}
+
+	if redacted == msg {
+		return err
+	}
+
+	return newRedactedError(redacted, err)
+}
+
+// redactDecodeError removes quoted value fragments from a decoding error if the content
+// declares a sensitive kind. The objects are not available when decoding fails, so the
+// content is scanned for kind declarations instead.
+func (r *Renderer) redactDecodeError(err error, content []byte) error {
+	if err == nil || !r.opts.Redact {
+		return err
+	}
+
+	if !r.sensitiveContent(content) {
+		return err
+	}
+
+	msg := err.Error()
+	redacted := quotedFragment.ReplaceAllString(msg, "`"+RedactedValue+"`")
+
+	if redacted == msg {
+		return err
+	}
+
+	return newRedactedError(redacted, err)
+}
+
+// sensitiveContent reports whether redaction is enabled and YAML content declares a sensitive kind.
+func (r *Renderer) sensitiveContent(content []byte) bool {
+	if !r.opts.Redact {
+		return false
+	}
+
+	for _, m := range kindLine.FindAllSubmatch(content, -1) {
+		if slices.ContainsFunc(r.opts.SensitiveKinds, func(gk schema.GroupKind) bool {
+			return bytes.Equal(m[1], []byte(gk.Kind))
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
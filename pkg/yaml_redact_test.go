@@ -0,0 +1,268 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const secretYAML = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+data:
+  password: c3VwZXJzZWNyZXQ=
+stringData:
+  token: plain-token-value
+`
+
+const credentialsYAML = `
+apiVersion: example.com/v1
+kind: Credentials
+metadata:
+  name: credentials
+data:
+  apiKey: custom-api-key
+`
+
+// dumpingValidator rejects every object with an error embedding the whole object.
+func dumpingValidator(_ context.Context, obj unstructured.Unstructured) error {
+	return fmt.Errorf("%w: %v", errRejected, obj.Object)
+}
+
+func TestRedact(t *testing.T) {
+
+	t.Run("should redact data and stringData of secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decodeObjects(secretYAML)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		redacted := yaml.Redact(objects[0])
+		g.Expect(redacted.Object["data"]).To(Equal(map[string]any{"password": yaml.RedactedValue}))
+		g.Expect(redacted.Object["stringData"]).To(Equal(map[string]any{"token": yaml.RedactedValue}))
+		g.Expect(objects[0].Object["data"]).To(Equal(map[string]any{"password": "c3VwZXJzZWNyZXQ="}))
+	})
+
+	t.Run("should leave other kinds untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := decodeObjects(configMapYAML)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(yaml.Redact(objects[0])).To(Equal(objects[0]))
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	testFS := fstest.MapFS{
+		"secret.yaml":      &fstest.MapFile{Data: []byte(secretYAML)},
+		"credentials.yaml": &fstest.MapFile{Data: []byte(credentialsYAML)},
+		"invalid.yaml":     &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Secret\ndata:\n  password: !!int c2VjcmV0\n")},
+	}
+
+	t.Run("should redact secret values from validation errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "secret.yaml"}},
+			yaml.WithValidator(dumpingValidator),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRejected))
+		g.Expect(err.Error()).To(ContainSubstring(yaml.RedactedValue))
+		g.Expect(err.Error()).ToNot(ContainSubstring("c3VwZXJzZWNyZXQ="))
+		g.Expect(err.Error()).ToNot(ContainSubstring("supersecret"))
+		g.Expect(err.Error()).ToNot(ContainSubstring("plain-token-value"))
+	})
+
+	t.Run("should redact secret values from decoding errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "invalid.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).ToNot(ContainSubstring("c2VjcmV0"))
+	})
+
+	t.Run("should redact secret values from ambiguous scalar reports", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pinFS := fstest.MapFS{
+			"pin.yaml": &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: pin\nstringData:\n  pin: 01234567\n")},
+		}
+
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: pinFS, Path: "pin.yaml"}},
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "ambiguous-scalar"),
+			HaveField("Line", 6),
+			HaveField("Message", Not(Or(ContainSubstring("1234567"), ContainSubstring("342391")))),
+		)))
+
+		renderer, err = yaml.New(
+			[]yaml.Source{{FS: pinFS, Path: "pin.yaml"}},
+			yaml.WithAmbiguousScalarPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrAmbiguousScalar))
+		g.Expect(err.Error()).ToNot(ContainSubstring("1234567"))
+	})
+
+	t.Run("should redact secret values from lossy number reports", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tokenFS := fstest.MapFS{
+			"token.yaml": &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\nstringData:\n  token: 123456789012345678901234567\n")},
+		}
+
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: tokenFS, Path: "token.yaml"}},
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "lossy-number"),
+			HaveField("Line", 6),
+			HaveField("Message", Not(ContainSubstring("123456789012345678901234567"))),
+		)))
+
+		renderer, err = yaml.New(
+			[]yaml.Source{{FS: tokenFS, Path: "token.yaml"}},
+			yaml.WithLossyNumberPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLossyNumber))
+		g.Expect(err.Error()).ToNot(ContainSubstring("123456789012345678901234567"))
+	})
+
+	t.Run("should not keep the unredacted error in the chain", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "secret.yaml"}},
+			yaml.WithValidator(dumpingValidator),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrValidation))
+		g.Expect(err).To(MatchError(errRejected))
+
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			g.Expect(e.Error()).ToNot(ContainSubstring("plain-token-value"))
+		}
+	})
+
+	t.Run("should redact short values quoted in errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pinFS := fstest.MapFS{
+			"pin.yaml": &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: pin\nstringData:\n  pin: \"x7\"\n")},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: pinFS, Path: "pin.yaml"}},
+			yaml.WithValidator(func(_ context.Context, obj unstructured.Unstructured) error {
+				pin, _, _ := unstructured.NestedString(obj.Object, "stringData", "pin")
+
+				return fmt.Errorf("%w: pin %q is too short", errRejected, pin)
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRejected))
+		g.Expect(err.Error()).To(ContainSubstring(`pin "` + yaml.RedactedValue + `" is too short`))
+		g.Expect(err.Error()).ToNot(ContainSubstring(`"x7"`))
+	})
+
+	t.Run("should redact decoding errors of JSON and flow-style secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flowFS := fstest.MapFS{
+			"json.yaml": &fstest.MapFile{Data: []byte(`{"apiVersion": "v1", "kind": "Secret", "data": {"password": !!int c2VjcmV0}}`)},
+			"flow.yaml": &fstest.MapFile{Data: []byte("{apiVersion: v1, kind: Secret, data: {password: !!int c2VjcmV0}}\n")},
+		}
+
+		for _, path := range []string{"json.yaml", "flow.yaml"} {
+			renderer, err := yaml.New([]yaml.Source{{FS: flowFS, Path: path}})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = renderer.Process(t.Context(), nil)
+			g.Expect(err).To(MatchError(yaml.ErrParse))
+			g.Expect(err.Error()).ToNot(ContainSubstring("c2VjcmV0"), path)
+		}
+	})
+
+	t.Run("should redact configured sensitive kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "credentials.yaml"}},
+			yaml.WithValidator(dumpingValidator),
+			yaml.WithSensitiveKinds(schema.GroupKind{Group: "example.com", Kind: "Credentials"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).ToNot(ContainSubstring("custom-api-key"))
+	})
+
+	t.Run("should keep values when redaction is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "secret.yaml"}},
+			yaml.WithValidator(dumpingValidator),
+			yaml.WithRedaction(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(errors.Is(err, errRejected)).To(BeTrue())
+		g.Expect(err.Error()).To(ContainSubstring("plain-token-value"))
+	})
+}
+
+func decodeObjects(content string) ([]unstructured.Unstructured, error) {
+	renderer, err := yaml.NewFromStrings(map[string]string{"manifest.yaml": content})
+	if err != nil {
+		return nil, err
+	}
+
+	return renderer.Process(context.Background(), nil)
+}
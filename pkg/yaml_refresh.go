@@ -0,0 +1,92 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// cacheRefreshKey marks renders that bypass cache lookups to replace cache entries.
+type cacheRefreshKey struct{}
+
+// isCacheRefresh reports whether ctx belongs to a cache refresh.
+func isCacheRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(cacheRefreshKey{}).(bool)
+
+	return refresh
+}
+
+// Refresh re-reads every cached source and replaces its cache entry, resetting its TTL, so
+// subsequent renders hit recent data. Sources without a cache, Objects sources and sources with
+// ${NAME} path placeholders (which need render-time values) are skipped. Filters, transformers
+// and validators do not run, as they are applied on every render. Errors of individual sources
+// are joined; their previous cache entries are kept until they expire.
+func (r *Renderer) Refresh(ctx context.Context) error {
+	r.mu.RLock()
+	inputs := r.inputs
+	r.mu.RUnlock()
+
+	ctx = context.WithValue(ctx, cacheRefreshKey{}, true)
+	ctx = withWarningHandler(ctx, r.opts.WarningHandler)
+	ctx = withPipelineValues(ctx, r.opts.PipelineValues)
+
+	var errs []error
+
+	for _, holder := range inputs {
+		if holder.Objects != nil || r.cacheFor(holder) == nil || hasPathVariables(holder.Path) {
+			continue
+		}
+
+		if err := checkContext(ctx); err != nil {
+			return r.labelError(err)
+		}
+
+		if _, _, err := r.renderSingle(ctx, holder); err != nil {
+			errs = append(errs, fmt.Errorf("error refreshing YAML pattern %s: %w", holder.Path, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return r.labelError(errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// StartRefresh calls Refresh every interval in a background goroutine until ctx is done, so
+// read-heavy consumers always hit warm, recent cache entries. Use an interval shorter than the
+// cache TTL to keep entries from expiring. Refresh errors are reported as "refresh-error"
+// warnings to the handler set with WithWarningHandler.
+//
+// Example:
+//
+//	if err := r.StartRefresh(ctx, time.Minute); err != nil {
+//		return err
+//	}
+func (r *Renderer) StartRefresh(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("refresh interval must be positive, got %s", interval)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+					ReportWarning(
+						withWarningHandler(ctx, r.opts.WarningHandler),
+						Warning{Rule: "refresh-error", Message: err.Error()},
+					)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRefresh(t *testing.T) {
+	kinds := func(g Gomega, renderer *yaml.Renderer) []string {
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result := make([]string, len(objects))
+		for i := range objects {
+			result[i] = objects[i].GetKind()
+		}
+
+		return result
+	}
+
+	t.Run("should replace cache entries", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{"app.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithCache(cache.WithTTL(time.Hour)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kinds(g, renderer)).To(Equal([]string{"Pod"}))
+
+		testFS["app.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+		g.Expect(kinds(g, renderer)).To(Equal([]string{"Pod"}))
+
+		g.Expect(renderer.Refresh(t.Context())).To(Succeed())
+		g.Expect(kinds(g, renderer)).To(Equal([]string{"ConfigMap"}))
+	})
+
+	t.Run("should report failing sources", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{"app.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithCache(cache.WithTTL(time.Hour)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kinds(g, renderer)).To(Equal([]string{"Pod"}))
+
+		testFS["app.yaml"] = &fstest.MapFile{Data: []byte("\x00binary")}
+		g.Expect(renderer.Refresh(t.Context())).To(MatchError(yaml.ErrBinaryContent))
+
+		// The previous entry is kept
+		g.Expect(kinds(g, renderer)).To(Equal([]string{"Pod"}))
+	})
+
+	t.Run("should refresh periodically", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{"app.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithCache(cache.WithTTL(time.Hour)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kinds(g, renderer)).To(Equal([]string{"Pod"}))
+
+		testFS["app.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+
+		g.Expect(renderer.StartRefresh(t.Context(), 10*time.Millisecond)).To(Succeed())
+		g.Eventually(func(g Gomega) []string { return kinds(g, renderer) }).Should(Equal([]string{"ConfigMap"}))
+	})
+
+	t.Run("should reject invalid intervals", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.StartRefresh(t.Context(), 0)).ToNot(Succeed())
+	})
+}
@@ -0,0 +1,141 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// RetryPolicy configures retries of transient read failures, e.g. on network filesystems.
+// Only filesystem operations (stat, open, read) are retried; parse and validation errors are not.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per operation, including the first one.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry; it doubles after every retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries. 0 = no cap.
+	MaxBackoff time.Duration
+
+	// Retryable decides whether an error is transient. nil = IsRetryable.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and exponential backoff from 100ms up to 2s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// IsRetryable reports whether an error is a filesystem or I/O error that may be transient. Only
+// path errors (as returned by fs.FS operations), unexpected EOFs and errors reporting a timeout
+// are retried. Errors that retrying cannot fix (missing files, permissions, invalid paths, closed
+// files, context cancellation, and parse, validation and pattern errors) are permanent.
+func IsRetryable(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, ErrParse),
+		errors.Is(err, ErrValidation),
+		errors.Is(err, ErrInvalidPattern),
+		errors.Is(err, path.ErrBadPattern),
+		errors.Is(err, fs.ErrNotExist),
+		errors.Is(err, fs.ErrPermission),
+		errors.Is(err, fs.ErrInvalid),
+		errors.Is(err, fs.ErrClosed),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// retry runs op, retrying transient failures according to the configured policy.
+// Each retry is reported as a "read-retry" warning.
+func (r *Renderer) retry(ctx context.Context, file string, op func() error) error {
+	policy := r.opts.Retry
+	if policy == nil {
+		return op()
+	}
+
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		ReportWarning(ctx, Warning{
+			File:    file,
+			Rule:    "read-retry",
+			Message: fmt.Sprintf("attempt %d of %d failed, retrying in %s: %v", attempt, policy.MaxAttempts, backoff, err),
+		})
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// retryFS retries whole-file reads (fs.ReadFile) of an underlying filesystem.
+// It is used for auxiliary files such as ignore files and checksum manifests.
+type retryFS struct {
+	fs.FS
+
+	ctx context.Context //nolint:containedctx // scoped to a single render
+	r   *Renderer
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (f retryFS) ReadFile(name string) ([]byte, error) {
+	var content []byte
+
+	err := f.r.retry(f.ctx, name, func() error {
+		var err error
+		content, err = fs.ReadFile(f.FS, name)
+
+		return err
+	})
+
+	return content, err
+}
+
+// retryingFS returns fsys with whole-file reads retried according to the retry policy.
+func (r *Renderer) retryingFS(ctx context.Context, fsys fs.FS) fs.FS {
+	if r.opts.Retry == nil {
+		return fsys
+	}
+
+	return retryFS{FS: fsys, ctx: ctx, r: r}
+}
@@ -0,0 +1,139 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+var errTransient = errors.New("i/o timeout")
+
+// flakyFS fails the first failures calls to Open with err.
+type flakyFS struct {
+	fs.FS
+
+	failures int32
+	err      error
+	opens    atomic.Int32
+}
+
+func (f *flakyFS) Open(name string) (fs.File, error) {
+	if f.opens.Add(1) <= f.failures {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: f.err}
+	}
+
+	return f.FS.Open(name)
+}
+
+func TestRetry(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	policy := yaml.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("should retry transient failures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyFS{FS: testFS, failures: 2, err: errTransient}
+		warnings := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: flaky, Path: "pod.yaml"}},
+			yaml.WithRetry(policy),
+			yaml.WithWarningHandler(warnings.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(warnings.Warnings()).To(HaveLen(2))
+		g.Expect(warnings.Warnings()[0].Rule).To(Equal("read-retry"))
+	})
+
+	t.Run("should give up after the maximum number of attempts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyFS{FS: testFS, failures: 5, err: errTransient}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: flaky, Path: "pod.yaml"}},
+			yaml.WithRetry(policy),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errTransient))
+		g.Expect(flaky.opens.Load()).To(BeEquivalentTo(3))
+	})
+
+	t.Run("should not retry permanent failures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyFS{FS: testFS, failures: 1, err: fs.ErrPermission}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: flaky, Path: "pod.yaml"}},
+			yaml.WithRetry(policy),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(fs.ErrPermission))
+		g.Expect(flaky.opens.Load()).To(BeEquivalentTo(1))
+	})
+
+	t.Run("should only retry transient filesystem errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(yaml.IsRetryable(&fs.PathError{Op: "read", Path: "pod.yaml", Err: errTransient})).To(BeTrue())
+		g.Expect(yaml.IsRetryable(fmt.Errorf("failed to read file: %w", io.ErrUnexpectedEOF))).To(BeTrue())
+
+		g.Expect(yaml.IsRetryable(errTransient)).To(BeFalse())
+		g.Expect(yaml.IsRetryable(&fs.PathError{Op: "open", Path: "pod.yaml", Err: fs.ErrNotExist})).To(BeFalse())
+		g.Expect(yaml.IsRetryable(fmt.Errorf("%w: %w", yaml.ErrParse, &fs.PathError{Op: "read", Err: errTransient}))).To(BeFalse())
+		g.Expect(yaml.IsRetryable(fmt.Errorf("%w: %w", yaml.ErrValidation, errTransient))).To(BeFalse())
+		g.Expect(yaml.IsRetryable(fmt.Errorf("%w: [", yaml.ErrInvalidPattern))).To(BeFalse())
+	})
+
+	t.Run("should not retry without a policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyFS{FS: testFS, failures: 1, err: errTransient}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: flaky, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errTransient))
+	})
+
+	t.Run("should stop waiting when the context is canceled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		flaky := &flakyFS{FS: testFS, failures: 5, err: errTransient}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: flaky, Path: "pod.yaml"}},
+			yaml.WithRetry(yaml.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+}
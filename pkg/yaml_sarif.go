@@ -0,0 +1,136 @@
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+)
+
+const (
+	// sarifVersion is the version of the SARIF format written by WriteSARIF.
+	sarifVersion = "2.1.0"
+
+	// sarifSchema is the JSON schema of the SARIF format written by WriteSARIF.
+	sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF writes diagnostics (see Diagnose) as a SARIF 2.1.0 log, so code scanning UIs such
+// as GitHub and GitLab can annotate manifest changes. Each diagnostic becomes a result of its
+// rule; files are reported relative to baseDir, the directory of the source filesystem within
+// the scanned repository ("" for the repository root). Diagnostics without a file have no
+// location.
+//
+// Example:
+//
+//	diagnostics, _ := r.Diagnose(ctx, nil)
+//	_ = yaml.WriteSARIF(f, diagnostics, "deploy/manifests")
+func WriteSARIF(w io.Writer, diagnostics []Diagnostic, baseDir string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           modulePath,
+			Version:        rendererVersion(),
+			InformationURI: "https://" + modulePath,
+			Rules:          []sarifRule{},
+		}},
+		Results: make([]sarifResult, 0, len(diagnostics)),
+	}
+
+	for _, d := range diagnostics {
+		if !slices.ContainsFunc(run.Tool.Driver.Rules, func(rule sarifRule) bool { return rule.ID == d.Rule }) {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: d.Rule})
+		}
+
+		result := sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifMessage{Text: d.Message},
+		}
+
+		if d.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: path.Join(baseDir, d.File)},
+			}}
+			if d.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: d.Line}
+			}
+
+			result.Locations = []sarifLocation{location}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}); err != nil {
+		return fmt.Errorf("failed to encode SARIF log: %w", err)
+	}
+
+	return nil
+}
+
+// sarifLevel maps a diagnostic severity to a SARIF result level.
+func sarifLevel(severity Severity) string {
+	if severity == SeverityWarning {
+		return "warning"
+	}
+
+	return "error"
+}
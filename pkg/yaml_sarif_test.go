@@ -0,0 +1,76 @@
+package yaml_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	t.Run("should write diagnostics as SARIF results", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		err := yaml.WriteSARIF(&buf, []yaml.Diagnostic{
+			{File: "pod.yaml", Line: 3, Severity: yaml.SeverityWarning, Rule: "lossy-number", Message: "line 3: lossy"},
+			{File: "pod.yaml", Severity: yaml.SeverityError, Rule: "validation", Message: "privileged"},
+			{Severity: yaml.SeverityError, Rule: "validation", Message: "no files matched pattern"},
+		}, "deploy")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var log map[string]any
+		g.Expect(json.Unmarshal(buf.Bytes(), &log)).To(Succeed())
+		g.Expect(log).To(HaveKeyWithValue("version", "2.1.0"))
+
+		runs, _ := log["runs"].([]any)
+		g.Expect(runs).To(HaveLen(1))
+
+		run, _ := runs[0].(map[string]any)
+		g.Expect(run).To(HaveKeyWithValue("tool", HaveKeyWithValue("driver", HaveKeyWithValue("rules", []any{
+			map[string]any{"id": "lossy-number"},
+			map[string]any{"id": "validation"},
+		}))))
+		g.Expect(run["results"]).To(HaveExactElements(
+			map[string]any{
+				"ruleId":  "lossy-number",
+				"level":   "warning",
+				"message": map[string]any{"text": "line 3: lossy"},
+				"locations": []any{map[string]any{"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": "deploy/pod.yaml"},
+					"region":           map[string]any{"startLine": float64(3)},
+				}}},
+			},
+			map[string]any{
+				"ruleId":  "validation",
+				"level":   "error",
+				"message": map[string]any{"text": "privileged"},
+				"locations": []any{map[string]any{"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": "deploy/pod.yaml"},
+				}}},
+			},
+			map[string]any{
+				"ruleId":  "validation",
+				"level":   "error",
+				"message": map[string]any{"text": "no files matched pattern"},
+			},
+		))
+	})
+
+	t.Run("should write validation findings of a render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{"broken.yaml": malformedConfigMapYAML})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		diagnostics, _ := renderer.Diagnose(t.Context(), nil)
+
+		var buf bytes.Buffer
+		g.Expect(yaml.WriteSARIF(&buf, diagnostics, "")).To(Succeed())
+		g.Expect(buf.String()).To(ContainSubstring(`"uri": "broken.yaml"`))
+		g.Expect(buf.String()).To(ContainSubstring(`"startLine": 5`))
+	})
+}
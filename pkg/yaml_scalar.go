@@ -0,0 +1,372 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	goyaml "gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrAmbiguousScalar is returned when a plain scalar has a different meaning in YAML 1.1 and 1.2
+	// and ambiguous scalars are rejected.
+	ErrAmbiguousScalar = newClassError(ErrParse, "ambiguous YAML scalar")
+
+	// ErrLossyNumber is returned when a number cannot be represented exactly in an unstructured
+	// object and lossy numbers are rejected.
+	ErrLossyNumber = newClassError(ErrParse, "number cannot be represented exactly")
+)
+
+// ScalarResolution controls how plain scalars whose meaning differs between YAML 1.1 and
+// YAML 1.2 are interpreted, e.g. the "Norway problem" where an unquoted NO is a boolean.
+type ScalarResolution int
+
+const (
+	// ScalarResolutionKubernetes interprets ambiguous scalars like kubectl and the Kubernetes API
+	// server, which follow YAML 1.1: y, yes, on, n, no, off (in any common casing) are booleans
+	// and numbers with a leading zero are octal. Booleans are kept as strings in fields that only
+	// accept strings, such as labels and ConfigMap data.
+	ScalarResolutionKubernetes ScalarResolution = iota
+
+	// ScalarResolutionYAML12 interprets ambiguous scalars following the YAML 1.2 core schema:
+	// y, yes, on, n, no, off are strings and numbers with a leading zero are decimal.
+	ScalarResolutionYAML12
+)
+
+const (
+	boolTag  = "!!bool"
+	intTag   = "!!int"
+	floatTag = "!!float"
+	strTag   = "!!str"
+)
+
+// yaml11Booleans maps the plain scalars that are booleans in YAML 1.1 but strings in YAML 1.2
+// to their boolean value.
+var yaml11Booleans = map[string]string{
+	"y": "true", "Y": "true", "yes": "true", "Yes": "true", "YES": "true",
+	"on": "true", "On": "true", "ON": "true",
+	"n": "false", "N": "false", "no": "false", "No": "false", "NO": "false",
+	"off": "false", "Off": "false", "OFF": "false",
+}
+
+// stringFields are the fields of all kinds whose values must be strings.
+var stringFields = [][]string{{"metadata", "labels"}, {"metadata", "annotations"}}
+
+// coreStringFields are the fields of core (v1) kinds whose values must be strings.
+var coreStringFields = map[string][][]string{
+	"ConfigMap": {{"data"}, {"binaryData"}},
+	"Secret":    {{"data"}, {"stringData"}},
+}
+
+// scalarScope is the position of a node in the document being resolved.
+type scalarScope struct {
+	// field is the path of mapping keys leading to the node.
+	field []string
+
+	// stringFields are the fields of the document whose values must be strings.
+	stringFields [][]string
+
+	// redact replaces scalar values with RedactedValue in warnings and errors.
+	redact bool
+}
+
+// child returns the scope of the value of key in a mapping node of the scope.
+func (s scalarScope) child(key string) scalarScope {
+	s.field = append(slices.Clip(s.field), key)
+
+	return s
+}
+
+// stringOnly reports whether the scope is within a field whose values must be strings.
+func (s scalarScope) stringOnly() bool {
+	for _, f := range s.stringFields {
+		if len(s.field) >= len(f) && slices.Equal(s.field[:len(f)], f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// documentStringFields returns the fields of a document whose values must be strings.
+func documentStringFields(doc *goyaml.Node) [][]string {
+	if doc.Kind == goyaml.DocumentNode && len(doc.Content) == 1 {
+		doc = doc.Content[0]
+	}
+
+	var apiVersion, kind string
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		switch doc.Content[i].Value {
+		case "apiVersion":
+			apiVersion = doc.Content[i+1].Value
+		case "kind":
+			kind = doc.Content[i+1].Value
+		}
+	}
+
+	if apiVersion != "v1" {
+		return stringFields
+	}
+
+	return slices.Concat(stringFields, coreStringFields[kind])
+}
+
+// scalarCandidate matches lines that may hold an ambiguous plain scalar value, and runs of
+// digits long enough to exceed the precision of a float64. It is a cheap pre-check:
+// false positives only cost a node scan.
+var scalarCandidate = regexp.MustCompile(
+	`(?m)(?:^|[\s\[{,])(?:[yYnN]|[yY]es|YES|[oO]n|ON|[nN]o|NO|[oO]ff|OFF|[-+]?0[0-9_]+)\s*(?:[,\]}#]|$)|[0-9][0-9_.]{15,}`,
+)
+
+// resolveScalars applies the scalar resolution, ambiguous scalar and lossy number policies
+// to YAML content. It returns the content unchanged if no scalar needs rewriting.
+func (r *Renderer) resolveScalars(ctx context.Context, path string, content []byte) ([]byte, error) {
+	if !scalarCandidate.Match(content) {
+		return content, nil
+	}
+
+	dec := goyaml.NewDecoder(bytes.NewReader(normalizeYAML(content)))
+
+	// Values of sensitive kinds are left out of warnings and errors
+	redact := r.sensitiveContent(content)
+
+	var documents []*goyaml.Node
+	rewrite := false
+
+	for {
+		var node goyaml.Node
+
+		err := dec.Decode(&node)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			// Syntax errors are reported with more context by the regular decoder
+			return content, nil //nolint:nilerr // decoding errors are not scalar issues
+		}
+
+		scope := scalarScope{stringFields: documentStringFields(&node), redact: redact}
+
+		changed, err := r.resolveNode(ctx, path, &node, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		rewrite = rewrite || changed
+		documents = append(documents, &node)
+	}
+
+	if !rewrite {
+		return content, nil
+	}
+
+	var buf bytes.Buffer
+
+	enc := goyaml.NewEncoder(&buf)
+	for _, doc := range documents {
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode resolved YAML: %w", err)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode resolved YAML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resolveNode recursively resolves ambiguous plain scalar values of a node. Mapping keys are
+// left untouched, as Kubernetes object keys are always strings. It reports whether any node
+// was rewritten.
+func (r *Renderer) resolveNode(ctx context.Context, path string, node *goyaml.Node, scope scalarScope) (bool, error) {
+	if node.Kind == goyaml.ScalarNode {
+		return r.resolveScalar(ctx, path, node, scope)
+	}
+
+	changed := false
+
+	for i, child := range node.Content {
+		if node.Kind == goyaml.MappingNode && i%2 == 0 {
+			continue
+		}
+
+		childScope := scope
+		if node.Kind == goyaml.MappingNode {
+			childScope = scope.child(node.Content[i-1].Value)
+		}
+
+		c, err := r.resolveNode(ctx, path, child, childScope)
+		if err != nil {
+			return false, err
+		}
+
+		changed = changed || c
+	}
+
+	return changed, nil
+}
+
+// resolveScalar resolves a single scalar node, reporting whether it was rewritten. YAML 1.1
+// booleans in fields whose values must be strings (e.g. ConfigMap data) are left as strings,
+// as the API server rejects booleans there.
+func (r *Renderer) resolveScalar(ctx context.Context, path string, node *goyaml.Node, scope scalarScope) (bool, error) {
+	// Quoted, block and explicitly tagged scalars are never ambiguous
+	if node.Style != 0 {
+		return false, nil
+	}
+
+	redact := scope.redact
+
+	if isLossyNumber(node) {
+		return r.resolveLossyNumber(ctx, path, node, redact)
+	}
+
+	var yaml11, yaml12 string
+
+	boolean, isBool := yaml11Booleans[node.Value]
+	isBool = isBool && !scope.stringOnly()
+	decimal, isOctal := legacyOctal(node.Value)
+
+	switch {
+	case isBool && node.Tag == strTag && redact:
+		yaml11, yaml12 = "a boolean", "a string"
+	case isBool && node.Tag == strTag:
+		yaml11, yaml12 = "the boolean "+boolean, "a string"
+	case isOctal && node.Tag == intTag && redact:
+		yaml11, yaml12 = "an octal number", "a decimal number"
+	case isOctal && node.Tag == intTag:
+		yaml11, yaml12 = "an octal number", "the decimal number "+decimal
+	default:
+		return false, nil
+	}
+
+	msg := fmt.Sprintf(
+		"line %d: %s is %s in YAML 1.1 but %s in YAML 1.2; quote it if a string is intended",
+		node.Line,
+		scalarValue(node, redact),
+		yaml11,
+		yaml12,
+	)
+
+	switch r.opts.AmbiguousScalarPolicy {
+	case PolicyError:
+		return false, fmt.Errorf("%w: %s: %s", ErrAmbiguousScalar, path, msg)
+	case PolicyWarn:
+		ReportWarning(ctx, Warning{File: path, Line: node.Line, Rule: "ambiguous-scalar", Message: msg})
+	case PolicyIgnore:
+	}
+
+	switch {
+	case isBool && r.opts.ScalarResolution == ScalarResolutionKubernetes:
+		node.Tag, node.Value = boolTag, boolean
+	case isOctal && r.opts.ScalarResolution == ScalarResolutionYAML12:
+		node.Tag, node.Value = intTag, decimal
+	default:
+		// The decoder already resolves the value this way
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// resolveLossyNumber applies the lossy number policy to a number node, reporting whether it was
+// rewritten.
+func (r *Renderer) resolveLossyNumber(ctx context.Context, path string, node *goyaml.Node, redact bool) (bool, error) {
+	msg := fmt.Sprintf(
+		"line %d: %s does not fit a 64-bit integer or float without loss; quote it to keep the exact value",
+		node.Line,
+		scalarValue(node, redact),
+	)
+
+	switch r.opts.LossyNumberPolicy {
+	case PolicyError:
+		return false, fmt.Errorf("%w: %s: %s", ErrLossyNumber, path, msg)
+	case PolicyWarn:
+		ReportWarning(ctx, Warning{File: path, Line: node.Line, Rule: "lossy-number", Message: msg})
+	case PolicyIgnore:
+	}
+
+	if !r.opts.PreserveLossyNumbers {
+		return false, nil
+	}
+
+	node.Tag = strTag
+
+	return true, nil
+}
+
+// scalarValue returns the value of a scalar node for messages, or RedactedValue with redact.
+func scalarValue(node *goyaml.Node, redact bool) string {
+	if redact {
+		return RedactedValue
+	}
+
+	return node.Value
+}
+
+// isLossyNumber reports whether a number node cannot be represented exactly by the int64 and
+// float64 values of an unstructured object. Floats are lossy if their shortest float64
+// representation differs in value from the written decimal, not merely in notation (1.10 is 1.1).
+func isLossyNumber(node *goyaml.Node) bool {
+	value := strings.ReplaceAll(node.Value, "_", "")
+
+	switch node.Tag {
+	case intTag:
+		_, err := strconv.ParseInt(value, 0, 64)
+
+		return errors.Is(err, strconv.ErrRange)
+	case floatTag:
+		written, ok := new(big.Rat).SetString(value)
+		if !ok {
+			// Special values such as .inf and .nan
+			return false
+		}
+
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return true
+		}
+
+		parsed, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+
+		return !ok || written.Cmp(parsed) != 0
+	}
+
+	return false
+}
+
+// legacyOctal reports whether a plain scalar is a YAML 1.1 octal number (a leading zero followed
+// by octal digits) whose value differs from its YAML 1.2 decimal reading, which is returned.
+func legacyOctal(value string) (string, bool) {
+	digits := strings.ReplaceAll(strings.TrimLeft(value, "+-"), "_", "")
+	if len(digits) < 2 || digits[0] != '0' {
+		return "", false
+	}
+
+	octal, err := strconv.ParseInt(digits, 8, 64)
+	if err != nil {
+		return "", false
+	}
+
+	decimal, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || decimal == octal {
+		return "", false
+	}
+
+	if strings.HasPrefix(value, "-") {
+		decimal = -decimal
+	}
+
+	return strconv.FormatInt(decimal, 10), true
+}
@@ -0,0 +1,248 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const ambiguousScalarYAML = `
+apiVersion: example.com/v1
+kind: Settings
+metadata:
+  name: norway
+data:
+  country: NO
+  quoted: "yes"
+  mode: 0755
+  zero: 0
+  tags: [on, off, "on"]
+`
+
+const stringFieldScalarYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: norway
+  labels:
+    enabled: yes
+data:
+  country: NO
+  tags: [on, off]
+  mode: 0755
+`
+
+func TestScalarResolution(t *testing.T) {
+	testFS := fstest.MapFS{
+		"norway.yaml": &fstest.MapFile{Data: []byte(ambiguousScalarYAML)},
+	}
+
+	t.Run("should interpret ambiguous scalars like Kubernetes by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "norway.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(Equal(map[string]any{
+			"country": false,
+			"quoted":  "yes",
+			"mode":    int64(493),
+			"zero":    int64(0),
+			"tags":    []any{true, false, "on"},
+		}))
+	})
+
+	t.Run("should keep YAML 1.1 booleans as strings in string-only fields", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: fstest.MapFS{
+				"norway.yaml": &fstest.MapFile{Data: []byte(stringFieldScalarYAML)},
+			}, Path: "norway.yaml"}},
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(Equal(map[string]string{"enabled": "yes"}))
+		g.Expect(objects[0].Object["data"]).To(Equal(map[string]any{
+			"country": "NO",
+			"tags":    []any{"on", "off"},
+			"mode":    int64(493),
+		}))
+		g.Expect(collector.Warnings()).To(ConsistOf(HaveField("Line", 11)))
+	})
+
+	t.Run("should interpret ambiguous scalars as YAML 1.2", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "norway.yaml"}},
+			yaml.WithScalarResolution(yaml.ScalarResolutionYAML12),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(Equal(map[string]any{
+			"country": "NO",
+			"quoted":  "yes",
+			"mode":    int64(755),
+			"zero":    int64(0),
+			"tags":    []any{"on", "off", "on"},
+		}))
+	})
+
+	t.Run("should warn about ambiguous scalars", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "norway.yaml"}},
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(collector.Warnings()).To(HaveLen(4))
+		g.Expect(collector.Warnings()).To(HaveEach(And(
+			HaveField("File", "norway.yaml"),
+			HaveField("Rule", "ambiguous-scalar"),
+		)))
+		g.Expect(collector.Warnings()[0].Message).To(ContainSubstring("line 7: NO is the boolean false"))
+	})
+
+	t.Run("should fail on ambiguous scalars", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "norway.yaml"}},
+			yaml.WithAmbiguousScalarPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrAmbiguousScalar))
+		g.Expect(err.Error()).To(ContainSubstring("norway.yaml: line 7"))
+	})
+
+	t.Run("should leave unambiguous files untouched", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: fstest.MapFS{
+				"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+			}, Path: "pod.yaml"}},
+			yaml.WithAmbiguousScalarPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+const largeNumberYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: numbers
+data:
+  exact: 9007199254740993
+  max: 9223372036854775807
+  huge: 99999999999999999999
+  decimal: 1.10
+  memory: 1Gi
+  cpu: 500m
+`
+
+func TestLossyNumbers(t *testing.T) {
+	testFS := fstest.MapFS{
+		"numbers.yaml": &fstest.MapFile{Data: []byte(largeNumberYAML)},
+	}
+
+	t.Run("should preserve integers within int64 and quantity strings", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "numbers.yaml"}},
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(And(
+			HaveKeyWithValue("exact", int64(9007199254740993)),
+			HaveKeyWithValue("max", int64(9223372036854775807)),
+			HaveKeyWithValue("decimal", 1.1),
+			HaveKeyWithValue("memory", "1Gi"),
+			HaveKeyWithValue("cpu", "500m"),
+		))
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "lossy-number"),
+			HaveField("Message", ContainSubstring("line 9: 99999999999999999999")),
+		)))
+	})
+
+	t.Run("should keep lossy numbers as strings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "numbers.yaml"}},
+			yaml.WithPreserveLossyNumbers(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(And(
+			HaveKeyWithValue("huge", "99999999999999999999"),
+			HaveKeyWithValue("exact", int64(9007199254740993)),
+		))
+	})
+
+	t.Run("should fail on lossy numbers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "numbers.yaml"}},
+			yaml.WithLossyNumberPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrLossyNumber))
+		g.Expect(err.Error()).To(ContainSubstring("numbers.yaml: line 9"))
+	})
+
+	t.Run("should keep unsigned integers beyond int64 as strings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"max.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: max\ndata:\n  max: 18446744073709551615\n"},
+			yaml.WithPreserveLossyNumbers(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(HaveKeyWithValue("max", "18446744073709551615"))
+	})
+}
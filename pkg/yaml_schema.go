@@ -0,0 +1,299 @@
+package yaml
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// ErrSchemaViolation is returned when an object does not match its schema in a SchemaBundle.
+var ErrSchemaViolation = newClassError(ErrValidation, "schema violation")
+
+// Schema is a structural schema, the subset of OpenAPI v3 used by Kubernetes for built-in APIs
+// and CustomResourceDefinitions.
+type Schema struct {
+	// Type is "object", "array", "string", "integer", "number" or "boolean". Empty accepts any type.
+	Type string `json:"type,omitempty"`
+
+	// Properties are the known fields of an object.
+	Properties map[string]*Schema `json:"properties,omitempty"`
+
+	// AdditionalProperties is the schema of the values of a map.
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+
+	// Items is the schema of the items of an array.
+	Items *Schema `json:"items,omitempty"`
+
+	// Required are the fields an object must set.
+	Required []string `json:"required,omitempty"`
+
+	// Enum are the allowed values of a scalar.
+	Enum []any `json:"enum,omitempty"`
+
+	// PreserveUnknownFields accepts object fields not listed in Properties.
+	PreserveUnknownFields bool `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+
+	// IntOrString accepts integers and strings.
+	IntOrString bool `json:"x-kubernetes-int-or-string,omitempty"`
+}
+
+// SchemaBundle holds the schemas of the APIs of a Kubernetes version.
+//
+// Bundles are JSON files of the form:
+//
+//	{
+//	  "kubernetesVersion": "1.29",
+//	  "schemas": {
+//	    "apps/v1/Deployment": {"type": "object", "properties": {...}},
+//	    "v1/ConfigMap": {...}
+//	  }
+//	}
+type SchemaBundle struct {
+	// KubernetesVersion is the Kubernetes version described by the bundle (e.g. "1.29").
+	KubernetesVersion string `json:"kubernetesVersion"`
+
+	// Schemas maps "group/version/Kind" ("version/Kind" for the core group) to object schemas.
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// LoadSchemaBundles loads all *.json schema bundles at the root of fsys, e.g. an embed.FS
+// shipped with the application so schema validation works offline:
+//
+//	//go:embed schemas/*.json
+//	var schemas embed.FS
+//
+//	sub, _ := fs.Sub(schemas, "schemas")
+//	bundles, err := yaml.LoadSchemaBundles(sub)
+func LoadSchemaBundles(fsys fs.FS) ([]SchemaBundle, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema bundles: %w", err)
+	}
+
+	bundles := make([]SchemaBundle, 0, len(files))
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema bundle %s: %w", file, err)
+		}
+
+		var bundle SchemaBundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return nil, fmt.Errorf("failed to decode schema bundle %s: %w", file, err)
+		}
+
+		if _, err := version.ParseGeneric(bundle.KubernetesVersion); err != nil {
+			return nil, fmt.Errorf("invalid kubernetes version %q in schema bundle %s: %w", bundle.KubernetesVersion, file, err)
+		}
+
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles, nil
+}
+
+// SelectSchemaBundle returns the bundle matching the target Kubernetes version: the bundle of the
+// highest version not after the target. An empty target selects the highest version.
+func SelectSchemaBundle(bundles []SchemaBundle, targetVersion string) (SchemaBundle, error) {
+	var target *version.Version
+
+	if targetVersion != "" {
+		v, err := version.ParseGeneric(targetVersion)
+		if err != nil {
+			return SchemaBundle{}, fmt.Errorf("invalid kubernetes version %q: %w", targetVersion, err)
+		}
+
+		target = v
+	}
+
+	var (
+		selected SchemaBundle
+		best     *version.Version
+	)
+
+	for _, bundle := range bundles {
+		v, err := version.ParseGeneric(bundle.KubernetesVersion)
+		if err != nil || target != nil && !target.AtLeast(v) || best != nil && !v.GreaterThan(best) {
+			continue
+		}
+
+		selected, best = bundle, v
+	}
+
+	if best == nil {
+		return SchemaBundle{}, fmt.Errorf("no schema bundle for kubernetes version %q", targetVersion)
+	}
+
+	return selected, nil
+}
+
+// SchemaValidator returns a validator that checks objects against their schema in bundle: field
+// types, unknown fields, required fields and enums. All violations of an object are reported in
+// a single ErrSchemaViolation. Objects whose apiVersion/kind has no schema in the bundle pass, and
+// apiVersion, kind and metadata are always accepted at the top level.
+func SchemaValidator(bundle SchemaBundle) Validator {
+	return func(_ context.Context, object unstructured.Unstructured) error {
+		s, ok := bundle.Schemas[schemaKey(object.GroupVersionKind())]
+		if !ok {
+			return nil
+		}
+
+		var violations []string
+
+		validateSchema(s, object.Object, "", &violations)
+
+		if len(violations) > 0 {
+			return fmt.Errorf(
+				"%w: %s %s (kubernetes %s): %s",
+				ErrSchemaViolation,
+				object.GetKind(),
+				object.GetName(),
+				bundle.KubernetesVersion,
+				strings.Join(violations, "; "),
+			)
+		}
+
+		return nil
+	}
+}
+
+// schemaKey returns the SchemaBundle key of a kind.
+func schemaKey(gvk schema.GroupVersionKind) string {
+	return path.Join(gvk.Group, gvk.Version, gvk.Kind)
+}
+
+// validateSchema appends the violations of value against s to violations. field is the path of value.
+func validateSchema(s *Schema, value any, field string, violations *[]string) {
+	if s == nil || value == nil {
+		return
+	}
+
+	fail := func(format string, args ...any) {
+		at := field
+		if at == "" {
+			at = "<root>"
+		}
+
+		*violations = append(*violations, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if !matchesType(s, value) {
+		fail("expected %s, got %s", schemaType(s), valueType(value))
+
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return enumEqual(e, value) }) {
+		fail("unsupported value %v", value)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail("missing required field %q", name)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			child := joinField(field, name)
+
+			switch {
+			case s.Properties[name] != nil:
+				validateSchema(s.Properties[name], v[name], child, violations)
+			case s.AdditionalProperties != nil:
+				validateSchema(s.AdditionalProperties, v[name], child, violations)
+			case field == "" && (name == "apiVersion" || name == "kind" || name == "metadata"):
+			case len(s.Properties) > 0 && !s.PreserveUnknownFields:
+				*violations = append(*violations, child+": unknown field")
+			}
+		}
+	case []any:
+		for i, item := range v {
+			validateSchema(s.Items, item, fmt.Sprintf("%s[%d]", field, i), violations)
+		}
+	}
+}
+
+// matchesType reports whether value has the type of s.
+func matchesType(s *Schema, value any) bool {
+	switch value.(type) {
+	case map[string]any:
+		return s.Type == "" || s.Type == "object"
+	case []any:
+		return s.Type == "" || s.Type == "array"
+	case string:
+		return s.Type == "" || s.Type == "string" || s.IntOrString
+	case bool:
+		return s.Type == "" || s.Type == "boolean"
+	case int64, int:
+		return s.Type == "" || s.Type == "integer" || s.Type == "number" || s.IntOrString
+	case float64:
+		return s.Type == "" || s.Type == "number"
+	default:
+		return s.Type == ""
+	}
+}
+
+// schemaType describes the types accepted by s.
+func schemaType(s *Schema) string {
+	if s.IntOrString {
+		return "integer or string"
+	}
+
+	return s.Type
+}
+
+// valueType describes the type of a decoded value.
+func valueType(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64, int:
+		return "integer"
+	case float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// enumEqual compares an enum entry decoded from JSON with a decoded object value.
+func enumEqual(enum any, value any) bool {
+	if n, ok := enum.(float64); ok {
+		switch v := value.(type) {
+		case int64:
+			return n == float64(v)
+		case float64:
+			return n == v
+		}
+	}
+
+	return reflect.DeepEqual(enum, value)
+}
+
+// joinField appends a key to a field path.
+func joinField(field string, key string) string {
+	if field == "" {
+		return key
+	}
+
+	return field + "." + key
+}
@@ -0,0 +1,142 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const configMapSchema = `{
+  "type": "object",
+  "properties": {
+    "data": {"type": "object", "additionalProperties": {"type": "string"}},
+    "immutable": {"type": "boolean"}
+  }
+}`
+
+const serviceSchema = `{
+  "type": "object",
+  "required": ["spec"],
+  "properties": {
+    "spec": {
+      "type": "object",
+      "properties": {
+        "type": {"type": "string", "enum": ["ClusterIP", "NodePort", "LoadBalancer", "ExternalName"]},
+        "ports": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "required": ["port"],
+            "properties": {
+              "port": {"type": "integer"},
+              "targetPort": {"x-kubernetes-int-or-string": true}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const invalidServiceYAML = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: Internal
+  ports:
+  - port: "80"
+    targetPort: http
+    nodePort: 30080
+`
+
+func TestSchemaBundles(t *testing.T) {
+	bundles := fstest.MapFS{
+		"v1.28.json": &fstest.MapFile{Data: []byte(`{"kubernetesVersion": "1.28", "schemas": {
+			"v1/ConfigMap": ` + configMapSchema + `,
+			"v1/Service": ` + serviceSchema + `
+		}}`)},
+		"v1.30.json": &fstest.MapFile{Data: []byte(`{"kubernetesVersion": "1.30", "schemas": {}}`)},
+	}
+
+	t.Run("should select the bundle of the highest version not after the target", func(t *testing.T) {
+		g := NewWithT(t)
+
+		loaded, err := yaml.LoadSchemaBundles(bundles)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(loaded).To(HaveLen(2))
+
+		for target, expected := range map[string]string{"1.29": "1.28", "v1.30.2": "1.30", "": "1.30"} {
+			bundle, err := yaml.SelectSchemaBundle(loaded, target)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(bundle.KubernetesVersion).To(Equal(expected), "target %q", target)
+		}
+
+		_, err = yaml.SelectSchemaBundle(loaded, "1.27")
+		g.Expect(err).To(MatchError(ContainSubstring("no schema bundle")))
+	})
+
+	t.Run("should accept objects matching their schema", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"config.yaml": configMapYAML, "pod.yaml": podYAML},
+			yaml.WithKubernetesVersion("1.29"),
+			yaml.WithSchemaBundles(bundles),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should report all schema violations of an object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"service.yaml": invalidServiceYAML},
+			yaml.WithKubernetesVersion("1.29"),
+			yaml.WithSchemaBundles(bundles),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrSchemaViolation))
+		g.Expect(err).To(MatchError(yaml.ErrValidation))
+		g.Expect(err).To(MatchError(ContainSubstring(
+			"Service web (kubernetes 1.28): " +
+				"spec.ports[0].nodePort: unknown field; " +
+				"spec.ports[0].port: expected integer, got string; " +
+				"spec.type: unsupported value Internal",
+		)))
+	})
+
+	t.Run("should skip kinds the selected bundle does not describe", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"service.yaml": invalidServiceYAML},
+			yaml.WithKubernetesVersion("1.30"),
+			yaml.WithSchemaBundles(bundles),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("should reject invalid bundles", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.NewFromStrings(
+			map[string]string{"pod.yaml": podYAML},
+			yaml.WithSchemaBundles(fstest.MapFS{"broken.json": &fstest.MapFile{Data: []byte(`{"kubernetesVersion": "latest"}`)}}),
+		)
+		g.Expect(err).To(MatchError(ContainSubstring("invalid schema bundles")))
+	})
+}
@@ -0,0 +1,62 @@
+package yaml
+
+import (
+	"context"
+	"maps"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RenameServiceAccounts returns a transformer that renames service accounts according to mapping
+// (old name to new name), e.g. to isolate tenants sharing a manifest bundle. It rewrites:
+//   - serviceAccountName (and the deprecated serviceAccount) of workload pod templates
+//   - ServiceAccount subjects of RoleBindings and ClusterRoleBindings
+//   - the name of ServiceAccount objects
+//
+// Names are matched regardless of namespace.
+//
+// Example:
+//
+//	yaml.WithTransformer(yaml.RenameServiceAccounts(map[string]string{"app": "tenant-a-app"}))
+func RenameServiceAccounts(mapping map[string]string) types.Transformer {
+	mapping = maps.Clone(mapping)
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		switch obj.GroupVersionKind().GroupKind() {
+		case schema.GroupKind{Kind: "ServiceAccount"}:
+			if name, ok := mapping[obj.GetName()]; ok {
+				obj.SetName(name)
+			}
+		case schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+			schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:
+			subjects, _ := obj.Object["subjects"].([]any)
+			for _, s := range subjects {
+				subject, ok := s.(map[string]any)
+				if !ok || subject["kind"] != "ServiceAccount" {
+					continue
+				}
+
+				renameField(subject, "name", mapping)
+			}
+		default:
+			if spec, ok := podSpec(obj); ok {
+				renameField(spec, "serviceAccountName", mapping)
+				renameField(spec, "serviceAccount", mapping)
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// renameField replaces the string value of key according to mapping.
+func renameField(m map[string]any, key string, mapping map[string]string) {
+	if value, ok := m[key].(string); ok {
+		if name, ok := mapping[value]; ok {
+			m[key] = name
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package yaml_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const serviceAccountsYAML = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      serviceAccountName: app
+      serviceAccount: app
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: app
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: apps
+- kind: ServiceAccount
+  name: other
+- kind: User
+  name: app
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: app
+`
+
+func TestRenameServiceAccounts(t *testing.T) {
+	t.Run("should rename service accounts and their references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"app.yaml": serviceAccountsYAML},
+			yaml.WithTransformer(yaml.RenameServiceAccounts(map[string]string{"app": "tenant-a-app"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		byKind := make(map[string]unstructured.Unstructured, len(objects))
+		for _, obj := range objects {
+			byKind[obj.GetKind()] = obj
+		}
+
+		serviceAccount := byKind["ServiceAccount"]
+		g.Expect(serviceAccount.GetName()).To(Equal("tenant-a-app"))
+
+		spec, _, _ := unstructured.NestedMap(byKind["Deployment"].Object, "spec", "template", "spec")
+		g.Expect(spec).To(And(
+			HaveKeyWithValue("serviceAccountName", "tenant-a-app"),
+			HaveKeyWithValue("serviceAccount", "tenant-a-app"),
+		))
+
+		binding := byKind["RoleBinding"]
+		g.Expect(binding.GetName()).To(Equal("app"))
+		g.Expect(binding.Object["subjects"]).To(Equal([]any{
+			map[string]any{"kind": "ServiceAccount", "name": "tenant-a-app", "namespace": "apps"},
+			map[string]any{"kind": "ServiceAccount", "name": "other"},
+			map[string]any{"kind": "User", "name": "app"},
+		}))
+	})
+}
@@ -0,0 +1,216 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// ErrUnavailableAPI is returned when an object uses an apiVersion or field that the target
+// Kubernetes version does not serve yet, or no longer serves.
+var ErrUnavailableAPI = newClassError(ErrValidation, "not available in target kubernetes version")
+
+// APIAvailability records the Kubernetes version that started serving an apiVersion/kind by default.
+type APIAvailability struct {
+	// GroupVersionKind is the API.
+	GroupVersionKind schema.GroupVersionKind
+
+	// IntroducedIn is the first Kubernetes version serving the API by default (e.g. "1.23").
+	IntroducedIn string
+}
+
+// FieldAvailability records the Kubernetes version that started accepting a field by default.
+type FieldAvailability struct {
+	// GroupKind is the kind the field belongs to. An empty GroupKind denotes a pod spec field,
+	// checked on pods and the pod templates of workloads.
+	GroupKind schema.GroupKind
+
+	// Path is the field path in RemoveFields syntax (e.g. "spec.timeZone"), relative to the pod
+	// spec for pod spec fields (e.g. "initContainers[*].restartPolicy").
+	Path string
+
+	// IntroducedIn is the first Kubernetes version accepting the field by default (e.g. "1.25").
+	IntroducedIn string
+}
+
+// APIAvailabilities returns the built-in list of Kubernetes APIs and the versions that started
+// serving them by default. APIs that are not listed are not checked.
+// The returned slice is a copy and may be extended by callers.
+func APIAvailabilities() []APIAvailability {
+	return []APIAvailability{
+		available("apps", "v1", "Deployment", "1.9"),
+		available("apps", "v1", "DaemonSet", "1.9"),
+		available("apps", "v1", "ReplicaSet", "1.9"),
+		available("apps", "v1", "StatefulSet", "1.9"),
+		available("networking.k8s.io", "v1", "Ingress", "1.19"),
+		available("networking.k8s.io", "v1", "IngressClass", "1.19"),
+		available("networking.k8s.io", "v1beta1", "IngressClass", "1.18"),
+		available("policy", "v1", "PodDisruptionBudget", "1.21"),
+		available("batch", "v1", "CronJob", "1.21"),
+		available("discovery.k8s.io", "v1", "EndpointSlice", "1.21"),
+		available("discovery.k8s.io", "v1beta1", "EndpointSlice", "1.17"),
+		available("events.k8s.io", "v1", "Event", "1.19"),
+		available("autoscaling", "v2", "HorizontalPodAutoscaler", "1.23"),
+		available("autoscaling", "v2beta2", "HorizontalPodAutoscaler", "1.12"),
+		available("node.k8s.io", "v1", "RuntimeClass", "1.20"),
+		available("apiextensions.k8s.io", "v1", "CustomResourceDefinition", "1.16"),
+		available("admissionregistration.k8s.io", "v1", "MutatingWebhookConfiguration", "1.16"),
+		available("admissionregistration.k8s.io", "v1", "ValidatingWebhookConfiguration", "1.16"),
+		available("admissionregistration.k8s.io", "v1", "ValidatingAdmissionPolicy", "1.30"),
+		available("admissionregistration.k8s.io", "v1", "ValidatingAdmissionPolicyBinding", "1.30"),
+		available("certificates.k8s.io", "v1", "CertificateSigningRequest", "1.19"),
+		available("storage.k8s.io", "v1", "CSIDriver", "1.18"),
+		available("storage.k8s.io", "v1", "CSINode", "1.17"),
+		available("storage.k8s.io", "v1", "CSIStorageCapacity", "1.24"),
+		available("flowcontrol.apiserver.k8s.io", "v1", "FlowSchema", "1.29"),
+		available("flowcontrol.apiserver.k8s.io", "v1", "PriorityLevelConfiguration", "1.29"),
+		available("flowcontrol.apiserver.k8s.io", "v1beta3", "FlowSchema", "1.26"),
+		available("flowcontrol.apiserver.k8s.io", "v1beta3", "PriorityLevelConfiguration", "1.26"),
+		available("flowcontrol.apiserver.k8s.io", "v1beta2", "FlowSchema", "1.23"),
+		available("flowcontrol.apiserver.k8s.io", "v1beta2", "PriorityLevelConfiguration", "1.23"),
+	}
+}
+
+// FieldAvailabilities returns the built-in list of fields added to stable Kubernetes APIs and
+// the versions that started accepting them by default. Fields that are not listed are not checked.
+// The returned slice is a copy and may be extended by callers.
+func FieldAvailabilities() []FieldAvailability {
+	return []FieldAvailability{
+		{GroupKind: schema.GroupKind{Kind: "Service"}, Path: "spec.internalTrafficPolicy", IntroducedIn: "1.22"},
+		{GroupKind: schema.GroupKind{Kind: "Service"}, Path: "spec.trafficDistribution", IntroducedIn: "1.31"},
+		{GroupKind: schema.GroupKind{Group: "batch", Kind: "CronJob"}, Path: "spec.timeZone", IntroducedIn: "1.25"},
+		{GroupKind: schema.GroupKind{Group: "batch", Kind: "Job"}, Path: "spec.podFailurePolicy", IntroducedIn: "1.26"},
+		{GroupKind: schema.GroupKind{Group: "batch", Kind: "Job"}, Path: "spec.backoffLimitPerIndex", IntroducedIn: "1.29"},
+		{GroupKind: schema.GroupKind{Group: "batch", Kind: "Job"}, Path: "spec.successPolicy", IntroducedIn: "1.31"},
+		{GroupKind: schema.GroupKind{Group: "batch", Kind: "Job"}, Path: "spec.managedBy", IntroducedIn: "1.32"},
+		{GroupKind: schema.GroupKind{Group: "policy", Kind: "PodDisruptionBudget"}, Path: "spec.unhealthyPodEvictionPolicy", IntroducedIn: "1.27"},
+		{GroupKind: schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, Path: "spec.persistentVolumeClaimRetentionPolicy", IntroducedIn: "1.27"},
+		{GroupKind: schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, Path: "spec.ordinals", IntroducedIn: "1.27"},
+		{Path: "schedulingGates", IntroducedIn: "1.27"},
+		{Path: "initContainers[*].restartPolicy", IntroducedIn: "1.29"},
+		{Path: "containers[*].resizePolicy", IntroducedIn: "1.33"},
+	}
+}
+
+func available(group string, ver string, kind string, introducedIn string) APIAvailability {
+	return APIAvailability{
+		GroupVersionKind: schema.GroupVersionKind{Group: group, Version: ver, Kind: kind},
+		IntroducedIn:     introducedIn,
+	}
+}
+
+// VersionSkewValidator returns a validator that flags objects using apiVersions or fields not
+// served by the target Kubernetes version (e.g. "1.27" or "v1.27.3"): APIs and fields introduced
+// later (APIAvailabilities, FieldAvailabilities) and APIs removed earlier (DeprecatedAPIs).
+// Violations are reported as "unavailable-api", "unavailable-field" or "removed-api" warnings with
+// PolicyWarn, or fail validation with ErrUnavailableAPI (ErrRemovedAPI for removed APIs) with
+// PolicyError; PolicyIgnore disables the check.
+func VersionSkewValidator(targetVersion string, policy Policy) (Validator, error) {
+	return versionSkewValidator(targetVersion, policy, true)
+}
+
+// versionSkewValidator builds the validator of VersionSkewValidator. checkRemoved is false when
+// removed APIs are already reported by the deprecated API check.
+func versionSkewValidator(targetVersion string, policy Policy, checkRemoved bool) (Validator, error) {
+	target, err := version.ParseGeneric(targetVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubernetes version %q: %w", targetVersion, err)
+	}
+
+	apis := make(map[schema.GroupVersionKind]string)
+	for _, a := range APIAvailabilities() {
+		apis[a.GroupVersionKind] = a.IntroducedIn
+	}
+
+	fields := make(map[schema.GroupKind][]fieldCheck)
+
+	for _, f := range FieldAvailabilities() {
+		segments, err := parseFieldPath(f.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[f.GroupKind] = append(fields[f.GroupKind], fieldCheck{FieldAvailability: f, segments: segments})
+	}
+
+	removed := make(map[schema.GroupVersionKind]DeprecatedAPI)
+	if checkRemoved {
+		for _, api := range DeprecatedAPIs() {
+			if api.RemovedIn != "" {
+				removed[api.GroupVersionKind] = api
+			}
+		}
+	}
+
+	return func(ctx context.Context, object unstructured.Unstructured) error {
+		if policy == PolicyIgnore {
+			return nil
+		}
+
+		gvk := object.GroupVersionKind()
+
+		if api, ok := removed[gvk]; ok && atLeast(target, api.RemovedIn) {
+			return checkDeprecatedAPI(ctx, object, api, target, policy)
+		}
+
+		if introducedIn, ok := apis[gvk]; ok && !atLeast(target, introducedIn) {
+			msg := fmt.Sprintf("%s %s: %s is served from %s", gvk.Kind, object.GetName(), gvk.GroupVersion(), introducedIn)
+
+			return reportSkew(ctx, object, "unavailable-api", msg, policy)
+		}
+
+		if err := checkFields(ctx, object, object.Object, fields[gvk.GroupKind()], target, policy); err != nil {
+			return err
+		}
+
+		if spec, ok := podSpec(object); ok {
+			return checkFields(ctx, object, spec, fields[schema.GroupKind{}], target, policy)
+		}
+
+		return nil
+	}, nil
+}
+
+// fieldCheck is a FieldAvailability with its parsed path.
+type fieldCheck struct {
+	FieldAvailability
+
+	segments []fieldSegment
+}
+
+// checkFields reports the fields of value (the object or its pod spec) not accepted by target.
+func checkFields(
+	ctx context.Context,
+	object unstructured.Unstructured,
+	value any,
+	checks []fieldCheck,
+	target *version.Version,
+	policy Policy,
+) error {
+	for _, f := range checks {
+		if atLeast(target, f.IntroducedIn) || !hasField(value, f.segments) {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s %s: field %s is accepted from %s", object.GetKind(), object.GetName(), f.Path, f.IntroducedIn)
+		if err := reportSkew(ctx, object, "unavailable-field", msg, policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportSkew fails with ErrUnavailableAPI with PolicyError, or reports a warning.
+func reportSkew(ctx context.Context, object unstructured.Unstructured, rule string, msg string, policy Policy) error {
+	if policy == PolicyError {
+		return fmt.Errorf("%w: %s", ErrUnavailableAPI, msg)
+	}
+
+	ReportWarning(ctx, Warning{File: sourceFile(object), Rule: rule, Message: msg})
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const newerAPIsYAML = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  maxReplicas: 3
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  schedule: "0 0 * * *"
+  timeZone: Europe/Rome
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - name: proxy
+            image: proxy
+            restartPolicy: Always
+          containers:
+          - name: report
+            image: report
+`
+
+func TestVersionSkewPolicy(t *testing.T) {
+	testFS := fstest.MapFS{
+		"newer.yaml":   &fstest.MapFile{Data: []byte(newerAPIsYAML)},
+		"cronjob.yaml": &fstest.MapFile{Data: []byte(cronJobV1Beta1YAML)},
+	}
+
+	render := func(t *testing.T, kubernetesVersion string, policy yaml.Policy) ([]yaml.Warning, error) {
+		t.Helper()
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "newer.yaml"}, {FS: testFS, Path: "cronjob.yaml"}},
+			yaml.WithKubernetesVersion(kubernetesVersion),
+			yaml.WithVersionSkewPolicy(policy),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+
+		return collector.Warnings(), err
+	}
+
+	t.Run("should warn about APIs and fields newer than the target version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		warnings, err := render(t, "1.22", yaml.PolicyWarn)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(warnings).To(ConsistOf(
+			yaml.Warning{Rule: "unavailable-api", Message: "HorizontalPodAutoscaler web: autoscaling/v2 is served from 1.23"},
+			yaml.Warning{Rule: "unavailable-field", Message: "CronJob report: field spec.timeZone is accepted from 1.25"},
+			yaml.Warning{Rule: "unavailable-field", Message: "CronJob report: field initContainers[*].restartPolicy is accepted from 1.29"},
+		))
+	})
+
+	t.Run("should warn about APIs removed before the target version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		warnings, err := render(t, "1.29", yaml.PolicyWarn)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(warnings).To(ConsistOf(HaveField("Rule", "removed-api")))
+	})
+
+	t.Run("should fail with the error policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := render(t, "1.22", yaml.PolicyError)
+		g.Expect(err).To(MatchError(yaml.ErrUnavailableAPI))
+		g.Expect(err).To(MatchError(yaml.ErrValidation))
+	})
+
+	t.Run("should require a kubernetes version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "newer.yaml"}},
+			yaml.WithVersionSkewPolicy(yaml.PolicyWarn),
+		)
+		g.Expect(err).To(MatchError(ContainSubstring("a kubernetes version is required")))
+	})
+
+	t.Run("should report removed APIs once with the deprecated API check", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "cronjob.yaml"}},
+			yaml.WithKubernetesVersion("1.29"),
+			yaml.WithVersionSkewPolicy(yaml.PolicyWarn),
+			yaml.WithDeprecatedAPIPolicy(yaml.PolicyWarn),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(collector.Warnings()).To(ConsistOf(HaveField("Rule", "removed-api")))
+	})
+}
@@ -0,0 +1,149 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+)
+
+// staleEntry is the latest result of a cached source, retained with its estimated size when
+// the cache has a size cap.
+type staleEntry struct {
+	spec   YAMLSpec
+	result loadedSource
+	size   int64
+	budget byteBudget
+}
+
+// keepStale retains the latest result of a cached source, to be served when a later read fails or
+// its entry expired. It is a no-op unless WithServeStale or WithStaleWhileRevalidate is enabled.
+// Only the result of the latest cache key is retained per source, replacing the previous one. If
+// the cache has a size cap, the retained copy counts toward it and is dropped if it does not fit.
+func (r *Renderer) keepStale(
+	c cache.Interface[loadedSource],
+	holder *sourceHolder,
+	spec YAMLSpec,
+	result loadedSource,
+) {
+	if !r.opts.ServeStale && !r.opts.StaleWhileRevalidate {
+		return
+	}
+
+	entry := &staleEntry{spec: spec, result: result.clone()}
+	if budget, ok := c.(byteBudget); ok {
+		entry.size = estimateResultSize(result)
+		entry.budget = budget
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+
+	holder.dropStale()
+
+	if holder.removed {
+		return
+	}
+
+	if entry.budget != nil && !entry.budget.reserve(entry.size) {
+		return
+	}
+
+	holder.stale = entry
+}
+
+// staleResult returns a copy of the last result retained for spec when a source read failed with
+// err, reporting a "stale-result" warning. Cancellation and cache refreshes never use stale
+// results.
+func (r *Renderer) staleResult(
+	ctx context.Context,
+	holder *sourceHolder,
+	spec YAMLSpec,
+	err error,
+) (loadedSource, bool) {
+	if !r.opts.ServeStale || isCacheRefresh(ctx) || errors.Is(err, ErrRenderInterrupted) {
+		return loadedSource{}, false
+	}
+
+	stale, ok := holder.staleFor(spec)
+	if !ok {
+		return loadedSource{}, false
+	}
+
+	ReportWarning(ctx, Warning{
+		Rule:    "stale-result",
+		Message: fmt.Sprintf("serving stale result for %s after error: %v", spec.Path, err),
+	})
+
+	return stale, true
+}
+
+// revalidate returns a copy of the last result retained for spec after its cache entry expired,
+// starting a background refresh of the source unless one is already running. The refresh outlives
+// the render: it keeps the render values but not the cancellation of ctx.
+func (r *Renderer) revalidate(
+	ctx context.Context,
+	holder *sourceHolder,
+	spec YAMLSpec,
+) (loadedSource, bool) {
+	if !r.opts.StaleWhileRevalidate {
+		return loadedSource{}, false
+	}
+
+	stale, ok := holder.staleFor(spec)
+	if !ok {
+		return loadedSource{}, false
+	}
+
+	if holder.revalidating.CompareAndSwap(false, true) {
+		refreshCtx := context.WithValue(context.WithoutCancel(ctx), cacheRefreshKey{}, true)
+
+		go func() {
+			defer holder.revalidating.Store(false)
+
+			if _, _, err := r.renderSingle(refreshCtx, holder); err != nil {
+				ReportWarning(refreshCtx, Warning{
+					Rule:    "revalidate-error",
+					Message: fmt.Sprintf("error revalidating YAML pattern %s: %v", spec.Path, err),
+				})
+			}
+		}()
+	}
+
+	return stale, true
+}
+
+// staleFor returns a copy of the last result retained for spec.
+func (h *sourceHolder) staleFor(spec YAMLSpec) (loadedSource, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stale == nil || h.stale.spec != spec {
+		return loadedSource{}, false
+	}
+
+	return h.stale.result.clone(), true
+}
+
+// dropStale releases the retained result; the caller holds the lock.
+func (h *sourceHolder) dropStale() {
+	if h.stale == nil {
+		return
+	}
+
+	if h.stale.budget != nil {
+		h.stale.budget.release(h.stale.size)
+	}
+
+	h.stale = nil
+}
+
+// remove drops the retained result of a removed source and stops retaining new ones.
+func (h *sourceHolder) remove() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removed = true
+	h.dropStale()
+}
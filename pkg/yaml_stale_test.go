@@ -0,0 +1,169 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/k8s-manifest-kit/pkg/util/cache"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestServeStale(t *testing.T) {
+	// newRenderer renders a source once, then breaks it and waits for its cache entry to expire
+	newRenderer := func(t *testing.T, opts ...yaml.RendererOption) *yaml.Renderer {
+		t.Helper()
+		g := NewWithT(t)
+
+		testFS := fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			append(opts, yaml.WithCache(cache.WithTTL(time.Millisecond)))...,
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		testFS["pod.yaml"] = &fstest.MapFile{Data: []byte("\x00binary")}
+		time.Sleep(5 * time.Millisecond)
+
+		return renderer
+	}
+
+	t.Run("should serve the last result when reading fails", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer := newRenderer(t, yaml.WithServeStale(true), yaml.WithWarningHandler(collector.Handle))
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "stale-result"),
+			HaveField("Message", ContainSubstring(yaml.ErrBinaryContent.Error())),
+		)))
+	})
+
+	t.Run("should fail without stale results", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := newRenderer(t)
+
+		_, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrBinaryContent))
+	})
+
+	t.Run("should not keep results exceeding the cache size cap", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := newRenderer(t, yaml.WithServeStale(true), yaml.WithCacheMaxBytes(1))
+
+		_, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrBinaryContent))
+	})
+
+	t.Run("should only keep the latest result per source", func(t *testing.T) {
+		g := NewWithT(t)
+
+		testFS := fstest.MapFS{
+			"prod/pod.yaml":   &fstest.MapFile{Data: []byte(podYAML)},
+			"staging/cm.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "${env}/*.yaml"}},
+			yaml.WithCache(cache.WithTTL(time.Millisecond)),
+			yaml.WithServeStale(true),
+			yaml.WithWarningHandler((&warningCollector{}).Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, env := range []string{"prod", "staging"} {
+			_, err = renderer.Process(t.Context(), map[string]any{"env": env})
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+
+		testFS["prod/pod.yaml"] = &fstest.MapFile{Data: []byte("\x00binary")}
+		testFS["staging/cm.yaml"] = &fstest.MapFile{Data: []byte("\x00binary")}
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = renderer.Process(t.Context(), map[string]any{"env": "prod"})
+		g.Expect(err).To(MatchError(yaml.ErrBinaryContent))
+
+		objects, err := renderer.Process(t.Context(), map[string]any{"env": "staging"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-config"))
+	})
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	// newRenderer renders a source once, replaces it with data and waits for its cache entry to expire
+	newRenderer := func(t *testing.T, data string, opts ...yaml.RendererOption) *yaml.Renderer {
+		t.Helper()
+		g := NewWithT(t)
+
+		testFS := fstest.MapFS{"manifest.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			append(opts, yaml.WithCache(cache.WithTTL(time.Millisecond)), yaml.WithStaleWhileRevalidate(true))...,
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		testFS["manifest.yaml"] = &fstest.MapFile{Data: []byte(data)}
+		time.Sleep(5 * time.Millisecond)
+
+		return renderer
+	}
+
+	t.Run("should serve expired results while refreshing them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := newRenderer(t, configMapYAML)
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+
+		g.Eventually(func(g Gomega) {
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+			g.Expect(objects[0].GetName()).To(Equal("test-config"))
+		}).Should(Succeed())
+	})
+
+	t.Run("should keep serving expired results when refreshing fails", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer := newRenderer(t, "\x00binary", yaml.WithWarningHandler(collector.Handle))
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+
+		g.Eventually(collector.Warnings).Should(ContainElement(And(
+			HaveField("Rule", "revalidate-error"),
+			HaveField("Message", ContainSubstring(yaml.ErrBinaryContent.Error())),
+		)))
+
+		objects, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+	})
+}
@@ -1,25 +1,232 @@
 package yaml
 
 import (
+	"context"
 	"fmt"
+	"path"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/k8s-manifest-kit/pkg/util/cache"
 	"github.com/k8s-manifest-kit/pkg/util/errors"
 )
 
+// sourceIDs generates the ids of source holders.
+var sourceIDs atomic.Uint64
+
 // sourceHolder wraps a Source with internal state for consistency with other renderers.
 type sourceHolder struct {
 	Source
+
+	// id identifies the holder in cache keys; it is never reused, so a source added after
+	// removing another one with the same path does not hit its entries.
+	id uint64
+
+	mu    sync.Mutex
+	files []string
+
+	// stale holds the latest result, served on read errors with WithServeStale and on expiry
+	// with WithStaleWhileRevalidate.
+	stale *staleEntry
+
+	// removed is set once the source is removed, so that no result is retained for it anymore.
+	removed bool
+
+	// revalidating is set while a background refresh of the source runs.
+	revalidating atomic.Bool
+
+	// cache is the source's own cache, set when Source.Cache enables caching.
+	cache cache.Interface[loadedSource]
+
+	// lastCount is the number of objects decoded by the previous uncached render.
+	lastCount atomic.Int64
+}
+
+// setResolvedFiles records the files matched by the latest render.
+func (h *sourceHolder) setResolvedFiles(files []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.files = slices.Clone(files)
+}
+
+// resolvedFiles returns a copy of the files matched by the latest render.
+func (h *sourceHolder) resolvedFiles() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return slices.Clone(h.files)
+}
+
+// newSourceHolder wraps a Source in a holder, normalizing and validating it.
+func newSourceHolder(source Source, opts RendererOptions) (*sourceHolder, error) {
+	holder := &sourceHolder{
+		Source: source,
+		id:     sourceIDs.Add(1),
+	}
+	if opts.WindowsPaths {
+		holder.Path = normalizeWindowsPath(holder.Path)
+	}
+	if err := holder.Validate(); err != nil {
+		return nil, err
+	}
+
+	if holder.Cache != nil && !holder.Cache.Disabled {
+		co := cache.Options{TTL: holder.Cache.TTL}
+		if opts.CacheOptions != nil {
+			co.KeyFunc = opts.CacheOptions.KeyFunc
+			if co.TTL == 0 {
+				co.TTL = opts.CacheOptions.TTL
+			}
+		}
+
+		holder.cache = newCache(&co, opts)
+	}
+
+	return holder, nil
 }
 
 // Validate checks if the Source configuration is valid.
 func (h *sourceHolder) Validate() error {
+	if h.Cache != nil && h.Cache.TTL < 0 {
+		return fmt.Errorf("%w: cache TTL must not be negative", ErrInvalidSource)
+	}
+
+	if h.Objects != nil {
+		if h.FS != nil {
+			return fmt.Errorf("%w: FS and Objects are mutually exclusive", ErrInvalidSource)
+		}
+
+		return h.validateExpectations()
+	}
+
 	if h.FS == nil {
 		return fmt.Errorf("filesystem is required: %w", errors.ErrFsRequired)
 	}
 	if len(strings.TrimSpace(h.Path)) == 0 {
 		return fmt.Errorf("path is required: %w", errors.ErrPathEmpty)
 	}
+	literal, err := validatePathVariables(h.Path)
+	if err != nil {
+		return err
+	}
+	if err := validatePattern(literal); err != nil {
+		return err
+	}
+
+	return h.validateExpectations()
+}
+
+// validateExpectations checks the expected object count bounds.
+func (h *sourceHolder) validateExpectations() error {
+	if h.ExpectAtLeast < 0 || h.ExpectAtMost < 0 {
+		return fmt.Errorf("%w: expected object counts must not be negative", ErrInvalidSource)
+	}
+	if h.ExpectAtMost > 0 && h.ExpectAtLeast > h.ExpectAtMost {
+		return fmt.Errorf(
+			"%w: ExpectAtLeast (%d) is greater than ExpectAtMost (%d)",
+			ErrInvalidSource,
+			h.ExpectAtLeast,
+			h.ExpectAtMost,
+		)
+	}
 
 	return nil
 }
+
+// checkCount verifies the number of objects rendered for the source against its expectations.
+func (h *sourceHolder) checkCount(count int) error {
+	if count < h.ExpectAtLeast {
+		return fmt.Errorf("%w: got %d objects, expected at least %d", ErrUnexpectedObjectCount, count, h.ExpectAtLeast)
+	}
+	if h.ExpectAtMost > 0 && count > h.ExpectAtMost {
+		return fmt.Errorf("%w: got %d objects, expected at most %d", ErrUnexpectedObjectCount, count, h.ExpectAtMost)
+	}
+
+	return nil
+}
+
+// matchFiles returns the files matching the resolved source pattern, excluding files rejected
+// by the renderer's file selection options.
+func (r *Renderer) matchFiles(ctx context.Context, holder *sourceHolder, pattern string) ([]string, error) {
+	var matches []string
+
+	err := r.retry(ctx, pattern, func() error {
+		var err error
+		matches, err = glob(ctx, holder.FS, pattern, r.opts.CaseInsensitive)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to match pattern %s: %w", pattern, err)
+	}
+
+	ignore, err := loadIgnoreRules(r.retryingFS(ctx, holder.FS), r.opts.IgnoreFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if r.opts.SkipHidden && isHidden(match) {
+			continue
+		}
+
+		if ignore.Ignored(match) {
+			continue
+		}
+
+		result = append(result, match)
+	}
+
+	return result, nil
+}
+
+// DefaultAllowedExtensions returns the file extensions rendered by default.
+func DefaultAllowedExtensions() []string {
+	return []string{".yaml", ".yml"}
+}
+
+// allowedExtension reports whether the extension of a matched file is one of the allowed extensions.
+func (r *Renderer) allowedExtension(p string) bool {
+	ext := path.Ext(p)
+
+	for _, allowed := range r.opts.AllowedExtensions {
+		if ext == allowed || (r.opts.CaseInsensitive && strings.EqualFold(ext, allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHidden reports whether any element of a slash-separated path is a dotfile or dot-directory,
+// or whether the file name is an editor backup or lock file (name~, #name#).
+func isHidden(p string) bool {
+	for _, elem := range strings.Split(p, "/") {
+		if strings.HasPrefix(elem, ".") && elem != "." && elem != ".." {
+			return true
+		}
+	}
+
+	name := path.Base(p)
+
+	return strings.HasSuffix(name, "~") || (strings.HasPrefix(name, "#") && strings.HasSuffix(name, "#"))
+}
+
+// trimSourcePrefix removes a directory prefix from a slash-separated file path.
+// The path is returned unchanged if it is not located below prefix.
+func trimSourcePrefix(file string, prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" || prefix == "." {
+		return file
+	}
+
+	if rel, ok := strings.CutPrefix(file, prefix+"/"); ok {
+		return rel
+	}
+
+	return file
+}
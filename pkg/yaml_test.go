@@ -1,6 +1,11 @@
 package yaml_test
 
 import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
 	"testing"
 	"testing/fstest"
 
@@ -11,6 +16,7 @@ import (
 	jqmatcher "github.com/lburgazzoli/gomega-matchers/pkg/matchers/jq"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
 
@@ -164,6 +170,136 @@ func TestRenderer(t *testing.T) {
 		))
 	})
 
+	t.Run("should apply batched filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+			"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithFilters(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+			yaml.WithTransformers(
+				labels.Set(map[string]string{"env": "test"}),
+				labels.Set(map[string]string{"team": "platform"}),
+			),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(And(
+			HaveKeyWithValue("env", "test"),
+			HaveKeyWithValue("team", "platform"),
+		))
+	})
+
+	t.Run("should run staged transformers in stage order", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		var order []string
+		record := func(name string) types.Transformer {
+			return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				order = append(order, name)
+
+				return obj, nil
+			}
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithStagedTransformer(yaml.StageFinalize, record("finalize")),
+			yaml.WithTransformer(record("mutate-1")),
+			yaml.WithStagedTransformer(yaml.StageMutate+10, record("custom")),
+			yaml.WithStagedTransformer(yaml.StageSanitize, record("sanitize")),
+			yaml.WithTransformer(record("mutate-2")),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(order).To(Equal([]string{"sanitize", "mutate-1", "mutate-2", "custom", "finalize"}))
+	})
+
+	t.Run("should compose struct-based options", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+			"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.RendererOptions{
+				Filters: []types.Filter{gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))},
+			},
+			yaml.RendererOptions{
+				Transformers: []types.Transformer{labels.Set(map[string]string{"env": "test"})},
+			},
+			yaml.WithTransformer(labels.Set(map[string]string{"team": "platform"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		g.Expect(objects[0].GetLabels()).To(And(
+			HaveKeyWithValue("env", "test"),
+			HaveKeyWithValue("team", "platform"),
+		))
+	})
+
+	t.Run("should keep defaults not set by struct-based options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defaults := yaml.RendererOptions{
+			BinaryFilePolicy:      yaml.PolicyError,
+			AmbiguousScalarPolicy: yaml.PolicyWarn,
+			LossyNumberPolicy:     yaml.PolicyWarn,
+			CacheDeepCopy:         true,
+			Redact:                true,
+			AllowAliases:          true,
+			AllowMergeKeys:        true,
+			RequireMatch:          true,
+			WindowsPaths:          true,
+		}
+
+		target := defaults
+		yaml.RendererOptions{SourceAnnotations: true}.ApplyTo(&target)
+
+		expected := defaults
+		expected.SourceAnnotations = true
+		g.Expect(target).To(Equal(expected))
+
+		testFS := fstest.MapFS{
+			"image.yaml": &fstest.MapFile{Data: []byte{0x89, 'P', 'N', 'G', 0x00, 0xFF, 0xFE}},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.json"}},
+			yaml.RendererOptions{SourceAnnotations: true},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+
+		renderer, err = yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.RendererOptions{SourceAnnotations: true},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(yaml.ErrBinaryContent))
+	})
+
 	t.Run("should handle .yml extension", func(t *testing.T) {
 		g := NewWithT(t)
 		testFS := fstest.MapFS{
@@ -215,6 +351,41 @@ func TestRenderer(t *testing.T) {
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(objects).To(HaveLen(2))
 	})
+
+	t.Run("should load a single literal path", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"config/multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+			"config/notes.txt":      &fstest.MapFile{Data: []byte("not yaml")},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "config/multi-doc.yaml"},
+			{FS: testFS, Path: "config/notes.txt"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Service"))
+		g.Expect(objects[1].GetKind()).To(Equal("Secret"))
+	})
+
+	t.Run("should return error for a literal directory path", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"config/pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "config"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(yaml.ErrPathIsDirectory))
+	})
 }
 
 func TestCacheIntegration(t *testing.T) {
@@ -339,6 +510,63 @@ func TestCacheIntegration(t *testing.T) {
 			g.Expect(result2[0].GetName()).ToNot(Equal("modified-name"))
 		}
 	})
+
+	t.Run("should share cached objects when deep copy is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml"},
+		},
+			yaml.WithCache(),
+			yaml.WithCacheDeepCopy(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result1, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result1).To(HaveLen(1))
+
+		result2, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result2).To(HaveLen(1))
+
+		// Results alias the cached objects
+		result1[0].SetName("modified-name")
+		g.Expect(result2[0].GetName()).To(Equal("modified-name"))
+	})
+
+	t.Run("should serve compressed cache entries", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml"},
+		},
+			yaml.WithCache(),
+			yaml.WithCacheCompression(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result1, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result1).To(HaveLen(1))
+
+		// Changes to the source and to returned objects do not reach the cached entry
+		testFS["pod.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+		name := result1[0].GetName()
+		result1[0].SetName("modified-name")
+
+		result2, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result2).To(HaveLen(1))
+		g.Expect(result2[0].GetName()).To(Equal(name))
+		g.Expect(result2[0].GetKind()).To(Equal("Pod"))
+	})
 }
 
 func BenchmarkYamlRenderWithoutCache(b *testing.B) {
@@ -394,6 +622,35 @@ func BenchmarkYamlRenderWithCache(b *testing.B) {
 	}
 }
 
+func BenchmarkYamlRenderWithCacheNoCopy(b *testing.B) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"multi.yaml":     &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	renderer, err := yaml.New(
+		[]yaml.Source{
+			{FS: testFS, Path: "*.yaml"},
+		},
+		yaml.WithCache(),
+		yaml.WithCacheDeepCopy(false),
+	)
+	if err != nil {
+		b.Fatalf("failed to create renderer: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, err := renderer.Process(b.Context(), nil)
+		if err != nil {
+			b.Fatalf("failed to render: %v", err)
+		}
+	}
+}
+
 func BenchmarkYamlRenderCacheMiss(b *testing.B) {
 	testFS := fstest.MapFS{
 		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
@@ -483,6 +740,83 @@ func TestSourceAnnotations(t *testing.T) {
 			g.Expect(annotations).ShouldNot(HaveKey(types.AnnotationSourceFile))
 		}
 	})
+
+	t.Run("should strip source prefix from file annotation", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"home/ci/repo/apps/pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+			"other/configmap.yaml":       &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{
+				{FS: testFS, Path: "home/ci/repo/apps/*.yaml"},
+				{FS: testFS, Path: "other/*.yaml"},
+			},
+			yaml.WithSourceAnnotations(true),
+			yaml.WithSourcePrefix("/home/ci/repo/"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "apps/pod.yaml"))
+		g.Expect(objects[1].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "other/configmap.yaml"))
+	})
+}
+
+func TestLabel(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	t.Run("should report the renderer type and label", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}}, yaml.WithLabel("base"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal(yaml.RendererType))
+		g.Expect(renderer.Label()).To(Equal("base"))
+	})
+
+	t.Run("should annotate objects with the label", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithLabel("base"),
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(yaml.AnnotationSourceLabel, "base"))
+	})
+
+	t.Run("should attribute errors and reports to the label", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var report yaml.RenderReport
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "missing/*.yaml"}},
+			yaml.WithLabel("overrides"),
+			yaml.WithPostRenderHook(func(_ context.Context, _ []unstructured.Unstructured, r yaml.RenderReport) error {
+				report = r
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+		g.Expect(err.Error()).To(HavePrefix(`yaml renderer "overrides": `))
+		g.Expect(report.Label).To(Equal("overrides"))
+	})
 }
 
 func TestCacheKeyFunc(t *testing.T) {
@@ -614,4 +948,1173 @@ func TestCacheKeyFunc(t *testing.T) {
 			g.Expect(result2[i]).To(Equal(result1[i]))
 		}
 	})
+
+	t.Run("should include the options fingerprint in cache keys", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		keyOf := func(opts ...yaml.RendererOption) string {
+			var options string
+			keyFunc := func(key any) string {
+				if spec, ok := key.(yaml.YAMLSpec); ok {
+					options = spec.Options
+				}
+
+				return cache.DefaultKeyFunc(key)
+			}
+
+			renderer, err := yaml.New(
+				[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+				append(opts, yaml.WithCache(cache.WithKeyFunc(keyFunc)))...,
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			return options
+		}
+
+		base := keyOf()
+		g.Expect(base).ToNot(BeEmpty())
+		g.Expect(keyOf()).To(Equal(base))
+		g.Expect(keyOf(yaml.WithMaxDepth(10))).ToNot(Equal(base))
+
+		// Transformers run on cached results
+		g.Expect(keyOf(yaml.WithTransformer(labels.Set(map[string]string{"env": "test"})))).To(Equal(base))
+	})
+
+	t.Run("should not share cache entries between sources with the same path", func(t *testing.T) {
+		g := NewWithT(t)
+		baseFS := fstest.MapFS{"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)}}
+		overlayFS := fstest.MapFS{"cm.yaml": &fstest.MapFile{Data: []byte(configMapYAML)}}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: baseFS, Path: "*.yaml"}, {Name: "overlay", FS: overlayFS, Path: "*.yaml"}},
+			yaml.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for range 2 {
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(2))
+			g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+			g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+		}
+
+		g.Expect(renderer.RemoveSource("overlay")).To(BeTrue())
+		g.Expect(renderer.AddSource(yaml.Source{Name: "overlay", FS: fstest.MapFS{
+			"secret.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		}, Path: "*.yaml"})).To(Succeed())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[1].GetKind()).To(Equal("Service"))
+	})
+}
+
+func TestDecodingTolerance(t *testing.T) {
+	t.Run("should strip UTF-8 byte order mark", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: append([]byte{0xEF, 0xBB, 0xBF}, []byte(podYAML)...)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+	})
+
+	t.Run("should accept CRLF line endings", func(t *testing.T) {
+		g := NewWithT(t)
+		content := strings.ReplaceAll(configMapYAML+"  script: |\n    echo hello\n", "\n", "\r\n")
+		testFS := fstest.MapFS{
+			"configmap.yaml": &fstest.MapFile{Data: []byte(content)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "configmap.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object).To(
+			jqmatcher.Match(`.data.script == "echo hello\n"`),
+		)
+	})
+
+	t.Run("should report positioned error for tab indentation", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Pod\nmetadata:\n\tname: tabbed\n")},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrTabIndentation))
+		g.Expect(err.Error()).To(ContainSubstring("pod.yaml: line 4, column 1"))
+	})
+
+	t.Run("should not blame tabs in block scalars for unrelated errors", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"configmap.yaml": &fstest.MapFile{Data: []byte(
+				"apiVersion: v1\nkind: ConfigMap\ndata:\n  script: |\n    echo\n    \tindented\n  bad: key: value\n",
+			)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "configmap.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrParse))
+		g.Expect(err).ToNot(MatchError(yaml.ErrTabIndentation))
+		g.Expect(err.Error()).To(ContainSubstring("line 7: mapping values are not allowed"))
+	})
+
+	t.Run("should report positioned error for tabs after spaces", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: tabbed\n\t  labels: {}\n")},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrTabIndentation))
+		g.Expect(err.Error()).To(ContainSubstring("pod.yaml: line 5, column 1"))
+	})
+
+	t.Run("should not share decoded documents across files", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"a.yaml": &fstest.MapFile{Data: []byte(multiDocYAML + "---\nnull\n---\n" + configMapYAML)},
+			"b.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+
+		names := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			names = append(names, obj.GetKind()+"/"+obj.GetName())
+		}
+
+		g.Expect(names).To(Equal([]string{"Service/test-service", "Secret/test-secret", "ConfigMap/test-config", "Pod/test-pod"}))
+		g.Expect(objects[2].Object).ToNot(HaveKey("spec"))
+	})
+}
+
+func TestBinaryFilePolicy(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":   &fstest.MapFile{Data: []byte(podYAML)},
+		"image.yaml": &fstest.MapFile{Data: []byte{0x89, 'P', 'N', 'G', 0x00, 0xFF, 0xFE}},
+	}
+
+	t.Run("should fail on binary files by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrBinaryContent))
+		g.Expect(err.Error()).To(ContainSubstring("image.yaml"))
+	})
+
+	t.Run("should skip binary files with a warning", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithBinaryFilePolicy(yaml.PolicyWarn),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("File", "image.yaml"),
+			HaveField("Rule", "binary-file"),
+		)))
+	})
+
+	t.Run("should skip binary files silently", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithBinaryFilePolicy(yaml.PolicyIgnore),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+func TestAllowedExtensions(t *testing.T) {
+	testFS := fstest.MapFS{
+		"config/pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"config/configmap.json": &fstest.MapFile{Data: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)},
+		"config/README.md":      &fstest.MapFile{Data: []byte("# readme")},
+	}
+
+	t.Run("should skip files without a YAML extension by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "config/*"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+	})
+
+	t.Run("should render files with allowed extensions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "config/*"}},
+			yaml.WithAllowedExtensions(".yaml", "json"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].GetKind()).To(Equal("Pod"))
+	})
+
+	t.Run("should use the default extensions for an empty list", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, opts := range [][]yaml.RendererOption{
+			{yaml.WithAllowedExtensions(".json"), yaml.WithAllowedExtensions()},
+			{&yaml.RendererOptions{AllowedExtensions: []string{}}},
+		} {
+			renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "config/*"}}, opts...)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+			g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		}
+	})
+
+	t.Run("should skip disallowed files with a warning", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "config/*"}},
+			yaml.WithExtensionPolicy(yaml.PolicyWarn),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(collector.Warnings()).To(ConsistOf(
+			And(HaveField("File", "config/README.md"), HaveField("Rule", "unsupported-extension")),
+			And(HaveField("File", "config/configmap.json"), HaveField("Rule", "unsupported-extension")),
+		))
+	})
+
+	t.Run("should fail on disallowed files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "config/*"}},
+			yaml.WithAllowedExtensions(".yaml", ".json"),
+			yaml.WithExtensionPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrUnsupportedExtension))
+		g.Expect(err.Error()).To(ContainSubstring("config/README.md"))
+	})
+}
+
+func TestEmptyDocumentPolicy(t *testing.T) {
+	content := podYAML + "\n---\n---\nnull\n---\n# only a comment\n---\n" + configMapYAML
+
+	testFS := fstest.MapFS{
+		"manifests.yaml": &fstest.MapFile{Data: []byte(content)},
+	}
+
+	t.Run("should skip empty documents silently by default", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(collector.Warnings()).To(BeEmpty())
+	})
+
+	t.Run("should warn about each empty document", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithEmptyDocumentPolicy(yaml.PolicyWarn),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(collector.Warnings()).To(HaveLen(3))
+		g.Expect(collector.Warnings()).To(HaveEach(And(
+			HaveField("File", "manifests.yaml"),
+			HaveField("Rule", "empty-document"),
+		)))
+	})
+
+	t.Run("should fail on empty documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithEmptyDocumentPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrEmptyDocument))
+		g.Expect(err.Error()).To(ContainSubstring("manifests.yaml: line"))
+	})
+
+	t.Run("should accept files without empty documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: fstest.MapFS{
+				"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+			}, Path: "*.yaml"}},
+			yaml.WithEmptyDocumentPolicy(yaml.PolicyError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+func TestSkipHidden(t *testing.T) {
+	testFS := fstest.MapFS{
+		"manifests/pod.yaml":         &fstest.MapFile{Data: []byte(podYAML)},
+		"manifests/.backup.yaml":     &fstest.MapFile{Data: []byte(configMapYAML)},
+		"manifests/configmap.yaml~":  &fstest.MapFile{Data: []byte(configMapYAML)},
+		"manifests/#configmap.yaml#": &fstest.MapFile{Data: []byte(configMapYAML)},
+		".git/manifests/pod.yaml":    &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	t.Run("should include hidden files by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "manifests/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should skip hidden files when enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{
+				{FS: testFS, Path: "manifests/*"},
+				{FS: testFS, Path: "*/manifests/*.yaml"},
+			},
+			yaml.WithSkipHidden(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+		g.Expect(err.Error()).To(ContainSubstring("*/manifests/*.yaml"))
+	})
+
+	t.Run("should skip dotfiles and editor backups", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "manifests/*"}},
+			yaml.WithSkipHidden(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+	})
+}
+
+func TestRequireMatch(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	t.Run("should fail on zero matches by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "manifests/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+
+	t.Run("should warn on zero matches when not required", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{
+				{FS: testFS, Path: "manifests/*.yaml"},
+				{FS: testFS, Path: "*.yaml"},
+			},
+			yaml.WithRequireMatch(false),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "no-match"),
+			HaveField("Message", ContainSubstring("manifests/*.yaml")),
+		)))
+	})
+}
+
+func TestExpectedObjectCount(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":   &fstest.MapFile{Data: []byte(podYAML)},
+		"multi.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should accept counts within bounds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml", ExpectAtLeast: 3, ExpectAtMost: 3},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+	})
+
+	t.Run("should fail when fewer objects than expected are rendered", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml", ExpectAtLeast: 2}},
+			yaml.WithFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrUnexpectedObjectCount))
+		g.Expect(err.Error()).To(ContainSubstring("got 1 objects, expected at least 2"))
+	})
+
+	t.Run("should fail when more objects than expected are rendered", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml", ExpectAtMost: 2},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrUnexpectedObjectCount))
+	})
+
+	t.Run("should reject inconsistent expectations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml", ExpectAtLeast: 5, ExpectAtMost: 2},
+		})
+		g.Expect(err).To(MatchError(yaml.ErrInvalidSource))
+	})
+}
+
+func TestPatternValidation(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	for _, pattern := range []string{"[*.yaml", "manifests/[a-.yaml", `pod\`, "*.y[]ml"} {
+		t.Run("should reject malformed pattern "+pattern, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := yaml.New(
+				[]yaml.Source{{FS: testFS, Path: pattern}},
+				yaml.WithWindowsPaths(false),
+			)
+			g.Expect(err).To(MatchError(yaml.ErrInvalidPattern))
+			g.Expect(err).To(MatchError(path.ErrBadPattern))
+		})
+	}
+
+	t.Run("should accept valid patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "[a-z]*.y?ml"},
+			{FS: testFS, Path: `manifests/\*.yaml`},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+}
+
+func TestBraceExpansion(t *testing.T) {
+	testFS := fstest.MapFS{
+		"manifests/base/pod.yaml":      &fstest.MapFile{Data: []byte(podYAML)},
+		"manifests/prod/configmap.yml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"manifests/dev/multi.yaml":     &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should expand alternations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "manifests/{base,prod}/*.y{a,}ml"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+	})
+
+	t.Run("should expand nested alternations without duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "manifests/{base,{prod,dev},*}/*.{yaml,yml}"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+	})
+
+	for _, pattern := range []string{"manifests/{base,prod/*.yaml", "manifests/base}/*.yaml", "{[a-,b}.yaml"} {
+		t.Run("should reject malformed pattern "+pattern, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := yaml.New([]yaml.Source{{FS: testFS, Path: pattern}})
+			g.Expect(err).To(MatchError(yaml.ErrInvalidPattern))
+		})
+	}
+}
+
+func TestCaseInsensitiveMatch(t *testing.T) {
+	testFS := fstest.MapFS{
+		"Manifests/POD.YAML":      &fstest.MapFile{Data: []byte(podYAML)},
+		"manifests/configmap.Yml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"manifests/README.md":     &fstest.MapFile{Data: []byte("# readme")},
+	}
+
+	t.Run("should match case-sensitively by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "manifests/*.y*ml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+
+	t.Run("should match paths and extensions ignoring case", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "manifests/*"}},
+			yaml.WithCaseInsensitiveMatch(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should extend character classes with both cases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "[m]anifests/[a-p]*.{yaml,yml}"}},
+			yaml.WithCaseInsensitiveMatch(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+}
+
+func TestWindowsPaths(t *testing.T) {
+	testFS := fstest.MapFS{
+		"manifests/base/pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	for _, pattern := range []string{
+		`manifests\base\*.yaml`,
+		`manifests/base\*.yaml`,
+		`C:\manifests\base\*.yaml`,
+		`c:/manifests/base/*.yaml`,
+		`\manifests\base\pod.yaml`,
+	} {
+		t.Run("should normalize "+pattern, func(t *testing.T) {
+			g := NewWithT(t)
+
+			renderer, err := yaml.New(
+				[]yaml.Source{{FS: testFS, Path: pattern}},
+				yaml.WithWindowsPaths(true),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+		})
+	}
+
+	t.Run("should keep backslash escapes when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: `manifests/base/\pod.yaml`}},
+			yaml.WithWindowsPaths(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+func TestRecursiveGlob(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":                     &fstest.MapFile{Data: []byte(podYAML)},
+		"manifests/configmap.yaml":     &fstest.MapFile{Data: []byte(configMapYAML)},
+		"manifests/nested/multi.yml":   &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"manifests/nested/deep/x.json": &fstest.MapFile{Data: []byte("{}")},
+	}
+
+	t.Run("should match files at any depth", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "**/*.{yaml,yml}"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+	})
+
+	t.Run("should match below a literal prefix", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "manifests/**/*.yml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Service"))
+	})
+
+	t.Run("should match nothing below a missing prefix", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "missing/**/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+}
+
+func TestNewFromStrings(t *testing.T) {
+
+	t.Run("should render inline manifests", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{
+			"pod.yaml":            podYAML,
+			"apps/configmap.yml":  configMapYAML,
+			"apps/multi-doc.yaml": multiDocYAML,
+			"README.md":           "# not a manifest",
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].GetKind()).To(Equal("Service"))
+		g.Expect(objects[2].GetKind()).To(Equal("Secret"))
+		g.Expect(objects[3].GetKind()).To(Equal("Pod"))
+	})
+
+	t.Run("should apply options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"pod.yaml": podYAML},
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "pod.yaml"))
+	})
+
+	t.Run("should fail when no manifests are given", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrNoFilesMatched))
+	})
+}
+
+func TestObjectsSource(t *testing.T) {
+	configMap := unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("prebuilt")
+
+	t.Run("should pass objects through alongside file sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "pod.yaml"},
+			{Objects: []unstructured.Unstructured{configMap}},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		g.Expect(objects[1].GetName()).To(Equal("prebuilt"))
+	})
+
+	t.Run("should apply filters and transformers without mutating input", func(t *testing.T) {
+		g := NewWithT(t)
+
+		input := []unstructured.Unstructured{configMap}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Objects: input, Path: "prebuilt"}},
+			yaml.WithTransformer(labels.Set(map[string]string{"team": "platform"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for range 2 {
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+			g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("team", "platform"))
+		}
+
+		g.Expect(input[0].GetLabels()).To(BeEmpty())
+	})
+
+	t.Run("should reject sources with both FS and Objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{{
+			FS:      fstest.MapFS{},
+			Path:    "*.yaml",
+			Objects: []unstructured.Unstructured{configMap},
+		}})
+		g.Expect(err).To(MatchError(yaml.ErrInvalidSource))
+	})
+}
+
+func TestAddRemoveSource(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	t.Run("should render added sources and drop removed ones", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{Name: "pods", FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(renderer.AddSource(yaml.Source{Name: "config", FS: testFS, Path: "configmap.yaml"})).To(Succeed())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(renderer.RemoveSource("pods")).To(BeTrue())
+		g.Expect(renderer.RemoveSource("pods")).To(BeFalse())
+
+		objects, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+	})
+
+	t.Run("should reject invalid and duplicate sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{Name: "pods", FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(renderer.AddSource(yaml.Source{Name: "pods", FS: testFS, Path: "configmap.yaml"})).
+			To(MatchError(yaml.ErrInvalidSource))
+		g.Expect(renderer.AddSource(yaml.Source{FS: testFS, Path: "[invalid"})).
+			To(MatchError(yaml.ErrInvalidPattern))
+
+		_, err = yaml.New([]yaml.Source{
+			{Name: "pods", FS: testFS, Path: "pod.yaml"},
+			{Name: "pods", FS: testFS, Path: "configmap.yaml"},
+		})
+		g.Expect(err).To(MatchError(yaml.ErrInvalidSource))
+	})
+
+	t.Run("should be safe to mutate sources while rendering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{Name: "pods", FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				_, err := renderer.Process(t.Context(), nil)
+				g.Expect(err).ToNot(HaveOccurred())
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				name := fmt.Sprintf("config-%d", i)
+				g.Expect(renderer.AddSource(yaml.Source{Name: name, FS: testFS, Path: "configmap.yaml"})).To(Succeed())
+				g.Expect(renderer.RemoveSource(name)).To(BeTrue())
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestProcessBySource(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should group objects by source", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{
+				{Name: "pods", FS: testFS, Path: "pod.yaml"},
+				{FS: testFS, Path: "multi-doc.yaml"},
+				{Name: "config", FS: testFS, Path: "configmap.yaml"},
+				{Name: "empty", FS: testFS, Path: "missing/*.yaml"},
+			},
+			yaml.WithRequireMatch(false),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		groups, err := renderer.ProcessBySource(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(groups).To(HaveLen(4))
+		g.Expect(groups["pods"]).To(HaveLen(1))
+		g.Expect(groups["pods"][0].GetKind()).To(Equal("Pod"))
+		g.Expect(groups["multi-doc.yaml"]).To(HaveLen(2))
+		g.Expect(groups["config"]).To(HaveLen(1))
+		g.Expect(groups["config"][0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(groups["empty"]).To(BeEmpty())
+	})
+}
+
+func TestSources(t *testing.T) {
+	testFS := fstest.MapFS{
+		"manifests/pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"manifests/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	t.Run("should report sources and resolved files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{Name: "manifests", FS: testFS, Path: "manifests/*.yaml"},
+			{Path: "prebuilt", Objects: []unstructured.Unstructured{}},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		sources := renderer.Sources()
+		g.Expect(sources).To(HaveLen(2))
+		g.Expect(sources[0].Name).To(Equal("manifests"))
+		g.Expect(sources[0].Path).To(Equal("manifests/*.yaml"))
+		g.Expect(sources[0].Files).To(BeNil())
+		g.Expect(sources[1].Path).To(Equal("prebuilt"))
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		sources = renderer.Sources()
+		g.Expect(sources[0].Files).To(Equal([]string{"manifests/configmap.yaml", "manifests/pod.yaml"}))
+		g.Expect(sources[1].Files).To(BeNil())
+	})
+}
+
+func TestConcurrentProcess(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should render concurrently while sharing the cache", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithCache(),
+			yaml.WithOriginals(yaml.NewOriginals()),
+			yaml.WithSourceAnnotations(true),
+			yaml.WithTransformer(labels.Set(map[string]string{"env": "test"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				objects, err := renderer.Process(t.Context(), nil)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(objects).To(HaveLen(4))
+
+				// Mutating results must not affect other callers or the cache
+				for i := range objects {
+					objects[i].SetName("mutated")
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveEach(WithTransform(func(obj unstructured.Unstructured) string {
+			return obj.GetName()
+		}, Not(Equal("mutated")))))
+	})
+}
+
+func TestContextCancellation(t *testing.T) {
+	testFS := fstest.MapFS{
+		"a/pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"b/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"c/multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should not render with a canceled context", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "**/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(yaml.ErrRenderInterrupted))
+		g.Expect(err).To(MatchError(context.Canceled))
+	})
+
+	t.Run("should stop between documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		documents := 0
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "c/*.yaml"}},
+			yaml.WithDocumentHook(func(_ context.Context, _ yaml.DocumentInfo, _ *unstructured.Unstructured) error {
+				documents++
+				cancel()
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(context.Canceled))
+		g.Expect(documents).To(Equal(1))
+	})
+
+	t.Run("should stop between files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		var files []string
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "**/*.yaml"}},
+			yaml.WithDocumentHook(func(_ context.Context, info yaml.DocumentInfo, _ *unstructured.Unstructured) error {
+				files = append(files, info.File)
+				cancel()
+
+				return nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(yaml.ErrRenderInterrupted))
+		g.Expect(files).To(Equal([]string{"a/pod.yaml"}))
+	})
+}
+
+func BenchmarkYamlRenderSingleFile(b *testing.B) {
+	testFS := fstest.MapFS{
+		"config/multi.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	renderer, err := yaml.New([]yaml.Source{
+		{FS: testFS, Path: "config/multi.yaml"},
+	})
+	if err != nil {
+		b.Fatalf("failed to create renderer: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, err := renderer.Process(b.Context(), nil)
+		if err != nil {
+			b.Fatalf("failed to render: %v", err)
+		}
+	}
+}
+
+func BenchmarkYamlRenderLargeFile(b *testing.B) {
+	testFS := fstest.MapFS{
+		"large.yaml": &fstest.MapFile{Data: []byte(strings.Repeat(configMapYAML+"\n---\n", 200))},
+	}
+
+	renderer, err := yaml.New([]yaml.Source{
+		{FS: testFS, Path: "*.yaml"},
+	})
+	if err != nil {
+		b.Fatalf("failed to create renderer: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, err := renderer.Process(b.Context(), nil)
+		if err != nil {
+			b.Fatalf("failed to render: %v", err)
+		}
+	}
+}
+
+func BenchmarkYamlRenderManyFiles(b *testing.B) {
+	testFS := fstest.MapFS{}
+	for i := range 100 {
+		testFS[fmt.Sprintf("config/%03d.yaml", i)] = &fstest.MapFile{Data: []byte(multiDocYAML)}
+	}
+
+	renderer, err := yaml.New([]yaml.Source{
+		{FS: testFS, Path: "config/*.yaml"},
+	})
+	if err != nil {
+		b.Fatalf("failed to create renderer: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, err := renderer.Process(b.Context(), nil)
+		if err != nil {
+			b.Fatalf("failed to render: %v", err)
+		}
+	}
 }
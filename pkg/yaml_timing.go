@@ -0,0 +1,136 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// FileTiming is the time spent on a single file of a source during a render.
+type FileTiming struct {
+	// File is the path of the file within the source filesystem.
+	File string
+
+	// Decode is the time spent reading, checking and decoding the file, including document hooks.
+	Decode time.Duration
+
+	// Transform is the time spent running renderer filters and transformers on the objects of
+	// the file.
+	Transform time.Duration
+
+	// Objects is the number of objects decoded from the file.
+	Objects int
+}
+
+// Total returns the decode and transform time of the file.
+func (t FileTiming) Total() time.Duration {
+	return t.Decode + t.Transform
+}
+
+// fileTimingsKey carries the file timings recorder of a source render in the render context.
+type fileTimingsKey struct{}
+
+// fileTimings records the decode time of the files of a source render.
+type fileTimings struct {
+	mu      sync.Mutex
+	timings []FileTiming
+}
+
+// withFileTimings returns a context recording file timings into the returned recorder.
+func withFileTimings(ctx context.Context) (context.Context, *fileTimings) {
+	recorder := &fileTimings{}
+
+	return context.WithValue(ctx, fileTimingsKey{}, recorder), recorder
+}
+
+// recordDecodeTime records the decode time of a file. Cache refreshes running in the
+// background are not part of the render and are not recorded.
+func recordDecodeTime(ctx context.Context, file string, d time.Duration, objects int) {
+	recorder, ok := ctx.Value(fileTimingsKey{}).(*fileTimings)
+	if !ok || isCacheRefresh(ctx) {
+		return
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.timings = append(recorder.timings, FileTiming{File: file, Decode: d, Objects: objects})
+}
+
+// list returns a copy of the recorded timings.
+func (t *fileTimings) list() []FileTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.timings)
+}
+
+// applyPipelineByFile applies the renderer pipeline to the objects of each file in turn, so
+// the transform time is attributed to files. Objects not matching the recorded files (e.g.
+// cached or stale results) are processed at once, without transform times.
+func (r *Renderer) applyPipelineByFile(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	timings []FileTiming,
+) ([]unstructured.Unstructured, error) {
+	total := 0
+	for _, t := range timings {
+		total += t.Objects
+	}
+
+	if len(timings) < 2 || total != len(objects) {
+		start := time.Now()
+		transformed, err := r.applyPipeline(ctx, objects)
+
+		if len(timings) == 1 && total == len(objects) {
+			timings[0].Transform = time.Since(start)
+		}
+
+		return transformed, err
+	}
+
+	result := make([]unstructured.Unstructured, 0, len(objects))
+	offset := 0
+
+	for i := range timings {
+		start := time.Now()
+
+		transformed, err := r.applyPipeline(ctx, objects[offset:offset+timings[i].Objects])
+		if err != nil {
+			return nil, err
+		}
+
+		timings[i].Transform = time.Since(start)
+		offset += timings[i].Objects
+		result = append(result, transformed...)
+	}
+
+	return result, nil
+}
+
+// reportSlowFiles reports a slow-file warning for every file whose decode and transform time
+// exceeds the threshold set with WithSlowFileThreshold.
+func (r *Renderer) reportSlowFiles(ctx context.Context, timings []FileTiming) {
+	if r.opts.SlowFileThreshold <= 0 {
+		return
+	}
+
+	for _, t := range timings {
+		if t.Total() <= r.opts.SlowFileThreshold {
+			continue
+		}
+
+		ReportWarning(ctx, Warning{
+			File: t.File,
+			Rule: "slow-file",
+			Message: fmt.Sprintf(
+				"file took %s (decode %s, transform %s), above the %s threshold",
+				t.Total(), t.Decode, t.Transform, r.opts.SlowFileThreshold,
+			),
+		})
+	}
+}
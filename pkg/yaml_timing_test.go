@@ -0,0 +1,85 @@
+package yaml_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFileTimings(t *testing.T) {
+	testFS := fstest.MapFS{
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	// slowPods makes transforming pods slow, so pod.yaml stands out
+	slowPods := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GetKind() == "Pod" {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		return obj, nil
+	}
+
+	render := func(t *testing.T, opts ...yaml.RendererOption) ([]yaml.SourceReport, []yaml.Warning) {
+		t.Helper()
+		g := NewWithT(t)
+
+		var report yaml.RenderReport
+
+		collector := &warningCollector{}
+		opts = append(opts,
+			yaml.WithTransformer(slowPods),
+			yaml.WithWarningHandler(collector.Handle),
+			yaml.WithPostRenderHook(func(_ context.Context, _ []unstructured.Unstructured, r yaml.RenderReport) error {
+				report = r
+
+				return nil
+			}),
+		)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		return report.Sources, collector.Warnings()
+	}
+
+	t.Run("should record decode and transform time per file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sources, warnings := render(t)
+		g.Expect(warnings).To(BeEmpty())
+		g.Expect(sources).To(HaveLen(1))
+
+		timings := sources[0].Timings
+		g.Expect(timings).To(HaveLen(2))
+		g.Expect(timings[0].File).To(Equal("multi-doc.yaml"))
+		g.Expect(timings[0].Objects).To(Equal(2))
+		g.Expect(timings[0].Decode).To(BeNumerically(">", 0))
+		g.Expect(timings[1].File).To(Equal("pod.yaml"))
+		g.Expect(timings[1].Objects).To(Equal(1))
+		g.Expect(timings[1].Transform).To(BeNumerically(">=", 50*time.Millisecond))
+		g.Expect(timings[1].Total()).To(Equal(timings[1].Decode + timings[1].Transform))
+	})
+
+	t.Run("should warn about files above the threshold", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, warnings := render(t, yaml.WithSlowFileThreshold(25*time.Millisecond))
+		g.Expect(warnings).To(ConsistOf(And(
+			HaveField("File", "pod.yaml"),
+			HaveField("Rule", "slow-file"),
+			HaveField("Message", ContainSubstring("above the 25ms threshold")),
+		)))
+	})
+}
@@ -0,0 +1,215 @@
+package yaml
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/k8s-manifest-kit/engine/pkg/pipeline"
+	"github.com/k8s-manifest-kit/engine/pkg/transformer"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Stage orders renderer transformers: transformers run in ascending stage order, and in
+// registration order within a stage. Stages are plain integers, so custom stages can be
+// placed between the predefined ones (e.g. StageMutate + 10).
+type Stage int
+
+const (
+	// StageSanitize is for transformers that clean up input, e.g. removing status or
+	// server-populated fields.
+	StageSanitize Stage = 100
+
+	// StageMutate is for transformers that change objects, e.g. adding labels or converting
+	// versions. It is the stage of transformers added with WithTransformer.
+	StageMutate Stage = 200
+
+	// StageFinalize is for transformers that must see the final objects, e.g. computing
+	// hashes or adding ownership metadata.
+	StageFinalize Stage = 300
+)
+
+// TransformerErrorMode controls how the renderer reacts to a failing transformer.
+type TransformerErrorMode int
+
+const (
+	// TransformerErrorAbort fails the render on the first transformer error.
+	TransformerErrorAbort TransformerErrorMode = iota
+
+	// TransformerErrorSkipTransformer reports a warning and continues with the object as it was
+	// before the failing transformer.
+	TransformerErrorSkipTransformer
+
+	// TransformerErrorSkipObject reports a warning and drops the object from the output.
+	TransformerErrorSkipObject
+)
+
+// StagedTransformer is a transformer assigned to a stage.
+type StagedTransformer struct {
+	Stage       Stage
+	Transformer types.Transformer
+}
+
+// orderTransformers returns the transformers of opts in execution order: transformers added
+// without a stage run in StageMutate, and the sort is stable so registration order is kept
+// within a stage.
+func orderTransformers(opts RendererOptions) []types.Transformer {
+	if len(opts.StagedTransformers) == 0 {
+		return opts.Transformers
+	}
+
+	staged := make([]StagedTransformer, 0, len(opts.Transformers)+len(opts.StagedTransformers))
+	for _, t := range opts.Transformers {
+		staged = append(staged, StagedTransformer{Stage: StageMutate, Transformer: t})
+	}
+
+	staged = append(staged, opts.StagedTransformers...)
+
+	slices.SortStableFunc(staged, func(a StagedTransformer, b StagedTransformer) int {
+		return cmp.Compare(a.Stage, b.Stage)
+	})
+
+	transformers := make([]types.Transformer, len(staged))
+	for i := range staged {
+		transformers[i] = staged[i].Transformer
+	}
+
+	return transformers
+}
+
+// TransformIf returns a transformer that applies t only to objects matching predicate; other
+// objects are returned unchanged. Any filter can serve as a predicate, including the engine's
+// gvk, labels, name and namespace filters and their And/Or/Not combinations.
+//
+// Example:
+//
+//	yaml.WithTransformer(yaml.TransformIf(
+//		yaml.MatchGroupKinds(schema.GroupKind{Group: "apps", Kind: "Deployment"}),
+//		addReplicas,
+//	))
+func TransformIf(predicate types.Filter, t types.Transformer) types.Transformer {
+	return transformer.If(predicate, t)
+}
+
+// MatchGroupKinds returns a predicate matching objects of any of the given group kinds,
+// regardless of their API version.
+func MatchGroupKinds(gks ...schema.GroupKind) types.Filter {
+	s := sets.New(gks...)
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return s.Has(obj.GroupVersionKind().GroupKind()), nil
+	}
+}
+
+// MatchLabelSelector returns a predicate matching objects whose labels match selector, as used
+// in Kubernetes API objects. A nil selector matches nothing and an empty selector matches every
+// object, following the API conventions.
+func MatchLabelSelector(selector *metav1.LabelSelector) (types.Filter, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return s.Matches(labels.Set(obj.GetLabels())), nil
+	}, nil
+}
+
+// MatchMetadata returns a predicate matching objects whose labels and annotations match a label
+// selector expression such as "env=prod" or "tier in (web,api),!canary". Labels take precedence
+// over annotations with the same key.
+func MatchMetadata(selector string) (types.Filter, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		metadata := labels.Merge(obj.GetAnnotations(), obj.GetLabels())
+
+		return s.Matches(metadata), nil
+	}, nil
+}
+
+// applyPipeline applies the renderer filters and transformers to objects, handling transformer
+// errors according to the configured TransformerErrorMode.
+func (r *Renderer) applyPipeline(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error) {
+	if r.opts.TransformerErrorMode == TransformerErrorAbort {
+		return pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+	}
+
+	filtered, err := pipeline.ApplyFilters(ctx, objects, r.opts.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("filter error: %w", err)
+	}
+
+	transformed := make([]unstructured.Unstructured, 0, len(filtered))
+
+	for _, obj := range filtered {
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+
+		result, ok := r.transformObject(ctx, obj)
+		if ok {
+			transformed = append(transformed, result)
+		}
+	}
+
+	return transformed, nil
+}
+
+// transformObject runs the transformers on a single object, reporting errors as warnings. It
+// returns false if the object must be dropped. When failing transformers are skipped, each
+// transformer gets a copy of the object, so changes made before failing are discarded.
+func (r *Renderer) transformObject(
+	ctx context.Context,
+	obj unstructured.Unstructured,
+) (unstructured.Unstructured, bool) {
+	result := obj
+	skip := r.opts.TransformerErrorMode == TransformerErrorSkipTransformer
+
+	for i, t := range r.opts.Transformers {
+		input := result
+		if skip {
+			input = *result.DeepCopy()
+		}
+
+		transformed, err := t(ctx, input)
+		if err == nil {
+			result = transformed
+
+			continue
+		}
+
+		err = transformer.Wrap(obj, r.redactError(err, []unstructured.Unstructured{obj}))
+
+		if r.opts.TransformerErrorMode == TransformerErrorSkipObject {
+			ReportWarning(ctx, Warning{
+				File:    sourceFile(obj),
+				Rule:    "transformer-error",
+				Message: fmt.Sprintf("dropped object after transformer %d failed: %v", i, err),
+			})
+
+			return unstructured.Unstructured{}, false
+		}
+
+		ReportWarning(ctx, Warning{
+			File:    sourceFile(obj),
+			Rule:    "transformer-error",
+			Message: fmt.Sprintf("skipped transformer %d: %v", i, err),
+		})
+	}
+
+	return result, true
+}
@@ -0,0 +1,305 @@
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/labels"
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTransformIf(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	render := func(t *testing.T, opts ...yaml.RendererOption) map[string]map[string]string {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result := make(map[string]map[string]string, len(objects))
+		for _, obj := range objects {
+			result[obj.GetKind()] = obj.GetLabels()
+		}
+
+		return result
+	}
+
+	t.Run("should transform objects matching a group kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result := render(t, yaml.WithTransformer(yaml.TransformIf(
+			yaml.MatchGroupKinds(schema.GroupKind{Kind: "Pod"}),
+			labels.Set(map[string]string{"matched": "true"}),
+		)))
+
+		g.Expect(result["Pod"]).To(HaveKeyWithValue("matched", "true"))
+		g.Expect(result["ConfigMap"]).ToNot(HaveKey("matched"))
+	})
+
+	t.Run("should transform objects matching a label selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		predicate, err := yaml.MatchLabelSelector(&metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "component",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"backend"},
+			}},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result := render(t, yaml.WithTransformer(yaml.TransformIf(
+			predicate,
+			labels.Set(map[string]string{"matched": "true"}),
+		)))
+
+		g.Expect(result["ConfigMap"]).To(HaveKeyWithValue("matched", "true"))
+		g.Expect(result["Pod"]).ToNot(HaveKey("matched"))
+	})
+
+	t.Run("should match nothing with a nil label selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		predicate, err := yaml.MatchLabelSelector(nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result := render(t, yaml.WithTransformer(yaml.TransformIf(
+			predicate,
+			labels.Set(map[string]string{"matched": "true"}),
+		)))
+
+		g.Expect(result["ConfigMap"]).ToNot(HaveKey("matched"))
+		g.Expect(result["Pod"]).ToNot(HaveKey("matched"))
+	})
+
+	t.Run("should reject invalid label selectors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.MatchLabelSelector(&metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "component",
+				Operator: metav1.LabelSelectorOpIn,
+			}},
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid label selector")))
+	})
+}
+
+func TestTransformerErrorMode(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+	}
+
+	errEnrich := errors.New("enrichment unavailable")
+	failOnPod := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GetKind() == "Pod" {
+			return unstructured.Unstructured{}, errEnrich
+		}
+
+		return obj, nil
+	}
+
+	transformers := []types.Transformer{
+		labels.Set(map[string]string{"before": "true"}),
+		failOnPod,
+		labels.Set(map[string]string{"after": "true"}),
+	}
+
+	t.Run("should abort on transformer errors by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformers(transformers...),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errEnrich))
+	})
+
+	t.Run("should skip the failing transformer", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformers(transformers...),
+			yaml.WithTransformerErrorMode(yaml.TransformerErrorSkipTransformer),
+			yaml.WithSourceAnnotations(true),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(And(
+				HaveKeyWithValue("before", "true"),
+				HaveKeyWithValue("after", "true"),
+			))
+		}
+
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("File", "pod.yaml"),
+			HaveField("Rule", "transformer-error"),
+			HaveField("Message", And(
+				ContainSubstring("skipped transformer 1"),
+				ContainSubstring("test-pod"),
+				ContainSubstring("enrichment unavailable"),
+			)),
+		)))
+	})
+
+	t.Run("should discard changes of a skipped transformer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mutateAndFail := func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			obj.SetLabels(map[string]string{"partial": "true"})
+
+			if obj.GetKind() == "Pod" {
+				return unstructured.Unstructured{}, errEnrich
+			}
+
+			return obj, nil
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformers(labels.Set(map[string]string{"before": "true"}), mutateAndFail),
+			yaml.WithTransformerErrorMode(yaml.TransformerErrorSkipTransformer),
+			yaml.WithWarningHandler((&warningCollector{}).Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			if obj.GetKind() == "Pod" {
+				g.Expect(obj.GetLabels()).To(And(
+					HaveKeyWithValue("before", "true"),
+					Not(HaveKey("partial")),
+				))
+			} else {
+				g.Expect(obj.GetLabels()).To(Equal(map[string]string{"partial": "true"}))
+			}
+		}
+	})
+
+	t.Run("should drop the object", func(t *testing.T) {
+		g := NewWithT(t)
+		collector := &warningCollector{}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithTransformers(transformers...),
+			yaml.WithTransformerErrorMode(yaml.TransformerErrorSkipObject),
+			yaml.WithWarningHandler(collector.Handle),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(collector.Warnings()).To(ConsistOf(And(
+			HaveField("Rule", "transformer-error"),
+			HaveField("Message", ContainSubstring("dropped object after transformer 1 failed")),
+		)))
+	})
+}
+
+const environmentsYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: prod-config
+  labels:
+    env: prod
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: staging-config
+  labels:
+    env: staging
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: annotated-config
+  annotations:
+    env: prod
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared-config
+`
+
+func TestTargetSelector(t *testing.T) {
+	render := func(t *testing.T, selector string) []string {
+		t.Helper()
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"config.yaml": environmentsYAML},
+			yaml.WithTargetSelector(selector),
+			// Transformers see only the targeted objects
+			yaml.WithTransformer(labels.Set(map[string]string{"env": "ignored"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		names := make([]string, len(objects))
+		for i := range objects {
+			names[i] = objects[i].GetName()
+		}
+
+		return names
+	}
+
+	t.Run("should keep objects with matching labels or annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(render(t, "env=prod")).To(Equal([]string{"prod-config", "annotated-config"}))
+	})
+
+	t.Run("should support set-based expressions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(render(t, "env notin (staging)")).To(Equal([]string{"prod-config", "annotated-config", "shared-config"}))
+	})
+
+	t.Run("should reject invalid selectors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.NewFromStrings(nil, yaml.WithTargetSelector("env in prod"))
+		g.Expect(err).To(MatchError(ContainSubstring("invalid target selector")))
+	})
+}
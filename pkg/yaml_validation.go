@@ -0,0 +1,122 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// ErrKindNotServed is returned when an object's GroupVersionKind is not served by the target cluster.
+	ErrKindNotServed = newClassError(ErrValidation, "kind is not served by the target cluster")
+
+	// ErrDeniedKind is returned when an object's kind is on the renderer's deny list.
+	ErrDeniedKind = newClassError(ErrValidation, "kind is denied")
+)
+
+// Validator is a function type that checks a single rendered object and returns an error
+// if the object must be rejected. Validators run after filters and transformers.
+type Validator func(ctx context.Context, object unstructured.Unstructured) error
+
+// ResourceDiscovery is the subset of the client-go discovery client needed to check which
+// resources a cluster serves. A *discovery.DiscoveryClient (or a cached/memory discovery client)
+// satisfies this interface.
+type ResourceDiscovery interface {
+	ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error)
+}
+
+// StaticDiscovery is a ResourceDiscovery backed by pre-fetched discovery data.
+// It is useful for offline validation against a snapshot of a cluster's API surface.
+type StaticDiscovery []*metav1.APIResourceList
+
+// ServerResourcesForGroupVersion returns the resource list for the given group version,
+// or a NotFound error if the group version is not part of the snapshot.
+func (d StaticDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	for _, list := range d {
+		if list != nil && list.GroupVersion == groupVersion {
+			return list, nil
+		}
+	}
+
+	gv, err := schema.ParseGroupVersion(groupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group version %q: %w", groupVersion, err)
+	}
+
+	return nil, apierrors.NewNotFound(gv.WithResource("").GroupResource(), groupVersion)
+}
+
+// ValidateKindServed returns a validator that rejects objects whose GroupVersionKind
+// is not served by the cluster described by the given discovery client.
+// For repeated renders, pass a cached discovery client to avoid a round trip per object.
+func ValidateKindServed(discovery ResourceDiscovery) Validator {
+	return func(_ context.Context, object unstructured.Unstructured) error {
+		gvk := object.GroupVersionKind()
+
+		list, err := discovery.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("%w: %s", ErrKindNotServed, gvk)
+			}
+
+			return fmt.Errorf("failed to discover resources for %s: %w", gvk.GroupVersion(), err)
+		}
+
+		for _, resource := range list.APIResources {
+			if resource.Kind == gvk.Kind {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %s", ErrKindNotServed, gvk)
+	}
+}
+
+// DenyKinds returns a validator that rejects objects of the given kinds.
+// Kinds are written as "Kind" (any group) or "Kind.group" (e.g. "ClusterRoleBinding.rbac.authorization.k8s.io").
+func DenyKinds(kinds ...string) Validator {
+	denied := make([]schema.GroupKind, len(kinds))
+	for i, kind := range kinds {
+		denied[i] = schema.ParseGroupKind(kind)
+	}
+
+	return func(_ context.Context, object unstructured.Unstructured) error {
+		gk := object.GroupVersionKind().GroupKind()
+
+		for _, d := range denied {
+			if d.Kind == gk.Kind && (d.Group == "" || d.Group == gk.Group) {
+				return fmt.Errorf("%w: %s", ErrDeniedKind, gk)
+			}
+		}
+
+		return nil
+	}
+}
+
+// applyValidators runs all validators against every object, stopping at the first failure.
+// It returns the index of the failing object with the error.
+func applyValidators(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	validators []Validator,
+) (int, error) {
+	for i, obj := range objects {
+		for _, v := range validators {
+			if err := v(ctx, obj); err != nil {
+				return i, classify(ErrValidation, fmt.Errorf(
+					"validation failed for %s %s (namespace: %s): %w",
+					obj.GroupVersionKind(),
+					obj.GetName(),
+					obj.GetNamespace(),
+					err,
+				))
+			}
+		}
+	}
+
+	return -1, nil
+}
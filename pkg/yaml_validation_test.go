@@ -0,0 +1,147 @@
+package yaml_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidateKindServed(t *testing.T) {
+	discovery := yaml.StaticDiscovery{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true},
+			},
+		},
+	}
+
+	t.Run("should accept served kinds", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithValidator(yaml.ValidateKindServed(discovery)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should reject kinds missing from a served group version", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithValidator(yaml.ValidateKindServed(discovery)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrKindNotServed))
+		g.Expect(err.Error()).To(ContainSubstring("test-config"))
+	})
+
+	t.Run("should reject unknown group versions", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"deployment.yaml": &fstest.MapFile{Data: []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-deployment
+`)},
+		}
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "*.yaml"}},
+			yaml.WithValidator(yaml.ValidateKindServed(discovery)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrKindNotServed))
+	})
+}
+
+func TestDeniedKinds(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"multi-doc.yaml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"binding.yaml": &fstest.MapFile{Data: []byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: admin-binding
+`)},
+	}
+
+	t.Run("should reject denied kinds with the offending object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "multi-doc.yaml"}},
+			yaml.WithDeniedKinds("Secret"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrDeniedKind))
+		g.Expect(err.Error()).To(ContainSubstring("test-secret"))
+	})
+
+	t.Run("should match kinds qualified with a group", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "binding.yaml"}},
+			yaml.WithDeniedKinds("ClusterRoleBinding.rbac.authorization.k8s.io"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(yaml.ErrDeniedKind))
+		g.Expect(err.Error()).To(ContainSubstring("admin-binding"))
+	})
+
+	t.Run("should not match kinds from other groups", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "binding.yaml"}},
+			yaml.WithDeniedKinds("ClusterRoleBinding.example.com"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should accept allowed kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "pod.yaml"}},
+			yaml.WithDeniedKinds("Secret", "ClusterRoleBinding"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
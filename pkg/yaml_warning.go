@@ -0,0 +1,70 @@
+package yaml
+
+import (
+	"context"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Policy controls how the renderer reacts to a detected condition.
+type Policy int
+
+const (
+	// PolicyIgnore silently ignores the condition.
+	PolicyIgnore Policy = iota
+
+	// PolicyWarn reports the condition as a warning and continues rendering.
+	PolicyWarn
+
+	// PolicyError fails the render.
+	PolicyError
+)
+
+// Warning describes a non-fatal issue detected while rendering.
+type Warning struct {
+	// File is the source file the warning relates to, if known.
+	File string
+
+	// Line is the 1-based line in File the warning relates to, or 0 if unknown.
+	Line int
+
+	// Rule is a short identifier of the check that produced the warning (e.g. "deprecated-api").
+	Rule string
+
+	// Message is a human-readable description of the issue.
+	Message string
+}
+
+// WarningHandler receives warnings reported during Process().
+// Handlers must be safe for concurrent use if the renderer is used concurrently.
+type WarningHandler func(ctx context.Context, warning Warning)
+
+type warningHandlerKey struct{}
+
+// withWarningHandler returns a context carrying the given warning handler.
+func withWarningHandler(ctx context.Context, handler WarningHandler) context.Context {
+	if handler == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, warningHandlerKey{}, handler)
+}
+
+// ReportWarning reports a warning to the handler configured via WithWarningHandler.
+// Filters, transformers and validators running inside Process() can use it to surface
+// non-fatal issues. It is a no-op when no handler is configured, except that warnings are
+// always recorded by Diagnose.
+func ReportWarning(ctx context.Context, warning Warning) {
+	collectDiagnostic(ctx, warning)
+
+	if handler, ok := ctx.Value(warningHandlerKey{}).(WarningHandler); ok {
+		handler(ctx, warning)
+	}
+}
+
+// sourceFile returns the source file annotation of an object, if present.
+func sourceFile(obj unstructured.Unstructured) string {
+	return obj.GetAnnotations()[types.AnnotationSourceFile]
+}
@@ -0,0 +1,517 @@
+package yaml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// ErrInvalidContainer is returned when a container fragment to inject has no name.
+var ErrInvalidContainer = errors.New("invalid container")
+
+// podSpecPaths are the paths of the pod spec in the built-in workload kinds.
+var podSpecPaths = map[schema.GroupKind][]string{
+	{Group: "", Kind: "Pod"}:                   {"spec"},
+	{Group: "", Kind: "PodTemplate"}:           {"template", "spec"},
+	{Group: "", Kind: "ReplicationController"}: {"spec", "template", "spec"},
+	{Group: "apps", Kind: "Deployment"}:        {"spec", "template", "spec"},
+	{Group: "apps", Kind: "StatefulSet"}:       {"spec", "template", "spec"},
+	{Group: "apps", Kind: "DaemonSet"}:         {"spec", "template", "spec"},
+	{Group: "apps", Kind: "ReplicaSet"}:        {"spec", "template", "spec"},
+	{Group: "batch", Kind: "Job"}:              {"spec", "template", "spec"},
+	{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// podSpec returns the pod spec of a workload object for in-place modification. It reports false
+// for objects that are not workloads or have no pod spec.
+func podSpec(obj unstructured.Unstructured) (map[string]any, bool) {
+	fields, ok := podSpecPaths[obj.GroupVersionKind().GroupKind()]
+	if !ok {
+		return nil, false
+	}
+
+	spec, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	if !ok {
+		return nil, false
+	}
+
+	m, ok := spec.(map[string]any)
+
+	return m, ok
+}
+
+// SchedulingDefaults are scheduling constraints added to pod templates by DefaultScheduling.
+type SchedulingDefaults struct {
+	// NodeSelector entries are added unless the pod template selects the same label key.
+	NodeSelector map[string]string
+
+	// Tolerations are added unless the pod template tolerates the same key and effect.
+	Tolerations []corev1.Toleration
+
+	// Affinity node, pod and pod anti-affinity are each set unless the pod template defines them.
+	Affinity *corev1.Affinity
+}
+
+// DefaultScheduling returns a transformer that adds scheduling defaults to the pod templates of
+// workloads (Pods, Deployments, StatefulSets, DaemonSets, ReplicaSets, ReplicationControllers,
+// Jobs, CronJobs and PodTemplates). Defaults are merged: values already set in a pod template
+// take precedence. Other objects are returned unchanged.
+//
+// Example:
+//
+//	t, err := yaml.DefaultScheduling(yaml.SchedulingDefaults{
+//		NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+//		Tolerations:  []corev1.Toleration{{Key: "dedicated", Value: "apps", Effect: corev1.TaintEffectNoSchedule}},
+//	})
+func DefaultScheduling(defaults SchedulingDefaults) (types.Transformer, error) {
+	tolerations := make([]map[string]any, len(defaults.Tolerations))
+	for i := range defaults.Tolerations {
+		t, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&defaults.Tolerations[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid toleration: %w", err)
+		}
+
+		tolerations[i] = t
+	}
+
+	var affinity map[string]any
+	if defaults.Affinity != nil {
+		a, err := runtime.DefaultUnstructuredConverter.ToUnstructured(defaults.Affinity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid affinity: %w", err)
+		}
+
+		affinity = a
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		spec, ok := podSpec(obj)
+		if !ok {
+			return obj, nil
+		}
+
+		mergeNodeSelector(spec, defaults.NodeSelector)
+		mergeTolerations(spec, tolerations)
+		mergeAffinity(spec, affinity)
+
+		return obj, nil
+	}, nil
+}
+
+// mergeNodeSelector adds the node selector entries whose keys the pod spec does not select.
+func mergeNodeSelector(spec map[string]any, nodeSelector map[string]string) {
+	if len(nodeSelector) == 0 {
+		return
+	}
+
+	selector, ok := spec["nodeSelector"].(map[string]any)
+	if !ok {
+		selector = make(map[string]any, len(nodeSelector))
+		spec["nodeSelector"] = selector
+	}
+
+	for k, v := range nodeSelector {
+		if _, exists := selector[k]; !exists {
+			selector[k] = v
+		}
+	}
+}
+
+// mergeTolerations adds the tolerations whose key and effect the pod spec does not tolerate.
+func mergeTolerations(spec map[string]any, tolerations []map[string]any) {
+	if len(tolerations) == 0 {
+		return
+	}
+
+	existing, _ := spec["tolerations"].([]any)
+	merged := existing
+
+	for _, t := range tolerations {
+		found := false
+
+		for _, e := range existing {
+			if m, ok := e.(map[string]any); ok && m["key"] == t["key"] && m["effect"] == t["effect"] {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, runtime.DeepCopyJSONValue(t))
+		}
+	}
+
+	spec["tolerations"] = merged
+}
+
+// mergeAffinity sets the node, pod and pod anti-affinity the pod spec does not define.
+func mergeAffinity(spec map[string]any, affinity map[string]any) {
+	if len(affinity) == 0 {
+		return
+	}
+
+	existing, ok := spec["affinity"].(map[string]any)
+	if !ok {
+		existing = make(map[string]any, len(affinity))
+		spec["affinity"] = existing
+	}
+
+	for k, v := range affinity {
+		if _, exists := existing[k]; !exists {
+			existing[k] = runtime.DeepCopyJSONValue(v)
+		}
+	}
+}
+
+// ResourceDefaults are default resource requests and limits for containers whose names match
+// a pattern.
+type ResourceDefaults struct {
+	// Containers is a path.Match pattern of the container names the defaults apply to
+	// (e.g. "istio-*"). Empty matches all containers.
+	Containers string
+
+	// Requests are the default resource requests.
+	Requests corev1.ResourceList
+
+	// Limits are the default resource limits.
+	Limits corev1.ResourceList
+}
+
+// DefaultResources returns a transformer that sets default resource requests and limits on the
+// containers and init containers of workloads that do not set them, e.g. to enforce platform
+// guardrails at render time. For each container, the first entry whose pattern matches the
+// container name is used, so specific patterns go before catch-all ones.
+//
+// Defaults are set per resource and never produce an invalid pair: a default request is not set
+// if the container has a limit for the resource (Kubernetes then defaults the request to the
+// limit), and a default limit is not set if it is below the request of the container.
+//
+// Example:
+//
+//	t, err := yaml.DefaultResources(
+//		yaml.ResourceDefaults{
+//			Containers: "istio-*",
+//			Requests:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+//		},
+//		yaml.ResourceDefaults{
+//			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+//			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+//		},
+//	)
+func DefaultResources(defaults ...ResourceDefaults) (types.Transformer, error) {
+	for _, d := range defaults {
+		if _, err := path.Match(d.Containers, ""); err != nil {
+			return nil, fmt.Errorf("invalid container pattern %q: %w", d.Containers, err)
+		}
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		spec, ok := podSpec(obj)
+		if !ok {
+			return obj, nil
+		}
+
+		for _, container := range podContainers(spec) {
+			name, _ := container["name"].(string)
+
+			for _, d := range defaults {
+				if matched, _ := path.Match(d.Containers, name); d.Containers == "" || matched {
+					if err := mergeResources(container, d); err != nil {
+						return unstructured.Unstructured{}, fmt.Errorf("container %s: %w", name, err)
+					}
+
+					break
+				}
+			}
+		}
+
+		return obj, nil
+	}, nil
+}
+
+// podContainers returns the containers and init containers of a pod spec.
+func podContainers(spec map[string]any) []map[string]any {
+	var containers []map[string]any
+
+	for _, field := range []string{"initContainers", "containers"} {
+		items, _ := spec[field].([]any)
+		for _, item := range items {
+			if c, ok := item.(map[string]any); ok {
+				containers = append(containers, c)
+			}
+		}
+	}
+
+	return containers
+}
+
+// mergeResources sets the default requests and limits a container does not set.
+func mergeResources(container map[string]any, defaults ResourceDefaults) error {
+	resources, ok := container["resources"].(map[string]any)
+	if !ok {
+		resources = make(map[string]any, 2)
+	}
+
+	requests, _ := resources["requests"].(map[string]any)
+	limits, _ := resources["limits"].(map[string]any)
+
+	for name, q := range defaults.Requests {
+		if _, exists := requests[string(name)]; exists {
+			continue
+		}
+
+		if _, exists := limits[string(name)]; exists {
+			continue
+		}
+
+		requests = setQuantity(requests, name, q)
+	}
+
+	for name, q := range defaults.Limits {
+		if _, exists := limits[string(name)]; exists {
+			continue
+		}
+
+		if request, exists := requests[string(name)]; exists {
+			r, err := resource.ParseQuantity(fmt.Sprint(request))
+			if err != nil {
+				return fmt.Errorf("invalid %s request: %w", name, err)
+			}
+
+			if r.Cmp(q) > 0 {
+				continue
+			}
+		}
+
+		limits = setQuantity(limits, name, q)
+	}
+
+	if requests != nil {
+		resources["requests"] = requests
+	}
+
+	if limits != nil {
+		resources["limits"] = limits
+	}
+
+	if len(resources) > 0 {
+		container["resources"] = resources
+	}
+
+	return nil
+}
+
+// setQuantity sets a resource quantity in a resource list, creating the list if needed.
+func setQuantity(list map[string]any, name corev1.ResourceName, q resource.Quantity) map[string]any {
+	if list == nil {
+		list = make(map[string]any, 1)
+	}
+
+	list[string(name)] = q.String()
+
+	return list
+}
+
+// ContainerPosition is where InjectContainer adds a container in a pod template.
+type ContainerPosition int
+
+const (
+	// AppendContainer adds the container after the existing containers, e.g. a sidecar.
+	AppendContainer ContainerPosition = iota
+
+	// PrependContainer adds the container before the existing containers.
+	PrependContainer
+
+	// AppendInitContainer adds the container after the existing init containers.
+	AppendInitContainer
+
+	// PrependInitContainer adds the container before the existing init containers, so it runs
+	// first, e.g. to set up networking for the other containers.
+	PrependInitContainer
+)
+
+// InjectContainer returns a transformer that adds a container, provided as an unstructured
+// fragment, to the pod templates of workloads, e.g. log shippers or proxies. Pod templates that
+// already have a container or init container with the same name are left unchanged, so the
+// transformer is idempotent. Combine it with TransformIf to inject into matching workloads only.
+//
+// Example:
+//
+//	t, err := yaml.InjectContainer(map[string]any{
+//		"name":  "log-shipper",
+//		"image": "fluent/fluent-bit:3.0",
+//	}, yaml.AppendContainer)
+func InjectContainer(container map[string]any, position ContainerPosition) (types.Transformer, error) {
+	name, ok := container["name"].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidContainer)
+	}
+
+	field := "containers"
+	if position == AppendInitContainer || position == PrependInitContainer {
+		field = "initContainers"
+	}
+
+	prepend := position == PrependContainer || position == PrependInitContainer
+
+	container, err := normalizeFragment(container)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidContainer, name, err)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		spec, ok := podSpec(obj)
+		if !ok {
+			return obj, nil
+		}
+
+		for _, c := range podContainers(spec) {
+			if c["name"] == name {
+				return obj, nil
+			}
+		}
+
+		existing, _ := spec[field].([]any)
+		injected := runtime.DeepCopyJSON(container)
+
+		if prepend {
+			spec[field] = append([]any{injected}, existing...)
+		} else {
+			spec[field] = append(existing, injected)
+		}
+
+		return obj, nil
+	}, nil
+}
+
+// normalizeFragment converts an unstructured fragment built from Go literals (e.g. int ports or
+// typed slices) to the JSON-compatible types of unstructured objects, so it can be deep copied.
+func normalizeFragment(fragment map[string]any) (map[string]any, error) {
+	data, err := json.Marshal(fragment)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized map[string]any
+	if err := utiljson.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// EnvInjection are environment variables added to containers by InjectEnv.
+type EnvInjection struct {
+	// Env are the variables to add. A container variable with the same name is kept unless
+	// Override is set.
+	Env []corev1.EnvVar
+
+	// EnvFrom are the sources to add, unless a container already references the same source.
+	EnvFrom []corev1.EnvFromSource
+
+	// Override replaces container variables with the same name as an injected variable.
+	Override bool
+}
+
+// InjectEnv returns a transformer that adds environment variables and envFrom sources to all
+// containers and init containers of workloads, e.g. proxy settings or telemetry endpoints.
+// Combine it with TransformIf to inject into matching workloads only.
+//
+// Example:
+//
+//	t, err := yaml.InjectEnv(yaml.EnvInjection{
+//		Env: []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy.corp:3128"}},
+//	})
+func InjectEnv(injection EnvInjection) (types.Transformer, error) {
+	env := make([]map[string]any, len(injection.Env))
+	for i := range injection.Env {
+		e, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&injection.Env[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid env var %s: %w", injection.Env[i].Name, err)
+		}
+
+		env[i] = e
+	}
+
+	envFrom := make([]map[string]any, len(injection.EnvFrom))
+	for i := range injection.EnvFrom {
+		e, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&injection.EnvFrom[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid envFrom source: %w", err)
+		}
+
+		envFrom[i] = e
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		spec, ok := podSpec(obj)
+		if !ok {
+			return obj, nil
+		}
+
+		for _, container := range podContainers(spec) {
+			mergeEnv(container, env, injection.Override)
+			mergeEnvFrom(container, envFrom)
+		}
+
+		return obj, nil
+	}, nil
+}
+
+// mergeEnv adds environment variables to a container, replacing variables with the same name
+// only with override.
+func mergeEnv(container map[string]any, env []map[string]any, override bool) {
+	if len(env) == 0 {
+		return
+	}
+
+	existing, _ := container["env"].([]any)
+
+	for _, e := range env {
+		i := slices.IndexFunc(existing, func(v any) bool {
+			m, ok := v.(map[string]any)
+
+			return ok && m["name"] == e["name"]
+		})
+
+		switch {
+		case i < 0:
+			existing = append(existing, runtime.DeepCopyJSON(e))
+		case override:
+			existing[i] = runtime.DeepCopyJSON(e)
+		}
+	}
+
+	container["env"] = existing
+}
+
+// mergeEnvFrom adds the envFrom sources a container does not reference yet.
+func mergeEnvFrom(container map[string]any, envFrom []map[string]any) {
+	if len(envFrom) == 0 {
+		return
+	}
+
+	existing, _ := container["envFrom"].([]any)
+
+	for _, e := range envFrom {
+		if !slices.ContainsFunc(existing, func(v any) bool { return reflect.DeepEqual(v, e) }) {
+			existing = append(existing, runtime.DeepCopyJSON(e))
+		}
+	}
+
+	container["envFrom"] = existing
+}
@@ -0,0 +1,383 @@
+package yaml_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const workloadsYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      nodeSelector:
+        kubernetes.io/os: windows
+      tolerations:
+      - key: dedicated
+        operator: Equal
+        value: web
+        effect: NoSchedule
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          affinity:
+            nodeAffinity:
+              requiredDuringSchedulingIgnoredDuringExecution:
+                nodeSelectorTerms:
+                - matchExpressions:
+                  - key: disk
+                    operator: In
+                    values: [ssd]
+          containers:
+          - name: backup
+            image: backup
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+// renderWorkloads renders workloadsYAML with the given options, keyed by object name.
+func renderWorkloads(t *testing.T, opts ...yaml.RendererOption) map[string]unstructured.Unstructured {
+	t.Helper()
+	g := NewWithT(t)
+
+	renderer, err := yaml.NewFromStrings(map[string]string{"workloads.yaml": workloadsYAML}, opts...)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	result := make(map[string]unstructured.Unstructured, len(objects))
+	for _, obj := range objects {
+		result[obj.GetName()] = obj
+	}
+
+	return result
+}
+
+func TestDefaultScheduling(t *testing.T) {
+	t.Run("should merge scheduling defaults into pod templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer, err := yaml.DefaultScheduling(yaml.SchedulingDefaults{
+			NodeSelector: map[string]string{
+				"kubernetes.io/os":   "linux",
+				"kubernetes.io/arch": "amd64",
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "apps", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+			},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+						Weight: 10,
+						Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "zone",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"a"},
+						}}},
+					}},
+				},
+				PodAntiAffinity: &corev1.PodAntiAffinity{},
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformer(transformer))
+
+		web := objects["web"].Object
+		nodeSelector, _, _ := unstructured.NestedStringMap(web, "spec", "template", "spec", "nodeSelector")
+		g.Expect(nodeSelector).To(Equal(map[string]string{
+			"kubernetes.io/os":   "windows",
+			"kubernetes.io/arch": "amd64",
+		}))
+
+		tolerations, _, _ := unstructured.NestedSlice(web, "spec", "template", "spec", "tolerations")
+		g.Expect(tolerations).To(ConsistOf(
+			HaveKeyWithValue("value", "web"),
+			HaveKeyWithValue("key", "spot"),
+		))
+
+		backup := objects["backup"].Object
+		affinity, _, _ := unstructured.NestedMap(backup, "spec", "jobTemplate", "spec", "template", "spec", "affinity")
+		g.Expect(affinity).To(HaveKey("podAntiAffinity"))
+		g.Expect(affinity["nodeAffinity"]).To(HaveKey("requiredDuringSchedulingIgnoredDuringExecution"))
+		g.Expect(affinity["nodeAffinity"]).ToNot(HaveKey("preferredDuringSchedulingIgnoredDuringExecution"))
+
+		g.Expect(objects["config"].Object).ToNot(HaveKey("spec"))
+	})
+
+	t.Run("should add tolerations to pod templates without tolerations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer, err := yaml.DefaultScheduling(yaml.SchedulingDefaults{
+			Tolerations: []corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpExists}},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformer(transformer))
+
+		web, _, _ := unstructured.NestedSlice(objects["web"].Object, "spec", "template", "spec", "tolerations")
+		backup, _, _ := unstructured.NestedSlice(objects["backup"].Object, "spec", "jobTemplate", "spec", "template", "spec", "tolerations")
+		g.Expect(web).To(HaveLen(2))
+		g.Expect(backup).To(ConsistOf(HaveKeyWithValue("key", "spot")))
+	})
+}
+
+const resourcesYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: web
+        image: nginx
+        resources:
+          requests:
+            memory: 1Gi
+          limits:
+            cpu: 2
+      - name: istio-proxy
+        image: proxy
+`
+
+func TestDefaultResources(t *testing.T) {
+	render := func(t *testing.T, defaults ...yaml.ResourceDefaults) map[string]any {
+		t.Helper()
+		g := NewWithT(t)
+
+		transformer, err := yaml.DefaultResources(defaults...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err := yaml.NewFromStrings(
+			map[string]string{"web.yaml": resourcesYAML},
+			yaml.WithTransformer(transformer),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		spec, _, _ := unstructured.NestedMap(objects[0].Object, "spec", "template", "spec")
+		resources := make(map[string]any)
+
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _ := spec[field].([]any)
+			for _, c := range containers {
+				container, _ := c.(map[string]any)
+				resources[container["name"].(string)] = container["resources"]
+			}
+		}
+
+		return resources
+	}
+
+	t.Run("should apply the first matching defaults per container", func(t *testing.T) {
+		g := NewWithT(t)
+
+		resources := render(t,
+			yaml.ResourceDefaults{
+				Containers: "istio-*",
+				Requests:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+			},
+			yaml.ResourceDefaults{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		)
+
+		g.Expect(resources["istio-proxy"]).To(Equal(map[string]any{
+			"requests": map[string]any{"cpu": "10m"},
+		}))
+		g.Expect(resources["init"]).To(Equal(map[string]any{
+			"requests": map[string]any{"cpu": "100m", "memory": "128Mi"},
+			"limits":   map[string]any{"memory": "512Mi"},
+		}))
+
+		// The cpu request defaults to the cpu limit, and the memory limit would be below the request
+		g.Expect(resources["web"]).To(Equal(map[string]any{
+			"requests": map[string]any{"memory": "1Gi"},
+			"limits":   map[string]any{"cpu": int64(2)},
+		}))
+	})
+
+	t.Run("should reject invalid container patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.DefaultResources(yaml.ResourceDefaults{Containers: "["})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid container pattern")))
+	})
+}
+
+func TestInjectContainer(t *testing.T) {
+	containerNames := func(obj unstructured.Unstructured, fields ...string) []string {
+		items, _, _ := unstructured.NestedSlice(obj.Object, fields...)
+
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i], _ = item.(map[string]any)["name"].(string)
+		}
+
+		return names
+	}
+
+	t.Run("should append sidecars and prepend init containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sidecar, err := yaml.InjectContainer(map[string]any{
+			"name":  "log-shipper",
+			"image": "fluent-bit",
+		}, yaml.AppendContainer)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		initContainer, err := yaml.InjectContainer(map[string]any{
+			"name":  "network-setup",
+			"image": "proxy-init",
+		}, yaml.PrependInitContainer)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformers(sidecar, initContainer))
+
+		g.Expect(containerNames(objects["web"], "spec", "template", "spec", "containers")).To(
+			Equal([]string{"web", "log-shipper"}),
+		)
+		g.Expect(containerNames(objects["web"], "spec", "template", "spec", "initContainers")).To(
+			Equal([]string{"network-setup"}),
+		)
+		g.Expect(containerNames(objects["backup"], "spec", "jobTemplate", "spec", "template", "spec", "containers")).To(
+			Equal([]string{"backup", "log-shipper"}),
+		)
+		g.Expect(objects["config"].Object).ToNot(HaveKey("spec"))
+	})
+
+	t.Run("should not inject containers twice", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sidecar, err := yaml.InjectContainer(map[string]any{"name": "web", "image": "other"}, yaml.PrependContainer)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformers(sidecar))
+
+		g.Expect(containerNames(objects["web"], "spec", "template", "spec", "containers")).To(Equal([]string{"web"}))
+		g.Expect(containerNames(objects["backup"], "spec", "jobTemplate", "spec", "template", "spec", "containers")).To(
+			Equal([]string{"web", "backup"}),
+		)
+	})
+
+	t.Run("should inject containers built from Go literals", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sidecar, err := yaml.InjectContainer(map[string]any{
+			"name":  "metrics",
+			"image": "exporter",
+			"ports": []map[string]any{{"containerPort": 9090}},
+			"args":  []string{"--port", "9090"},
+		}, yaml.AppendContainer)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformers(sidecar))
+
+		containers, _, _ := unstructured.NestedSlice(objects["web"].Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).To(HaveLen(2))
+		g.Expect(containers[1]).To(Equal(map[string]any{
+			"name":  "metrics",
+			"image": "exporter",
+			"ports": []any{map[string]any{"containerPort": int64(9090)}},
+			"args":  []any{"--port", "9090"},
+		}))
+	})
+
+	t.Run("should reject containers that cannot be converted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.InjectContainer(map[string]any{"name": "web", "image": make(chan int)}, yaml.AppendContainer)
+		g.Expect(err).To(MatchError(yaml.ErrInvalidContainer))
+	})
+
+	t.Run("should reject containers without a name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.InjectContainer(map[string]any{"image": "nginx"}, yaml.AppendContainer)
+		g.Expect(err).To(MatchError(yaml.ErrInvalidContainer))
+	})
+}
+
+func TestInjectEnv(t *testing.T) {
+	t.Run("should add env vars and sources to all containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transformer, err := yaml.InjectEnv(yaml.EnvInjection{
+			Env: []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+			EnvFrom: []corev1.EnvFromSource{{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "telemetry"}},
+			}},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformers(transformer, transformer))
+
+		containers, _, _ := unstructured.NestedSlice(objects["web"].Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).To(ConsistOf(And(
+			HaveKeyWithValue("env", []any{map[string]any{"name": "HTTPS_PROXY", "value": "http://proxy:3128"}}),
+			HaveKeyWithValue("envFrom", []any{map[string]any{"configMapRef": map[string]any{"name": "telemetry"}}}),
+		)))
+	})
+
+	t.Run("should only override existing env vars when requested", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing, err := yaml.InjectEnv(yaml.EnvInjection{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		keep, err := yaml.InjectEnv(yaml.EnvInjection{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		override, err := yaml.InjectEnv(yaml.EnvInjection{
+			Env:      []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "warn"}},
+			Override: true,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects := renderWorkloads(t, yaml.WithTransformers(existing, keep))
+		containers, _, _ := unstructured.NestedSlice(objects["web"].Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).To(ConsistOf(HaveKeyWithValue("env", []any{map[string]any{"name": "LOG_LEVEL", "value": "info"}})))
+
+		objects = renderWorkloads(t, yaml.WithTransformers(existing, override))
+		containers, _, _ = unstructured.NestedSlice(objects["web"].Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).To(ConsistOf(HaveKeyWithValue("env", []any{map[string]any{"name": "LOG_LEVEL", "value": "warn"}})))
+	})
+}
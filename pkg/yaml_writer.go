@@ -0,0 +1,231 @@
+package yaml
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/pkg/util"
+	goyaml "gopkg.in/yaml.v3"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const defaultWriteIndent = 2
+
+// WriteOption is a generic option for WriteOptions.
+type WriteOption = util.Option[WriteOptions]
+
+// WriteOptions is a struct-based option that can set multiple writer options at once.
+type WriteOptions struct {
+	// Indent is the number of spaces used for indentation. 0 = default (2).
+	Indent int
+
+	// Sort orders objects by apiVersion, kind, namespace and name before writing.
+	// When false, objects are written in the order given.
+	Sort bool
+
+	// Format selects the output encoding. Default: FormatYAML.
+	Format Format
+
+	// Originals enables format preservation: objects unchanged since decoding are written
+	// from their original YAML nodes, keeping comments and mapping order. nil = disabled.
+	Originals *Originals
+
+	// HelmHooks makes sorting honor Helm hook annotations: pre-* hooks first, then regular
+	// objects, then post-* and test hooks, each ordered by hook weight. Only applies with Sort.
+	HelmHooks bool
+
+	// StripSourceAnnotations removes the source annotations added by WithSourceAnnotations from
+	// written objects.
+	StripSourceAnnotations bool
+}
+
+// ApplyTo applies the writer options to the target configuration.
+func (opts WriteOptions) ApplyTo(target *WriteOptions) {
+	if opts.Indent > 0 {
+		target.Indent = opts.Indent
+	}
+
+	target.Sort = opts.Sort
+	target.Format = opts.Format
+	target.HelmHooks = opts.HelmHooks
+	target.StripSourceAnnotations = opts.StripSourceAnnotations
+
+	if opts.Originals != nil {
+		target.Originals = opts.Originals
+	}
+}
+
+// WithIndent sets the number of spaces used for indentation in written YAML.
+func WithIndent(indent int) WriteOption {
+	return util.FunctionalOption[WriteOptions](func(opts *WriteOptions) {
+		opts.Indent = indent
+	})
+}
+
+// WithFormat sets the output encoding used by Write.
+func WithFormat(format Format) WriteOption {
+	return util.FunctionalOption[WriteOptions](func(opts *WriteOptions) {
+		opts.Format = format
+	})
+}
+
+// WithPreservedFormatting writes objects that are unchanged since decoding from their original
+// YAML nodes, preserving comments and mapping order. The same Originals must be passed to the
+// renderer via WithOriginals. Objects modified by filters, transformers or source annotations
+// are written normally. Only applies to FormatYAML.
+func WithPreservedFormatting(originals *Originals) WriteOption {
+	return util.FunctionalOption[WriteOptions](func(opts *WriteOptions) {
+		opts.Originals = originals
+	})
+}
+
+// WithSortedOutput enables ordering of written objects by apiVersion, kind, namespace and name.
+func WithSortedOutput(enabled bool) WriteOption {
+	return util.FunctionalOption[WriteOptions](func(opts *WriteOptions) {
+		opts.Sort = enabled
+	})
+}
+
+// WithHelmHookOrdering makes sorted output honor the helm.sh/hook and helm.sh/hook-weight
+// annotations of static YAML exported from charts, so hooks keep the position Helm would run them
+// in: pre-* hooks before regular objects, post-* hooks after them and test hooks last, each phase
+// ordered by ascending hook weight. Only applies together with WithSortedOutput.
+func WithHelmHookOrdering(enabled bool) WriteOption {
+	return util.FunctionalOption[WriteOptions](func(opts *WriteOptions) {
+		opts.HelmHooks = enabled
+	})
+}
+
+// WithStrippedSourceAnnotations removes the source annotations added by WithSourceAnnotations
+// from written objects, so hydrated manifests do not carry renderer bookkeeping. Objects are
+// copied before their annotations are changed. Objects whose only change since decoding is the
+// source annotations are again eligible for WithPreservedFormatting.
+func WithStrippedSourceAnnotations(enabled bool) WriteOption {
+	return util.FunctionalOption[WriteOptions](func(opts *WriteOptions) {
+		opts.StripSourceAnnotations = enabled
+	})
+}
+
+// Write marshals objects into a multi-document YAML stream separated by "---".
+// Mapping keys are emitted in sorted order, so the output is deterministic for a given input.
+// With FormatCanonicalJSON, one canonical JSON document is written per line instead.
+//
+// Example:
+//
+//	objects, _ := e.Render(ctx)
+//	err := yaml.Write(objects, os.Stdout, yaml.WriteOptions{Sort: true})
+func Write(objects []unstructured.Unstructured, w io.Writer, opts ...WriteOption) error {
+	writeOpts := WriteOptions{
+		Indent: defaultWriteIndent,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&writeOpts)
+	}
+
+	if writeOpts.StripSourceAnnotations {
+		objects = stripSourceAnnotations(objects)
+	}
+
+	if writeOpts.Sort {
+		objects = slices.Clone(objects)
+		if writeOpts.HelmHooks {
+			slices.SortStableFunc(objects, compareHelmHooks)
+		} else {
+			slices.SortStableFunc(objects, compareObjects)
+		}
+	}
+
+	if writeOpts.Format == FormatCanonicalJSON {
+		return writeCanonicalJSON(objects, w)
+	}
+
+	enc := goyaml.NewEncoder(w)
+	enc.SetIndent(writeOpts.Indent)
+
+	for i := range objects {
+		var doc any = objects[i].Object
+		if node, ok := writeOpts.Originals.lookup(objects[i]); ok {
+			doc = node
+		}
+
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf(
+				"failed to encode %s %s: %w",
+				objects[i].GroupVersionKind(),
+				objects[i].GetName(),
+				err,
+			)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to flush YAML stream: %w", err)
+	}
+
+	return nil
+}
+
+// stripSourceAnnotations returns objects without source annotations, copying the objects that
+// carry any.
+func stripSourceAnnotations(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	result := slices.Clone(objects)
+
+	for i := range result {
+		annotations := result[i].GetAnnotations()
+		count := len(annotations)
+
+		delete(annotations, types.AnnotationSourceType)
+		delete(annotations, types.AnnotationSourceFile)
+		delete(annotations, AnnotationSourceLabel)
+
+		if len(annotations) == count {
+			continue
+		}
+
+		result[i] = *result[i].DeepCopy()
+		if len(annotations) == 0 {
+			unstructured.RemoveNestedField(result[i].Object, "metadata", "annotations")
+		} else {
+			result[i].SetAnnotations(annotations)
+		}
+	}
+
+	return result
+}
+
+// compareObjects orders objects by apiVersion, kind, namespace and name.
+func compareObjects(a unstructured.Unstructured, b unstructured.Unstructured) int {
+	return cmp.Or(
+		cmp.Compare(a.GetAPIVersion(), b.GetAPIVersion()),
+		cmp.Compare(a.GetKind(), b.GetKind()),
+		cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+		cmp.Compare(a.GetName(), b.GetName()),
+	)
+}
+
+// writeCanonicalJSON writes one canonical JSON document per line.
+func writeCanonicalJSON(objects []unstructured.Unstructured, w io.Writer) error {
+	for i := range objects {
+		data, err := CanonicalJSON(objects[i])
+		if err != nil {
+			return fmt.Errorf(
+				"failed to encode %s %s: %w",
+				objects[i].GroupVersionKind(),
+				objects[i].GetName(),
+				err,
+			)
+		}
+
+		data = append(data, '\n')
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write JSON stream: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// maxSafeInteger is the largest integer exactly representable as a float64 (2^53).
+	maxSafeInteger = 1 << 53
+
+	// Float formatting switches to exponent notation outside [1e-6, 1e21), as in RFC 8785.
+	minPlainFloat = 1e-6
+	maxPlainFloat = 1e21
+)
+
+// ErrUnsupportedValue is returned when an object contains a value that has no canonical JSON form.
+var ErrUnsupportedValue = errors.New("unsupported value for canonical JSON")
+
+// Format selects the encoding used by Write.
+type Format int
+
+const (
+	// FormatYAML writes a multi-document YAML stream separated by "---".
+	FormatYAML Format = iota
+
+	// FormatCanonicalJSON writes one canonical JSON document per line (sorted keys,
+	// normalized numbers, no insignificant whitespace), suitable for signing and hashing.
+	FormatCanonicalJSON
+)
+
+// CanonicalJSON encodes an object as canonical JSON: object keys are sorted, no insignificant
+// whitespace is emitted, HTML characters are not escaped, and numbers are normalized so that
+// integral values are written without a fractional part or exponent.
+func CanonicalJSON(obj unstructured.Unstructured) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, obj.Object); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(buf, v)
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(v, 10))
+	case float32:
+		return writeCanonicalFloat(buf, float64(v))
+	case float64:
+		return writeCanonicalFloat(buf, v)
+	case map[string]any:
+		return writeCanonicalMap(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedValue, value)
+	}
+
+	return nil
+}
+
+func writeCanonicalMap(buf *bytes.Buffer, m map[string]any) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		writeCanonicalString(buf, k)
+		buf.WriteByte(':')
+
+		if err := writeCanonical(buf, m[k]); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	// Encoding a string cannot fail; the encoder appends a newline that is trimmed below.
+	_ = enc.Encode(s)
+
+	buf.Truncate(buf.Len() - 1)
+}
+
+func writeCanonicalFloat(buf *bytes.Buffer, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedValue, f)
+	}
+
+	if f == math.Trunc(f) && math.Abs(f) < maxSafeInteger {
+		buf.WriteString(strconv.FormatInt(int64(f), 10))
+
+		return nil
+	}
+
+	abs := math.Abs(f)
+	if abs >= minPlainFloat && abs < maxPlainFloat {
+		buf.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+
+		return nil
+	}
+
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	// Normalize exponent form (e.g. 1e+21 and 1e-07 become 1e+21 and 1e-7).
+	mantissa, exp, _ := strings.Cut(s, "e")
+	sign := exp[0]
+	exp = strings.TrimLeft(exp[1:], "0")
+
+	buf.WriteString(mantissa)
+	buf.WriteByte('e')
+	buf.WriteByte(sign)
+	buf.WriteString(exp)
+
+	return nil
+}
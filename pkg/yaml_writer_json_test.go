@@ -0,0 +1,65 @@
+package yaml_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+
+	t.Run("should sort keys and normalize numbers", func(t *testing.T) {
+		g := NewWithT(t)
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"kind":       "ConfigMap",
+			"apiVersion": "v1",
+			"metadata":   map[string]any{"name": "<test>"},
+			"data": map[string]any{
+				"b":     float64(3),
+				"a":     int64(1),
+				"f":     1.5,
+				"small": 1e-7,
+				"large": 1e21,
+				"list":  []any{true, nil, "x"},
+			},
+		}}
+
+		data, err := yaml.CanonicalJSON(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(Equal(
+			`{"apiVersion":"v1","data":{"a":1,"b":3,"f":1.5,"large":1e+21,"list":[true,null,"x"],"small":1e-7},` +
+				`"kind":"ConfigMap","metadata":{"name":"<test>"}}`,
+		))
+	})
+
+	t.Run("should reject non-finite numbers", func(t *testing.T) {
+		g := NewWithT(t)
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"value": math.Inf(1),
+		}}
+
+		_, err := yaml.CanonicalJSON(obj)
+		g.Expect(err).To(MatchError(yaml.ErrUnsupportedValue))
+	})
+
+	t.Run("should write one document per line", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{
+			{Object: map[string]any{"kind": "Pod", "apiVersion": "v1"}},
+			{Object: map[string]any{"kind": "Service", "apiVersion": "v1"}},
+		}
+
+		var buf bytes.Buffer
+		err := yaml.Write(objects, &buf, yaml.WithFormat(yaml.FormatCanonicalJSON))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(buf.String()).To(Equal(
+			"{\"apiVersion\":\"v1\",\"kind\":\"Pod\"}\n{\"apiVersion\":\"v1\",\"kind\":\"Service\"}\n",
+		))
+	})
+}
@@ -0,0 +1,147 @@
+package yaml_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWrite(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml":       &fstest.MapFile{Data: []byte(podYAML)},
+		"configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"multi.yaml":     &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should write a multi-document stream", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		err = yaml.Write(objects, &buf)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(strings.Count(buf.String(), "---\n")).To(Equal(len(objects) - 1))
+
+		// Round trip through the renderer
+		roundTrip, err := yaml.New([]yaml.Source{{
+			FS:   fstest.MapFS{"out.yaml": &fstest.MapFile{Data: buf.Bytes()}},
+			Path: "out.yaml",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := roundTrip.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(objects))
+	})
+
+	t.Run("should produce deterministic sorted output", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		reversed := slices.Clone(objects)
+		slices.Reverse(reversed)
+
+		var buf1, buf2 bytes.Buffer
+		g.Expect(yaml.Write(objects, &buf1, yaml.WriteOptions{Sort: true})).To(Succeed())
+		g.Expect(yaml.Write(reversed, &buf2, yaml.WithSortedOutput(true))).To(Succeed())
+		g.Expect(buf1.String()).To(Equal(buf2.String()))
+		g.Expect(buf1.String()).To(HavePrefix("apiVersion: v1\ndata:\n"))
+	})
+
+	t.Run("should honor indentation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "pod.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		g.Expect(yaml.Write(objects, &buf, yaml.WithIndent(4))).To(Succeed())
+		g.Expect(buf.String()).To(ContainSubstring("\n    name: test-pod\n"))
+	})
+
+	t.Run("should order Helm hooks by phase and weight", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.NewFromStrings(map[string]string{"chart.yaml": helmChartYAML})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		g.Expect(yaml.Write(objects, &buf, yaml.WithSortedOutput(true), yaml.WithHelmHookOrdering(true))).To(Succeed())
+
+		written, err := yaml.NewFromStrings(map[string]string{"out.yaml": buf.String()})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := written.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		names := make([]string, len(result))
+		for i := range result {
+			names[i] = result[i].GetName()
+		}
+		g.Expect(names).To(Equal([]string{"migrate", "create-db", "app", "app-config", "notify", "smoke-test"}))
+	})
+}
+
+const helmChartYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: smoke-test
+  annotations:
+    helm.sh/hook: test
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: notify
+  annotations:
+    helm.sh/hook: post-install,post-upgrade
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: pre-upgrade,post-install
+    helm.sh/hook-weight: "-5"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: create-db
+  annotations:
+    helm.sh/hook: pre-install
+    helm.sh/hook-weight: "1"
+`
@@ -0,0 +1,154 @@
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	outputDirMode  = 0o755
+	outputFileMode = 0o644
+)
+
+var (
+	// ErrInvalidOutputPath is returned when a layout produces a path that is not a valid relative path.
+	ErrInvalidOutputPath = errors.New("invalid output path")
+
+	// ErrMissingSourceFile is returned when an object lacks the source file annotation required by a layout.
+	ErrMissingSourceFile = errors.New("object has no source file annotation")
+)
+
+// FileWriter is a minimal writable filesystem used by WriteTree.
+// Names are slash-separated paths relative to the writer's root, as with fs.FS.
+type FileWriter interface {
+	WriteFile(name string, data []byte) error
+}
+
+// DirWriter is a FileWriter that writes files below a local directory,
+// creating parent directories as needed.
+type DirWriter string
+
+// WriteFile writes data to name below the directory, creating parent directories as needed.
+func (d DirWriter) WriteFile(name string, data []byte) error {
+	target := filepath.Join(string(d), filepath.FromSlash(name))
+
+	//nolint:gosec // hydrated manifests are meant to be readable by other tools
+	if err := os.MkdirAll(filepath.Dir(target), outputDirMode); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", name, err)
+	}
+
+	//nolint:gosec // hydrated manifests are meant to be readable by other tools
+	if err := os.WriteFile(target, data, outputFileMode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Layout maps an object to the relative, slash-separated path of the file it is written to.
+// Objects mapped to the same path are written to that file as a multi-document stream.
+type Layout func(obj unstructured.Unstructured) (string, error)
+
+// LayoutByNamespace returns a layout writing each object to <namespace>/<kind>_<name>.yaml,
+// with the kind lowercased. Objects without a namespace are written at the root.
+func LayoutByNamespace() Layout {
+	return func(obj unstructured.Unstructured) (string, error) {
+		name := strings.ToLower(obj.GetKind()) + "_" + obj.GetName() + ".yaml"
+
+		return path.Join(obj.GetNamespace(), name), nil
+	}
+}
+
+// LayoutBySource returns a layout mirroring the input layout, writing each object to the file
+// recorded in its source file annotation. Requires WithSourceAnnotations(true) on the renderer.
+func LayoutBySource() Layout {
+	return func(obj unstructured.Unstructured) (string, error) {
+		file := sourceFile(obj)
+		if file == "" {
+			return "", fmt.Errorf("%w: %s %s", ErrMissingSourceFile, obj.GetKind(), obj.GetName())
+		}
+
+		return file, nil
+	}
+}
+
+// WriteTree writes objects into a directory tree using the given layout, one file per distinct path.
+// Objects sharing a path are written as a multi-document stream in the order given.
+// Write options are applied to each file.
+//
+// Example:
+//
+//	err := yaml.WriteTree(objects, yaml.DirWriter("/path/to/repo"), yaml.LayoutByNamespace())
+func WriteTree(
+	objects []unstructured.Unstructured,
+	w FileWriter,
+	layout Layout,
+	opts ...WriteOption,
+) error {
+	paths := make([]string, 0)
+	groups := make(map[string][]unstructured.Unstructured)
+
+	for _, obj := range objects {
+		p, err := layout(obj)
+		if err != nil {
+			return fmt.Errorf("failed to compute output path: %w", err)
+		}
+
+		if !fs.ValidPath(p) || p == "." {
+			return fmt.Errorf("%w: %q for %s %s", ErrInvalidOutputPath, p, obj.GetKind(), obj.GetName())
+		}
+
+		if _, ok := groups[p]; !ok {
+			paths = append(paths, p)
+		}
+
+		groups[p] = append(groups[p], obj)
+	}
+
+	for _, p := range paths {
+		var buf bytes.Buffer
+		if err := Write(groups[p], &buf, opts...); err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", p, err)
+		}
+
+		if err := w.WriteFile(p, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p, err)
+		}
+	}
+
+	return nil
+}
+
+// WriteBack renders all configured inputs and writes the objects into w mirroring the input
+// layout: each object goes to the file it was decoded from (relative to WithSourcePrefix), and
+// objects from the same file are written as a multi-document stream in their original order.
+// Source annotations are stripped from the output. Files left without objects by filters are not
+// written. Requires WithSourceAnnotations(true) on the renderer.
+//
+// Example:
+//
+//	err := r.WriteBack(ctx, yaml.DirWriter("/path/to/hydrated"))
+func (r *Renderer) WriteBack(ctx context.Context, w FileWriter, opts ...WriteOption) error {
+	if !r.opts.SourceAnnotations {
+		return fmt.Errorf("%w: write-back requires WithSourceAnnotations(true)", ErrMissingSourceFile)
+	}
+
+	objects, err := r.Process(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	opts = append(slices.Clip(opts), WithStrippedSourceAnnotations(true))
+
+	return WriteTree(objects, w, LayoutBySource(), opts...)
+}
@@ -0,0 +1,146 @@
+package yaml_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/engine/pkg/transformer/meta/labels"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+
+	. "github.com/onsi/gomega"
+)
+
+const namespacedDeploymentYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+`
+
+func TestWriteTree(t *testing.T) {
+	testFS := fstest.MapFS{
+		"apps/deployment.yaml": &fstest.MapFile{Data: []byte(namespacedDeploymentYAML)},
+		"apps/multi.yaml":      &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should write one file per object by namespace", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := t.TempDir()
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "apps/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = yaml.WriteTree(objects, yaml.DirWriter(dir), yaml.LayoutByNamespace())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(filepath.Join(dir, "prod", "deployment_web.yaml")).To(BeARegularFile())
+		g.Expect(filepath.Join(dir, "service_test-service.yaml")).To(BeARegularFile())
+		g.Expect(filepath.Join(dir, "secret_test-secret.yaml")).To(BeARegularFile())
+	})
+
+	t.Run("should mirror the input layout", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := t.TempDir()
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "apps/*.yaml"}},
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = yaml.WriteTree(objects, yaml.DirWriter(dir), yaml.LayoutBySource())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(filepath.Join(dir, "apps", "multi.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(And(
+			ContainSubstring("name: test-service"),
+			ContainSubstring("---\n"),
+			ContainSubstring("name: test-secret"),
+		))
+		g.Expect(filepath.Join(dir, "apps", "deployment.yaml")).To(BeARegularFile())
+	})
+
+	t.Run("should fail to mirror objects without source annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "apps/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = yaml.WriteTree(objects, yaml.DirWriter(t.TempDir()), yaml.LayoutBySource())
+		g.Expect(err).To(MatchError(yaml.ErrMissingSourceFile))
+	})
+
+	t.Run("should reject paths escaping the output root", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "apps/deployment.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		escape := func(_ unstructured.Unstructured) (string, error) { return "../escape.yaml", nil }
+
+		err = yaml.WriteTree(objects, yaml.DirWriter(t.TempDir()), escape)
+		g.Expect(err).To(MatchError(yaml.ErrInvalidOutputPath))
+	})
+}
+
+func TestWriteBack(t *testing.T) {
+	testFS := fstest.MapFS{
+		"apps/deployment.yaml": &fstest.MapFile{Data: []byte(namespacedDeploymentYAML)},
+		"apps/multi.yaml":      &fstest.MapFile{Data: []byte(multiDocYAML)},
+	}
+
+	t.Run("should write transformed objects mirroring the input layout", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := t.TempDir()
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "apps/*.yaml"}},
+			yaml.WithSourceAnnotations(true),
+			yaml.WithTransformer(labels.Set(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(renderer.WriteBack(t.Context(), yaml.DirWriter(dir))).To(Succeed())
+
+		data, err := os.ReadFile(filepath.Join(dir, "apps", "multi.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(And(
+			MatchRegexp(`(?s)name: test-service.*---\n.*name: test-secret`),
+			ContainSubstring("env: prod"),
+			Not(ContainSubstring("manifests.k8s-manifests-lib")),
+		))
+
+		data, err = os.ReadFile(filepath.Join(dir, "apps", "deployment.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).ToNot(ContainSubstring("annotations"))
+	})
+
+	t.Run("should require source annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "apps/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = renderer.WriteBack(t.Context(), yaml.DirWriter(t.TempDir()))
+		g.Expect(err).To(MatchError(yaml.ErrMissingSourceFile))
+	})
+}
@@ -0,0 +1,140 @@
+// Package yamlfake provides a fake YAML renderer for unit tests of code that wires renderers
+// into an engine, without reading real filesystems.
+//
+// Example:
+//
+//	fake := yamlfake.New(pod, service)
+//	e, _ := engine.New(engine.WithRenderer(fake))
+//	objects, _ := e.Render(ctx)
+//	calls := fake.Calls()
+package yamlfake
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/k8s-manifest-kit/engine/pkg/types"
+	"github.com/k8s-manifest-kit/pkg/util/k8s"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+)
+
+var _ types.Renderer = (*Renderer)(nil)
+
+// Result is a programmed outcome of a Process call.
+type Result struct {
+	// Objects are returned by the call (as deep copies).
+	Objects []unstructured.Unstructured
+
+	// Err is returned by the call. Objects are ignored when set.
+	Err error
+}
+
+// Call records a single Process invocation.
+type Call struct {
+	// Values are the render-time values passed to Process.
+	Values map[string]any
+}
+
+// Renderer is a fake implementation of types.Renderer reporting the YAML renderer type.
+// Programmed results are returned in order, and the last one is repeated once all have been
+// consumed. It is safe for concurrent use.
+type Renderer struct {
+	mu      sync.Mutex
+	results []Result
+	next    int
+	calls   []Call
+
+	// replaceable marks the default result of New, replaced when results are programmed.
+	replaceable bool
+}
+
+// New creates a fake renderer that returns the given objects from every Process call.
+func New(objects ...unstructured.Unstructured) *Renderer {
+	return &Renderer{
+		results:     []Result{{Objects: objects}},
+		replaceable: true,
+	}
+}
+
+// NewWithResults creates a fake renderer that returns the given results from successive
+// Process calls, repeating the last one. Without results, Process returns no objects.
+func NewWithResults(results ...Result) *Renderer {
+	return &Renderer{
+		results: slices.Clone(results),
+	}
+}
+
+// Return appends a result returning the given objects and returns the renderer for chaining.
+// Results programmed by New are replaced on the first call to Return or ReturnError.
+func (r *Renderer) Return(objects ...unstructured.Unstructured) *Renderer {
+	return r.program(Result{Objects: objects})
+}
+
+// ReturnError appends a result returning err and returns the renderer for chaining.
+// Results programmed by New are replaced on the first call to Return or ReturnError.
+func (r *Renderer) ReturnError(err error) *Renderer {
+	return r.program(Result{Err: err})
+}
+
+// program appends a result, replacing the default result of New.
+func (r *Renderer) program(result Result) *Renderer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.replaceable {
+		r.results = nil
+		r.replaceable = false
+	}
+
+	r.results = append(r.results, result)
+
+	return r
+}
+
+// Process records the call and returns the next programmed result.
+func (r *Renderer) Process(_ context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Call{Values: maps.Clone(values)})
+
+	if len(r.results) == 0 {
+		return []unstructured.Unstructured{}, nil
+	}
+
+	result := r.results[min(r.next, len(r.results)-1)]
+	r.next++
+
+	if result.Err != nil {
+		return nil, result.Err
+	}
+
+	return k8s.DeepCloneUnstructuredSlice(result.Objects), nil
+}
+
+// Name returns the renderer type identifier of the YAML renderer.
+func (r *Renderer) Name() string {
+	return yaml.RendererType
+}
+
+// Calls returns the Process calls recorded so far.
+func (r *Renderer) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+// Reset clears the recorded calls and restarts the programmed results from the first one.
+func (r *Renderer) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+	r.next = 0
+}
@@ -0,0 +1,91 @@
+package yamlfake_test
+
+import (
+	"errors"
+	"testing"
+
+	engine "github.com/k8s-manifest-kit/engine/pkg"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/k8s-manifest-kit/renderer-yaml/pkg/yamlfake"
+
+	. "github.com/onsi/gomega"
+)
+
+var errRender = errors.New("render failed")
+
+func configMap(name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName(name)
+
+	return obj
+}
+
+func TestRenderer(t *testing.T) {
+	t.Run("should return the programmed objects", func(t *testing.T) {
+		g := NewWithT(t)
+		fake := yamlfake.New(configMap("a"), configMap("b"))
+
+		objects, err := fake.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(fake.Name()).To(Equal("yaml"))
+	})
+
+	t.Run("should return copies of the programmed objects", func(t *testing.T) {
+		g := NewWithT(t)
+		fake := yamlfake.New(configMap("a"))
+
+		objects, err := fake.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		objects[0].SetName("changed")
+
+		objects, err = fake.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects[0].GetName()).To(Equal("a"))
+	})
+
+	t.Run("should return results in order and repeat the last one", func(t *testing.T) {
+		g := NewWithT(t)
+		fake := yamlfake.New().ReturnError(errRender).Return(configMap("a"))
+
+		_, err := fake.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(errRender))
+
+		for range 2 {
+			objects, err := fake.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+		}
+	})
+
+	t.Run("should record calls", func(t *testing.T) {
+		g := NewWithT(t)
+		fake := yamlfake.New()
+
+		_, _ = fake.Process(t.Context(), map[string]any{"env": "prod"})
+		_, _ = fake.Process(t.Context(), nil)
+
+		g.Expect(fake.Calls()).To(HaveLen(2))
+		g.Expect(fake.Calls()[0].Values).To(HaveKeyWithValue("env", "prod"))
+
+		fake.Reset()
+		g.Expect(fake.Calls()).To(BeEmpty())
+	})
+
+	t.Run("should plug into an engine", func(t *testing.T) {
+		g := NewWithT(t)
+		fake := yamlfake.New(configMap("a"))
+
+		e, err := engine.New(engine.WithRenderer(fake))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(fake.Calls()).To(HaveLen(1))
+	})
+}
@@ -0,0 +1,198 @@
+package yamltest
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+)
+
+// conformanceCases are YAML streams covering the decoding behavior renderer backends must match.
+var conformanceCases = map[string]string{
+	"multiple documents": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`,
+	"empty and untyped documents": `
+---
+# comment only
+---
+null
+---
+{}
+---
+metadata:
+  name: no-kind
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+`,
+	"scalars": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scalars
+data:
+  quoted: "80"
+  single: '0x10'
+  empty: ""
+spec:
+  integer: 80
+  negative: -3
+  float: 0.5
+  exponent: 1e3
+  boolean: true
+  nothing: ~
+  timestamp: 2001-12-14t21:59:43.10-05:00
+  date: 2001-12-14
+  quantity: 500m
+`,
+	"nested collections": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        ports:
+        - containerPort: 80
+        args: [--port, "80", --verbose]
+        env: []
+      volumes: {}
+`,
+	"block scalars": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scripts
+data:
+  literal: |
+    echo hello
+    echo world
+  folded: >
+    one
+    two
+  stripped: |-
+    no newline
+  kept: |+
+    trailing
+
+`,
+	"anchors and merge keys": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: anchors
+  labels: &labels
+    app: web
+  annotations:
+    <<: *labels
+    team: platform
+data:
+  copy: &value shared
+  alias: *value
+`,
+	"flow style and json": `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "json"}, "data": {"key": "value"}}
+---
+{apiVersion: v1, kind: Secret, metadata: {name: flow}, stringData: {token: abc}}
+`,
+	"unicode and special keys": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unicode
+  annotations:
+    example.com/description: "café \U0001F600"
+    "key with spaces": value
+data:
+  "1": numeric key
+  multi: "line\nbreak"
+`,
+}
+
+// DecoderConformance checks that decoder produces the same objects as yaml.YAMLv3Decoder for a
+// corpus of YAML streams (multiple, empty and untyped documents, scalar types, block scalars,
+// anchors and merge keys, flow style and JSON), reporting a diff for each mismatch. Use it to
+// verify decoders passed to yaml.WithDecoder:
+//
+//	func TestDecoder(t *testing.T) {
+//		yamltest.DecoderConformance(t, myDecoder{})
+//	}
+func DecoderConformance(t testing.TB, decoder yaml.Decoder) {
+	t.Helper()
+
+	for _, name := range slices.Sorted(maps.Keys(conformanceCases)) {
+		content := conformanceCases[name]
+
+		want, err := yaml.YAMLv3Decoder{}.Decode([]byte(content))
+		if err != nil {
+			t.Fatalf("%s: reference decoder failed: %v", name, err)
+
+			return
+		}
+
+		got, err := decoder.Decode([]byte(content))
+		if err != nil {
+			t.Errorf("%s: decoder failed: %v", name, err)
+
+			continue
+		}
+
+		if reflect.DeepEqual(objectMaps(want), objectMaps(got)) {
+			continue
+		}
+
+		wantYAML, gotYAML := dump(t, want), dump(t, got)
+		if wantYAML == gotYAML {
+			t.Errorf("%s: decoded values differ from yaml.YAMLv3Decoder in their Go types (e.g. int instead of int64)", name)
+
+			continue
+		}
+
+		t.Errorf("%s: decoded objects differ from yaml.YAMLv3Decoder:\n%s", name, Diff(wantYAML, gotYAML))
+	}
+}
+
+// objectMaps returns the content of objects, so empty and nil results compare equal.
+func objectMaps(objects []unstructured.Unstructured) []map[string]any {
+	contents := make([]map[string]any, len(objects))
+	for i := range objects {
+		contents[i] = objects[i].Object
+	}
+
+	return contents
+}
+
+// dump writes objects as multi-document YAML.
+func dump(t testing.TB, objects []unstructured.Unstructured) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	if err := yaml.Write(objects, &buf); err != nil {
+		t.Fatalf("failed to write objects: %v", err)
+	}
+
+	return buf.String()
+}
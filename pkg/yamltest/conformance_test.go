@@ -0,0 +1,64 @@
+package yamltest_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+	"github.com/k8s-manifest-kit/renderer-yaml/pkg/yamltest"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecoderConformance(t *testing.T) {
+	t.Run("should accept the default decoder", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rec := &recordingTB{TB: t}
+		yamltest.DecoderConformance(rec, yaml.YAMLv3Decoder{})
+
+		g.Expect(rec.errors).To(BeEmpty())
+	})
+
+	t.Run("should report decoders producing different objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		lossy := yaml.DecoderFunc(func(content []byte) ([]unstructured.Unstructured, error) {
+			objects, err := yaml.YAMLv3Decoder{}.Decode(content)
+			for i := range objects {
+				objects[i].SetLabels(nil)
+			}
+
+			return objects, err
+		})
+
+		rec := &recordingTB{TB: t}
+		yamltest.DecoderConformance(rec, lossy)
+
+		g.Expect(rec.errors).To(ConsistOf(
+			And(ContainSubstring("anchors and merge keys"), ContainSubstring("-   labels:")),
+			ContainSubstring("nested collections"),
+		))
+	})
+
+	t.Run("should report values of different Go types", func(t *testing.T) {
+		g := NewWithT(t)
+
+		narrowing := yaml.DecoderFunc(func(content []byte) ([]unstructured.Unstructured, error) {
+			objects, err := yaml.YAMLv3Decoder{}.Decode(content)
+			for i := range objects {
+				if replicas, ok, _ := unstructured.NestedInt64(objects[i].Object, "spec", "replicas"); ok {
+					objects[i].Object["spec"].(map[string]any)["replicas"] = int(replicas)
+				}
+			}
+
+			return objects, err
+		})
+
+		rec := &recordingTB{TB: t}
+		yamltest.DecoderConformance(rec, narrowing)
+
+		g.Expect(rec.errors).To(ConsistOf(ContainSubstring("nested collections: decoded values differ")))
+	})
+}
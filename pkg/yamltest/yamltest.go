@@ -0,0 +1,157 @@
+// Package yamltest provides golden-file helpers for testing manifest pipelines built on the
+// YAML renderer.
+//
+// Rendered objects are written as sorted multi-document YAML and compared against a golden file.
+// Run tests with -update (or UPDATE_GOLDEN=1) to rewrite golden files from the current output:
+//
+//	func TestManifests(t *testing.T) {
+//		yamltest.RenderGolden(t, os.DirFS("manifests"), "**/*.yaml", "testdata/manifests.golden.yaml")
+//	}
+//
+//	go test ./... -update
+package yamltest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	yaml "github.com/k8s-manifest-kit/renderer-yaml/pkg"
+)
+
+// UpdateEnv is the environment variable that enables updating golden files when set to a
+// non-empty value, as an alternative to the -update flag.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// update is the -update flag. It is only registered if no other package defined it first,
+// so test binaries that already declare their own -update flag keep working (the flag is then
+// read by name).
+var update = func() *bool {
+	if flag.Lookup("update") != nil {
+		return nil
+	}
+
+	return flag.Bool("update", false, "update golden files")
+}()
+
+// Updating reports whether golden files are rewritten instead of compared.
+func Updating() bool {
+	if os.Getenv(UpdateEnv) != "" {
+		return true
+	}
+
+	if update != nil {
+		return *update
+	}
+
+	f := flag.Lookup("update")
+
+	return f != nil && f.Value.String() == "true"
+}
+
+// Render renders the files of fsys matching pattern and returns the objects, failing the test
+// if the renderer cannot be created or rendering fails.
+func Render(
+	t testing.TB,
+	fsys fs.FS,
+	pattern string,
+	opts ...yaml.RendererOption,
+) []unstructured.Unstructured {
+	t.Helper()
+
+	renderer, err := yaml.New([]yaml.Source{{FS: fsys, Path: pattern}}, opts...)
+	if err != nil {
+		t.Fatalf("failed to create YAML renderer: %v", err)
+
+		return nil
+	}
+
+	objects, err := renderer.Process(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to render %s: %v", pattern, err)
+
+		return nil
+	}
+
+	return objects
+}
+
+// RenderGolden renders the files of fsys matching pattern and compares the result against
+// the golden file.
+func RenderGolden(
+	t testing.TB,
+	fsys fs.FS,
+	pattern string,
+	golden string,
+	opts ...yaml.RendererOption,
+) {
+	t.Helper()
+
+	Golden(t, Render(t, fsys, pattern, opts...), golden)
+}
+
+// Golden writes objects as sorted multi-document YAML and compares the output against the
+// golden file, reporting a line diff on mismatch. In update mode the golden file is written
+// instead, creating parent directories as needed.
+func Golden(
+	t testing.TB,
+	objects []unstructured.Unstructured,
+	golden string,
+	opts ...yaml.WriteOption,
+) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	opts = append([]yaml.WriteOption{yaml.WithSortedOutput(true)}, opts...)
+	if err := yaml.Write(objects, &buf, opts...); err != nil {
+		t.Fatalf("failed to write objects: %v", err)
+
+		return
+	}
+
+	if Updating() {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+
+			return
+		}
+
+		if err := os.WriteFile(golden, buf.Bytes(), 0o600); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+
+			return
+		}
+
+		t.Logf("updated golden file %s", golden)
+
+		return
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+
+		return
+	}
+
+	if !bytes.Equal(want, buf.Bytes()) {
+		t.Errorf(
+			"output does not match golden file %s (run with -update to accept it):\n%s",
+			golden,
+			Diff(string(want), buf.String()),
+		)
+	}
+}
+
+// Diff returns a line diff turning want into got: removed lines are prefixed with "-",
+// added lines with "+" and unchanged lines with a space.
+func Diff(want string, got string) string {
+	return yaml.LineDiff(want, got)
+}
@@ -0,0 +1,103 @@
+package yamltest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k8s-manifest-kit/renderer-yaml/pkg/yamltest"
+
+	. "github.com/onsi/gomega"
+)
+
+const podYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-pod
+spec:
+  containers:
+  - name: nginx
+    image: nginx:latest
+`
+
+// recordingTB captures failures instead of failing the enclosing test.
+type recordingTB struct {
+	testing.TB
+
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Logf(string, ...any) {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRenderGolden(t *testing.T) {
+	testFS := fstest.MapFS{
+		"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+	}
+
+	t.Run("should create and then match a golden file", func(t *testing.T) {
+		g := NewWithT(t)
+		golden := filepath.Join(t.TempDir(), "testdata", "pod.golden.yaml")
+
+		t.Setenv(yamltest.UpdateEnv, "1")
+		yamltest.RenderGolden(t, testFS, "*.yaml", golden)
+
+		content, err := os.ReadFile(golden)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(ContainSubstring("name: test-pod"))
+
+		t.Setenv(yamltest.UpdateEnv, "")
+		yamltest.RenderGolden(t, testFS, "*.yaml", golden)
+	})
+
+	t.Run("should report a diff on mismatch", func(t *testing.T) {
+		g := NewWithT(t)
+		golden := filepath.Join(t.TempDir(), "pod.golden.yaml")
+
+		t.Setenv(yamltest.UpdateEnv, "1")
+		yamltest.RenderGolden(t, testFS, "*.yaml", golden)
+		t.Setenv(yamltest.UpdateEnv, "")
+
+		changed := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML + "  restartPolicy: Never\n")},
+		}
+
+		rec := &recordingTB{TB: t}
+		yamltest.RenderGolden(rec, changed, "*.yaml", golden)
+
+		g.Expect(rec.errors).To(HaveLen(1))
+		g.Expect(rec.errors[0]).To(ContainSubstring("does not match golden file"))
+		g.Expect(rec.errors[0]).To(ContainSubstring("+   restartPolicy: Never"))
+	})
+
+	t.Run("should fail when the golden file is missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rec := &recordingTB{TB: t}
+		yamltest.RenderGolden(rec, testFS, "*.yaml", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		g.Expect(rec.errors).To(ConsistOf(ContainSubstring("run with -update to create it")))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("should mark added and removed lines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diff := yamltest.Diff("a\nb\nc", "a\nx\nc")
+
+		g.Expect(diff).To(Equal("  a\n- b\n+ x\n  c\n"))
+	})
+}